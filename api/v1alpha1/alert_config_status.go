@@ -0,0 +1,86 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AlertConfigConditionReady is true when the alert configuration is valid and can be used to send alerts.
+	AlertConfigConditionReady = "Ready"
+
+	// AlertConfigConditionTemplateValid is true when all the template bodies can be parsed and rendered.
+	AlertConfigConditionTemplateValid = "TemplateValid"
+
+	// AlertConfigConditionCredentialsResolved is true when all the secrets and configmaps referenced are found.
+	AlertConfigConditionCredentialsResolved = "CredentialsResolved"
+)
+
+const (
+	AlertConfigReasonValid               = "Valid"
+	AlertConfigReasonInvalid             = "Invalid"
+	AlertConfigReasonTemplateInvalid     = "TemplateInvalid"
+	AlertConfigReasonCredentialsNotFound = "CredentialsNotFound"
+	AlertConfigReasonResolved            = "Resolved"
+)
+
+// SetCondition adds or updates a condition in the AlertConfig status.
+func (a *AlertConfig) SetCondition(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&a.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: a.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// IsReady returns true if the Ready condition of the AlertConfig is true.
+func (a *AlertConfig) IsReady() bool {
+	return meta.IsStatusConditionTrue(a.Status.Conditions, AlertConfigConditionReady)
+}
+
+// SetStatusSent records a successfully sent alert.
+func (a *AlertConfig) SetStatusSent() {
+	now := metav1.Now()
+	a.Status.LastSent = &now
+	a.Status.LastError = ""
+}
+
+// SetStatusError records the error of a failed alert.
+func (a *AlertConfig) SetStatusError(err error) {
+	a.Status.LastError = err.Error()
+}
+
+// AddPending queues an update for the next digest.
+// A pending update of the same image and action is replaced by the new one.
+func (a *AlertConfig) AddPending(entry AlertDigestEntry) {
+	for i, p := range a.Status.Pending {
+		if p.Name == entry.Name && p.Action == entry.Action {
+			a.Status.Pending[i] = entry
+			return
+		}
+	}
+
+	a.Status.Pending = append(a.Status.Pending, entry)
+}
+
+// RemovePending removes the updates sent in a digest.
+// An update queued again since the digest has been built is kept.
+func (a *AlertConfig) RemovePending(sent []AlertDigestEntry) {
+	pending := make([]AlertDigestEntry, 0, len(a.Status.Pending))
+	for _, p := range a.Status.Pending {
+		found := false
+		for _, s := range sent {
+			if p.Name == s.Name && p.Action == s.Action && p.NewTag == s.NewTag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			pending = append(pending, p)
+		}
+	}
+
+	a.Status.Pending = pending
+}
@@ -11,6 +11,57 @@ type (
 
 		// +kubebuilder:validation:Optional
 		Email *AlertEmailSpec `json:"email,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		Slack *AlertSlackSpec `json:"slack,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		Teams *AlertTeamsSpec `json:"teams,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		Mattermost *AlertMattermostSpec `json:"mattermost,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		Telegram *AlertTelegramSpec `json:"telegram,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		Webhook *AlertWebhookSpec `json:"webhook,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Digest batches the alerts in one periodic message instead of sending them immediately.
+		Digest *AlertDigestSpec `json:"digest,omitempty"`
+	}
+
+	// AlertDigestSpec defines the digest mode of the AlertConfig
+	AlertDigestSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:Schedule is the crontab (6 fields with seconds) used to send the digest.
+		Schedule string `json:"schedule"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:TemplateBody is the template of the digest message. The pending updates are available in .Updates.
+		TemplateBody string `json:"templateBody,omitempty"`
+	}
+
+	// AlertDigestEntry is an update waiting to be sent in the next digest
+	AlertDigestEntry struct {
+		// Action is the alert action that queued the update.
+		Action string `json:"action"`
+
+		// Name is the name of the Image resource.
+		Name string `json:"name"`
+
+		// ImageName is the image of the Image resource.
+		ImageName string `json:"imageName"`
+
+		// ActualTag is the tag used when the update has been queued.
+		ActualTag string `json:"actualTag,omitempty"`
+
+		// NewTag is the new tag available.
+		NewTag string `json:"newTag"`
+
+		// Time is the time when the update has been queued.
+		Time metav1.Time `json:"time"`
 	}
 
 	// AlertEmailSpec defines the desired state of AlertEmail
@@ -93,12 +144,175 @@ type (
 		TemplateBody string `json:"templateBody,omitempty"`
 	}
 
-	// AlertDiscordStatus defines the observed state of AlertDiscord
-	AlertConfigStatus struct{}
+	// AlertSlackSpec defines the desired state of AlertSlack
+	AlertSlackSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:WebhookURL is the Slack incoming webhook URL (https://hooks.slack.com/services/...).
+		WebhookURL ValueOrValueFrom `json:"webhookURL"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:BotName overrides the name displayed for the message.
+		BotName string `json:"botName,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Icon is an emoji or an URL used as icon for the message.
+		Icon string `json:"icon,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Color of the message left-hand border.
+		Color string `json:"color,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Title prepended above the message.
+		Title string `json:"title,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Schemaless
+		// +kubebuilder:validation:Type=string
+		TemplateBody string `json:"templateBody,omitempty"`
+	}
+
+	// AlertTeamsSpec defines the desired state of AlertTeams
+	AlertTeamsSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:WebhookURL is the Microsoft Teams incoming webhook URL.
+		WebhookURL ValueOrValueFrom `json:"webhookURL"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Title of the message card.
+		Title string `json:"title,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Color of the message card theme.
+		Color string `json:"color,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Schemaless
+		// +kubebuilder:validation:Type=string
+		TemplateBody string `json:"templateBody,omitempty"`
+	}
+
+	// AlertMattermostSpec defines the desired state of AlertMattermost
+	AlertMattermostSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:WebhookURL is the Mattermost incoming webhook URL (https://mattermost.example.com/hooks/xxx).
+		WebhookURL ValueOrValueFrom `json:"webhookURL"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Channel overrides the default channel of the webhook.
+		Channel string `json:"channel,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Username overrides the default username of the webhook.
+		Username string `json:"username,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Icon is an emoji or an URL used as icon for the message.
+		Icon string `json:"icon,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Schemaless
+		// +kubebuilder:validation:Type=string
+		TemplateBody string `json:"templateBody,omitempty"`
+	}
+
+	// AlertTelegramSpec defines the desired state of AlertTelegram
+	AlertTelegramSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:Token is the Telegram bot token (123456:ABC-DEF).
+		Token ValueOrValueFrom `json:"token"`
+
+		// +kubebuilder:validation:Required
+		// +kubebuilder:validation:MinItems=1
+		// +kubebuilder:description:List of chat IDs or channel names (@channel-name).
+		Chats []string `json:"chats"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:How the text message should be parsed.
+		// +kubebuilder:default:None
+		// +kubebuilder:validation:Enum=None;Markdown;HTML;MarkdownV2
+		ParseMode string `json:"parseMode,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Send the message silently.
+		// +kubebuilder:default:false
+		DisableNotification bool `json:"disableNotification,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Schemaless
+		// +kubebuilder:validation:Type=string
+		TemplateBody string `json:"templateBody,omitempty"`
+	}
+
+	// AlertWebhookSpec defines the desired state of AlertWebhook
+	AlertWebhookSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:URL is the endpoint that receives the alert.
+		URL ValueOrValueFrom `json:"url"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:HTTP method used to send the alert.
+		// +kubebuilder:default:POST
+		// +kubebuilder:validation:Enum=POST;PUT;PATCH
+		Method string `json:"method,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Value of the Content-Type header.
+		// +kubebuilder:default:application/json
+		ContentType string `json:"contentType,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Extra headers sent with the request. Values can be read from secrets or configmaps.
+		Headers []AlertWebhookHeader `json:"headers,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Schemaless
+		// +kubebuilder:validation:Type=string
+		TemplateBody string `json:"templateBody,omitempty"`
+	}
+
+	// AlertWebhookHeader defines a header sent by AlertWebhook
+	AlertWebhookHeader struct {
+		// +kubebuilder:validation:Required
+		Name string `json:"name"`
+
+		// +kubebuilder:validation:Required
+		Value ValueOrValueFrom `json:"value"`
+	}
+
+	// AlertConfigStatus defines the observed state of AlertConfig
+	AlertConfigStatus struct {
+		// ObservedGeneration is the generation of the AlertConfig validated by the operator.
+		// +optional
+		ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+		// Conditions represent the latest available observations of the AlertConfig.
+		// Known condition types are Ready, TemplateValid and CredentialsResolved.
+		// +optional
+		// +listType=map
+		// +listMapKey=type
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+		// LastSent is the last time an alert was successfully sent with this configuration.
+		// +optional
+		LastSent *metav1.Time `json:"lastSent,omitempty"`
+
+		// LastError is the error returned by the last failed alert.
+		// It is cleared when an alert is successfully sent.
+		// +optional
+		LastError string `json:"lastError,omitempty"`
+
+		// Pending is the list of updates waiting to be sent in the next digest.
+		// +optional
+		Pending []AlertDigestEntry `json:"pending,omitempty"`
+	}
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Last-Sent",type=date,JSONPath=`.status.lastSent`
 
 type AlertConfig struct {
 	metav1.TypeMeta   `json:",inline"`
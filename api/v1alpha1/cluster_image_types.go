@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LabelClusterImage is the label set on the Images created from a ClusterImage to list them.
+// Its value is the name of the ClusterImage.
+const LabelClusterImage = "kimup.cloudavenue.io/cluster-image"
+
+type (
+	// ClusterImageSpec defines the desired state of ClusterImage
+	ClusterImageSpec struct {
+		// NamespaceSelector selects the namespaces where the image is defined.
+		// All the namespaces are selected if it is empty.
+		// +kubebuilder:validation:Optional
+		NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+		// ImageSpec is the definition of the Image created in the selected namespaces.
+		ImageSpec `json:",inline"`
+	}
+
+	// ClusterImageStatus defines the observed state of ClusterImage
+	ClusterImageStatus struct {
+		// ObservedGeneration is the generation of the ClusterImage reconciled.
+		// +optional
+		ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+		// Namespaces is the list of the namespaces where the Image is managed by the ClusterImage.
+		// +optional
+		Namespaces []string `json:"namespaces,omitempty"`
+
+		// Overridden is the list of the selected namespaces defining their own Image for the same image.
+		// +optional
+		Overridden []string `json:"overridden,omitempty"`
+	}
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// ClusterImage is the Schema for the clusterimages API
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.image`
+// +kubebuilder:printcolumn:name="Namespaces",type=string,JSONPath=`.status.namespaces`
+// +kubebuilder:printcolumn:name="Overridden",type=string,JSONPath=`.status.overridden`,priority=1
+type ClusterImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterImageSpec   `json:"spec,omitempty"`
+	Status ClusterImageStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterImageList contains a list of ClusterImage
+type ClusterImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterImage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterImage{}, &ClusterImageList{})
+}
+
+// IsManagedByClusterImage returns true if the image has been created from a ClusterImage.
+// The ownership is decided by the controller reference, the LabelClusterImage label
+// can be set or removed by the users and is only used to list the Images.
+func (i *Image) IsManagedByClusterImage() bool {
+	owner := metav1.GetControllerOf(i)
+	return owner != nil && owner.Kind == "ClusterImage" && owner.APIVersion == GroupVersion.String()
+}
@@ -0,0 +1,130 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type (
+	// GitConfigSpec defines the git repository and the file updated by the git actions.
+	GitConfigSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:validation:Pattern=`^https?://`
+		// +kubebuilder:description:URL is the HTTP(S) URL of the git repository.
+		URL string `json:"url"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:default:=main
+		// +kubebuilder:description:Branch is the branch updated by the git-commit action and the base branch of the pull requests.
+		Branch string `json:"branch,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Auth is the HTTP basic authentication used to clone and push.
+		Auth *GitAuthSpec `json:"auth,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		Author GitAuthorSpec `json:"author,omitempty"`
+
+		// +kubebuilder:validation:Required
+		File GitFileSpec `json:"file"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:CommitMessage is the template of the commit message.
+		CommitMessage string `json:"commitMessage,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:PullRequest configures the pull requests opened by the git-pull-request action.
+		PullRequest *GitPullRequestSpec `json:"pullRequest,omitempty"`
+	}
+
+	// GitAuthSpec defines the HTTP basic authentication of the git repository
+	GitAuthSpec struct {
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Username is the username. Most of the providers accept any value with a token.
+		Username ValueOrValueFrom `json:"username,omitempty"`
+
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:Password is the password or the access token.
+		Password ValueOrValueFrom `json:"password"`
+	}
+
+	// GitAuthorSpec defines the author of the commits
+	GitAuthorSpec struct {
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:default:=kimup
+		Name string `json:"name,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:default:=kimup@cloudavenue.io
+		Email string `json:"email,omitempty"`
+	}
+
+	// GitFileSpec defines the file updated with the new tag
+	GitFileSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description:Path is the path of the file in the repository.
+		Path string `json:"path"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Enum=kustomize;helm;yaml
+		// +kubebuilder:default:=kustomize
+		// +kubebuilder:description:Format is the format of the file. kustomize updates the images field, helm and yaml update the value at Key.
+		Format string `json:"format,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Key is the dot separated path of the tag in the file (helm and yaml formats). Default is image.tag for the helm format.
+		Key string `json:"key,omitempty"`
+	}
+
+	// GitPullRequestSpec defines the pull requests opened by the git-pull-request action
+	GitPullRequestSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:validation:Enum=github;gitlab;gitea
+		Provider string `json:"provider"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:APIURL is the URL of the provider API. Default is https://api.github.com for github and https://gitlab.com/api/v4 for gitlab. Required for gitea.
+		APIURL string `json:"apiURL,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Token is the token used to call the provider API. Default is the password of the auth.
+		Token ValueOrValueFrom `json:"token,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description:Title is the template of the pull request title. Default is the commit message.
+		Title string `json:"title,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:default:=kimup/
+		// +kubebuilder:description:BranchPrefix is the prefix of the branch created for the pull request.
+		BranchPrefix string `json:"branchPrefix,omitempty"`
+	}
+
+	// GitConfigStatus defines the observed state of GitConfig
+	GitConfigStatus struct{}
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+// +kubebuilder:printcolumn:name="Branch",type=string,JSONPath=`.spec.branch`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.spec.file.path`
+
+type GitConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitConfigSpec   `json:"spec,omitempty"`
+	Status GitConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type GitConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitConfig{}, &GitConfigList{})
+}
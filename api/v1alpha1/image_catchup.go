@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+// CatchUpPolicy defines how the crontab runs missed while kimup was down are handled.
+type CatchUpPolicy string
+
+const (
+	// CatchUpRunOnce refreshes the image once if at least one run has been missed.
+	CatchUpRunOnce CatchUpPolicy = "runOnce"
+
+	// CatchUpSkip ignores the missed runs.
+	CatchUpSkip CatchUpPolicy = "skip"
+
+	// CatchUpRunAll refreshes the image for each missed run, up to MaxCatchUpRuns.
+	CatchUpRunAll CatchUpPolicy = "runAll"
+
+	// MaxCatchUpRuns is the maximum number of missed runs counted, and so of refreshes of the runAll policy.
+	MaxCatchUpRuns = 10
+)
+
+// CatchUpRuns returns the number of refreshes to execute for the missed runs according to the policy.
+func (p CatchUpPolicy) CatchUpRuns(missed int) int {
+	switch {
+	case missed == 0:
+		return 0
+	case p == CatchUpSkip:
+		return 0
+	case p == CatchUpRunAll:
+		return min(missed, MaxCatchUpRuns)
+	default:
+		return 1
+	}
+}
+
+// LastSyncTime returns the time of the last sync of the image.
+// The creation time is returned if the image has never been synchronized.
+func (i *Image) LastSyncTime() time.Time {
+	if t, err := time.Parse(time.RFC3339, i.Status.Time); err == nil {
+		return t
+	}
+
+	return i.CreationTimestamp.Time
+}
+
+// LastCrontabRunTime returns the time of the last run of the crontab trigger.
+// The time of the last sync is returned if no run of the crontab has been recorded.
+func (i *Image) LastCrontabRunTime(crontab string) time.Time {
+	for _, r := range i.Status.CrontabRuns {
+		if r.Crontab == crontab {
+			return r.Time.Time
+		}
+	}
+
+	return i.LastSyncTime()
+}
+
+// SetCrontabRun records the time of the run of the crontab trigger.
+// The runs of the crontabs removed from the triggers are dropped.
+func (i *Image) SetCrontabRun(crontab string, t time.Time) {
+	i.Status.CrontabRuns = slices.DeleteFunc(i.Status.CrontabRuns, func(r ImageStatusCrontabRun) bool {
+		return r.Crontab == crontab || !slices.ContainsFunc(i.Spec.Triggers, func(trigger ImageTrigger) bool {
+			return trigger.Type == triggers.Crontab && trigger.Value == r.Crontab
+		})
+	})
+
+	i.Status.CrontabRuns = append(i.Status.CrontabRuns, ImageStatusCrontabRun{
+		Crontab: crontab,
+		Time:    metav1.NewTime(t),
+	})
+}
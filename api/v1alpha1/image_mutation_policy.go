@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	"github.com/shipengqi/vc"
+)
+
+// MutationPolicy defines which container tags are rewritten by the admission controller.
+type MutationPolicy string
+
+const (
+	// MutationPolicyAlways rewrites the tag of the container whatever the tag of the manifest.
+	MutationPolicyAlways MutationPolicy = "always"
+
+	// MutationPolicyOnlyIfOlder rewrites the tag of the container only if the tag of the manifest
+	// is older than the tag of the image. Tags that can not be compared as semver or calver are kept.
+	MutationPolicyOnlyIfOlder MutationPolicy = "onlyIfOlder"
+
+	// MutationPolicyOnlyIfBaseTag rewrites the tag of the container only if the manifest
+	// has no tag or uses the base tag of the image.
+	MutationPolicyOnlyIfBaseTag MutationPolicy = "onlyIfBaseTag"
+)
+
+// ShouldMutate returns true if the tag of the manifest has to be replaced by the tag of the image
+// according to the mutation policy. An empty tag is always replaced.
+func (i *Image) ShouldMutate(tag string) bool {
+	if tag == "" {
+		return true
+	}
+
+	switch i.Spec.MutationPolicy {
+	case MutationPolicyOnlyIfBaseTag:
+		return tag == i.Spec.BaseTag
+	case MutationPolicyOnlyIfOlder:
+		return isOlderTag(tag, i.GetTag())
+	default:
+		return true
+	}
+}
+
+// isOlderTag returns true if the tag is older than the reference tag.
+// The tags are compared as semver, then as calver. It returns false if they can not be compared.
+func isOlderTag(tag, reference string) bool {
+	if v, err := vc.NewSemverStr(tag); err == nil {
+		if r, err := vc.NewSemverStr(reference); err == nil {
+			return v.Lt(r)
+		}
+	}
+
+	if v, err := vc.NewCalVerStr(tag); err == nil {
+		if r, err := vc.NewCalVerStr(reference); err == nil {
+			return v.Lt(r)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,277 @@
+package v1alpha1
+
+import (
+	"github.com/shipengqi/vc"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type (
+	// ImageStatusRule is the result of a rule evaluated by the last sync.
+	ImageStatusRule struct {
+		// Name is the name of the rule.
+		Name string `json:"name"`
+
+		// Type is the type of the rule.
+		Type string `json:"type"`
+
+		// Result is the result of the evaluation.
+		// +kubebuilder:validation:Enum=Matched;NotMatched;Error
+		Result ImageRuleResult `json:"result"`
+
+		// NewTag is the tag found by the rule when it matched.
+		// +optional
+		NewTag string `json:"newTag,omitempty"`
+
+		// Message is the error of the evaluation.
+		// +optional
+		Message string `json:"message,omitempty"`
+
+		// Time is the time of the evaluation.
+		Time metav1.Time `json:"time"`
+
+		// Actions are the results of the actions of the rule when it matched.
+		// +optional
+		Actions []ImageStatusAction `json:"actions,omitempty"`
+	}
+
+	ImageRuleResult string
+
+	// ImageStatusAction is the result of an action executed by the last sync.
+	ImageStatusAction struct {
+		// Type is the type of the action.
+		Type string `json:"type"`
+
+		// Result is the result of the action.
+		// Skipped is used for the alerts already sent for the tag.
+		// +kubebuilder:validation:Enum=Succeeded;Failed;Skipped
+		Result ImageActionResult `json:"result"`
+
+		// Message is the error of the action.
+		// +optional
+		Message string `json:"message,omitempty"`
+
+		// Duration is the duration of the execution of the action.
+		// +optional
+		Duration metav1.Duration `json:"duration,omitempty"`
+
+		// Time is the time when the action has been executed.
+		Time metav1.Time `json:"time"`
+	}
+
+	ImageActionResult string
+
+	// ImageStatusHistory is a tag applied to the image.
+	ImageStatusHistory struct {
+		// Tag is the tag applied.
+		Tag string `json:"tag"`
+
+		// Time is the time when the tag has been applied.
+		Time metav1.Time `json:"time"`
+	}
+)
+
+// MaxHistory is the number of tags kept in the history of the image.
+const MaxHistory = 10
+
+const (
+	ImageRuleResultMatched    ImageRuleResult = "Matched"
+	ImageRuleResultNotMatched ImageRuleResult = "NotMatched"
+	ImageRuleResultError      ImageRuleResult = "Error"
+)
+
+const (
+	ImageActionResultSucceeded ImageActionResult = "Succeeded"
+	ImageActionResultFailed    ImageActionResult = "Failed"
+	ImageActionResultSkipped   ImageActionResult = "Skipped"
+)
+
+// Conditions of the image.
+const (
+	// ImageConditionTagsFetched is true when the tags have been fetched from the registry.
+	ImageConditionTagsFetched = "TagsFetched"
+
+	// ImageConditionRulesEvaluated is true when all the rules have been evaluated without error.
+	ImageConditionRulesEvaluated = "RulesEvaluated"
+
+	// ImageConditionActionsSucceeded is true when all the actions executed have succeeded.
+	ImageConditionActionsSucceeded = "ActionsSucceeded"
+
+	// ImageConditionReady is true when the three other conditions are true.
+	ImageConditionReady = "Ready"
+)
+
+// Reasons of the conditions of the image. The failure reasons are the ImageStatusLastSync values.
+const (
+	ImageReasonSucceeded      = "Succeeded"
+	ImageReasonScheduled      = "Scheduled"
+	ImageReasonTagsNotFetched = "TagsNotFetched"
+)
+
+// SetCondition sets the condition of the image for its current generation.
+func (i *Image) SetCondition(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&i.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: i.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// GetCondition returns the condition of the image or nil if it is not set.
+func (i *Image) GetCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(i.Status.Conditions, conditionType)
+}
+
+// IsReady returns true if the Ready condition of the image is true.
+func (i *Image) IsReady() bool {
+	return meta.IsStatusConditionTrue(i.Status.Conditions, ImageConditionReady)
+}
+
+// SetReadyCondition sets the Ready condition from the TagsFetched, RulesEvaluated
+// and ActionsSucceeded conditions. The reason of the first condition that is not true is used.
+func (i *Image) SetReadyCondition() {
+	for _, t := range []string{ImageConditionTagsFetched, ImageConditionRulesEvaluated, ImageConditionActionsSucceeded} {
+		c := i.GetCondition(t)
+		switch {
+		case c == nil:
+			i.SetCondition(ImageConditionReady, metav1.ConditionUnknown, ImageReasonScheduled, "")
+			return
+		case c.Status != metav1.ConditionTrue:
+			i.SetCondition(ImageConditionReady, metav1.ConditionFalse, c.Reason, c.Message)
+			return
+		}
+	}
+
+	i.SetCondition(ImageConditionReady, metav1.ConditionTrue, ImageReasonSucceeded, "")
+}
+
+// SetRuleStatus sets the result of the rule. The previous result of the rule is replaced.
+func (i *Image) SetRuleStatus(rule ImageStatusRule) {
+	for k, r := range i.Status.Rules {
+		if r.Name == rule.Name {
+			i.Status.Rules[k] = rule
+			return
+		}
+	}
+
+	i.Status.Rules = append(i.Status.Rules, rule)
+}
+
+// GetRuleStatus returns the result of the rule or nil if the rule has not been evaluated.
+func (i *Image) GetRuleStatus(name string) *ImageStatusRule {
+	for k := range i.Status.Rules {
+		if i.Status.Rules[k].Name == name {
+			return &i.Status.Rules[k]
+		}
+	}
+
+	return nil
+}
+
+// AddActionStatus adds the result of an action to the rule.
+func (r *ImageStatusRule) AddActionStatus(action ImageStatusAction) {
+	r.Actions = append(r.Actions, action)
+}
+
+// AddHistory records the tag applied to the image. The most recent tag is the first
+// of the history, which keeps the last MaxHistory tags.
+// Nothing is recorded if the tag is already the most recent one.
+func (i *Image) AddHistory(tag string) {
+	if len(i.Status.History) > 0 && i.Status.History[0].Tag == tag {
+		return
+	}
+
+	i.Status.History = append([]ImageStatusHistory{{Tag: tag, Time: metav1.Now()}}, i.Status.History...)
+	if len(i.Status.History) > MaxHistory {
+		i.Status.History = i.Status.History[:MaxHistory]
+	}
+}
+
+// SetStatusLatestTag sets the latest version among the tags.
+// The tags are compared as semver without the prereleases, then as calver.
+// The latest tag is empty if no tag can be compared.
+func (i *Image) SetStatusLatestTag(tags []string) {
+	i.Status.LatestTag = LatestTag(tags)
+}
+
+// SetStatusVersionsBehind sets the time when a version newer than the tag of the image has been found.
+// It returns the number of versions newer than the tag of the image.
+func (i *Image) SetStatusVersionsBehind(tags []string) int {
+	tag := i.Status.Tag
+	if tag == "" {
+		tag = i.Spec.BaseTag
+	}
+
+	behind := VersionsBehind(tag, tags)
+	switch {
+	case behind == 0:
+		i.Status.NewerTagSince = nil
+	case i.Status.NewerTagSince == nil:
+		now := metav1.Now()
+		i.Status.NewerTagSince = &now
+	}
+
+	return behind
+}
+
+// VersionsBehind returns the number of versions among the tags newer than the tag.
+// The tags are compared as semver without the prereleases if the tag is a semver, otherwise as calver.
+// It returns 0 if the tag can not be compared.
+func VersionsBehind(tag string, tags []string) (behind int) {
+	if current, err := vc.NewSemverStr(tag); err == nil {
+		for _, t := range tags {
+			v, err := vc.NewSemverStr(t)
+			if err == nil && v.Prerelease() == "" && v.Gt(current) {
+				behind++
+			}
+		}
+		return behind
+	}
+
+	if current, err := vc.NewCalVerStr(tag); err == nil {
+		for _, t := range tags {
+			v, err := vc.NewCalVerStr(t)
+			if err == nil && v.Gt(current) {
+				behind++
+			}
+		}
+	}
+
+	return behind
+}
+
+// LatestTag returns the latest version among the tags.
+// The tags are compared as semver without the prereleases, then as calver.
+func LatestTag(tags []string) string {
+	var latest *vc.Semver
+	for _, tag := range tags {
+		v, err := vc.NewSemverStr(tag)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		if latest == nil || v.Gt(latest) {
+			latest = v
+		}
+	}
+	if latest != nil {
+		return latest.Original()
+	}
+
+	var latestCalver *vc.CalVer
+	for _, tag := range tags {
+		v, err := vc.NewCalVerStr(tag)
+		if err != nil {
+			continue
+		}
+		if latestCalver == nil || v.Gt(latestCalver) {
+			latestCalver = v
+		}
+	}
+	if latestCalver != nil {
+		return latestCalver.Original()
+	}
+
+	return ""
+}
@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+// HasTrigger returns true if the image defines a trigger of the type.
+func (i *Image) HasTrigger(name triggers.Name) bool {
+	for _, trigger := range i.Spec.Triggers {
+		if trigger.Type == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Dependencies returns the names of the Images followed by the dependency triggers of the image.
+func (i *Image) Dependencies() []string {
+	dependencies := []string{}
+	for _, trigger := range i.Spec.Triggers {
+		if trigger.Type == triggers.Dependency && trigger.ImageRef != nil && trigger.ImageRef.Name != "" {
+			dependencies = append(dependencies, trigger.ImageRef.Name)
+		}
+	}
+
+	return dependencies
+}
@@ -51,6 +51,14 @@ type (
 		// +kubebuilder:example:="v1.2.0"
 		BaseTag string `json:"baseTag,omitempty"`
 
+		// MutationPolicy defines which container tags are rewritten by the admission controller.
+		// always rewrites any tag, onlyIfOlder keeps the tags newer than the tag of the image
+		// and onlyIfBaseTag only rewrites the containers without tag or using the base tag.
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Enum=always;onlyIfOlder;onlyIfBaseTag
+		// +kubebuilder:default:=always
+		MutationPolicy MutationPolicy `json:"mutationPolicy,omitempty"`
+
 		// +kubebuilder:validation:Required
 		// +kubebuilder:validation:MinItems=1
 		Triggers []ImageTrigger `json:"triggers"`
@@ -63,11 +71,29 @@ type (
 	// ImageTrigger
 	ImageTrigger struct {
 		// +kubebuilder:validation:Required
-		// +kubebuilder:validation:Enum=crontab;webhook
+		// +kubebuilder:validation:Enum=crontab;webhook;interval;on-pod-create;dependency
 		Type triggers.Name `json:"type"`
 
 		// +kubebuilder:validation:Optional
 		Value string `json:"value"`
+
+		// Every is the interval between two refreshes of the interval trigger (minimum 1m).
+		// The refreshes of the images are spread across the interval.
+		// +kubebuilder:validation:Optional
+		Every *metav1.Duration `json:"every,omitempty"`
+
+		// ImageRef is the Image of the namespace followed by the dependency trigger.
+		// The image is refreshed when the tag of the referenced Image changes.
+		// +kubebuilder:validation:Optional
+		ImageRef *corev1.LocalObjectReference `json:"imageRef,omitempty"`
+
+		// CatchUp is the policy applied to the crontab runs missed while kimup was down.
+		// runOnce refreshes the image once, skip ignores the missed runs
+		// and runAll refreshes the image for each missed run (up to 10).
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:validation:Enum=runOnce;skip;runAll
+		// +kubebuilder:default:=runOnce
+		CatchUp CatchUpPolicy `json:"catchUp,omitempty"`
 	}
 
 	// ImageRule
@@ -90,7 +116,7 @@ type (
 	// ImageAction
 	ImageAction struct {
 		// +kubebuilder:validation:Required
-		// +kubebuilder:validation:Enum=apply;request-approval;alert-discord
+		// +kubebuilder:validation:Enum=apply;request-approval;alert-discord;alert-email;alert-slack;alert-teams;alert-mattermost;alert-telegram;alert-webhook;git-commit;git-pull-request;patch-resource
 		Type string `json:"type"`
 
 		// +kubebuilder:validation:Optional
@@ -104,6 +130,81 @@ type (
 		Tag    string              `json:"tag"`
 		Result ImageStatusLastSync `json:"result"`
 		Time   string              `json:"time"`
+
+		// ObservedGeneration is the generation of the image used by the last sync.
+		// +optional
+		ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+		// Conditions are the conditions of the last sync (TagsFetched, RulesEvaluated, ActionsSucceeded and Ready).
+		// +optional
+		// +listType=map
+		// +listMapKey=type
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+		// LastSuccessfulSync is the time of the last sync without error.
+		// +optional
+		LastSuccessfulSync *metav1.Time `json:"lastSuccessfulSync,omitempty"`
+
+		// NextScheduledSync is the time of the next sync scheduled by the crontab trigger.
+		// +optional
+		NextScheduledSync *metav1.Time `json:"nextScheduledSync,omitempty"`
+
+		// LatestTag is the latest version available in the registry.
+		// +optional
+		LatestTag string `json:"latestTag,omitempty"`
+
+		// NewerTagSince is the time when a version newer than the tag has been found in the registry.
+		// It is not set if the image is up to date.
+		// +optional
+		NewerTagSince *metav1.Time `json:"newerTagSince,omitempty"`
+
+		// Rules are the results of the rules evaluated by the last sync.
+		// +optional
+		Rules []ImageStatusRule `json:"rules,omitempty"`
+
+		// Notifications is the list of the alerts already sent for the rules of the image.
+		// It is used to avoid sending the same alert for the same tag several times.
+		// +optional
+		Notifications []ImageStatusNotification `json:"notifications,omitempty"`
+
+		// History is the list of the last tags applied to the image, the most recent first.
+		// +optional
+		History []ImageStatusHistory `json:"history,omitempty"`
+
+		// CrontabRuns is the time of the last run of each crontab trigger.
+		// It is used to count the runs missed while kimup was down.
+		// +optional
+		// +listType=map
+		// +listMapKey=crontab
+		CrontabRuns []ImageStatusCrontabRun `json:"crontabRuns,omitempty"`
+	}
+
+	// ImageStatusCrontabRun is the last run of a crontab trigger
+	ImageStatusCrontabRun struct {
+		// Crontab is the crontab expression of the trigger.
+		Crontab string `json:"crontab"`
+
+		// Time is the time of the last run of the trigger.
+		Time metav1.Time `json:"time"`
+	}
+
+	// ImageStatusNotification is an alert already sent for a rule
+	ImageStatusNotification struct {
+		// Rule is the name of the rule that triggered the alert.
+		Rule string `json:"rule"`
+
+		// Action is the type of the alert action.
+		Action string `json:"action"`
+
+		// AlertConfig is the name of the AlertConfig used by the action.
+		// +optional
+		AlertConfig string `json:"alertConfig,omitempty"`
+
+		// Tag is the new tag notified.
+		Tag string `json:"tag"`
+
+		// Time is the time when the alert has been sent.
+		Time metav1.Time `json:"time"`
 	}
 )
 
@@ -113,8 +214,12 @@ type (
 // Image is the Schema for the images API
 // +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.image`
 // +kubebuilder:printcolumn:name="Tag",type=string,JSONPath=`.status.tag`
+// +kubebuilder:printcolumn:name="Latest",type=string,JSONPath=`.status.latestTag`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Last-Result",type=string,JSONPath=`.status.result`
 // +kubebuilder:printcolumn:name="Last-Sync",type=date,JSONPath=`.status.time`
+// +kubebuilder:printcolumn:name="Next-Sync",type=date,JSONPath=`.status.nextScheduledSync`,priority=1
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`,priority=1
 type Image struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -151,6 +256,46 @@ func (i *Image) SetStatusTime(time string) {
 	i.Status.Time = time
 }
 
+// IsAlreadyNotified returns true if the alert action of the rule has already been sent for the tag.
+func (i *Image) IsAlreadyNotified(rule string, action ImageAction, tag string) bool {
+	for _, n := range i.Status.Notifications {
+		if n.Rule == rule && n.Action == action.Type && n.AlertConfig == action.alertConfigName() {
+			return n.Tag == tag
+		}
+	}
+
+	return false
+}
+
+// SetNotified records that the alert action of the rule has been sent for the tag.
+func (i *Image) SetNotified(rule string, action ImageAction, tag string) {
+	notification := ImageStatusNotification{
+		Rule:        rule,
+		Action:      action.Type,
+		AlertConfig: action.alertConfigName(),
+		Tag:         tag,
+		Time:        metav1.Now(),
+	}
+
+	for k, n := range i.Status.Notifications {
+		if n.Rule == rule && n.Action == action.Type && n.AlertConfig == notification.AlertConfig {
+			i.Status.Notifications[k] = notification
+			return
+		}
+	}
+
+	i.Status.Notifications = append(i.Status.Notifications, notification)
+}
+
+// alertConfigName returns the name of the AlertConfig referenced by the action.
+func (a ImageAction) alertConfigName() string {
+	if a.Data.ValueFrom != nil && a.Data.ValueFrom.AlertConfigRef != nil {
+		return a.Data.ValueFrom.AlertConfigRef.Name
+	}
+
+	return ""
+}
+
 // GetImageWithTag returns the image name with the tag
 func (i *Image) GetImageWithTag() string {
 	if i.Status.Tag == "" {
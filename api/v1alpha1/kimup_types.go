@@ -34,6 +34,13 @@ type (
 		// Image of the Kimup container. If not set, the default image will be used.
 		Image string `json:"image,omitempty"`
 
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Number of Kimup replicas
+		// +kubebuilder:default:=1
+		// +kubebuilder:validation:Minimum=1
+		// Replicas is the number of Kimup pods. The Images are distributed across the replicas.
+		Replicas *int32 `json:"replicas,omitempty"`
+
 		// +kubebuilder:validation:Optional
 		// +kubebuilder:description: Annotations to add to the Kimup pods.
 		// Annotations is a key value map that will be added to the Kimup pods.
@@ -97,6 +104,11 @@ type (
 		// Metrics is a map of settings that will be used to configure the metrics probe. If not set, the probe will be enabled.
 		Metrics KimupProbeSpec `json:"metrics,omitempty"`
 
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the labels of the metrics of the images
+		// MetricsLabels is the list of the labels of the metrics of the images. The labels not listed are aggregated to limit the cardinality. If not set, all the labels will be used.
+		MetricsLabels []KimupMetricsLabel `json:"metricsLabels,omitempty"`
+
 		// +kubebuilder:validation:Optional
 		// +kubebuilder:description: Manage the healthz settings
 		// +kubebuilder:default:={enabled:true}
@@ -109,6 +121,116 @@ type (
 		// +kubebuilder:validation:Enum=debug;info;warn;error;fatal;panic;trace
 		// LogLevel is a string that will be used to configure the log level of the Kimup instance. If not set, the info log level will be used.
 		LogLevel string `json:"logLevel,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the registry push notifications settings
+		// Events configures the sources of the push notifications of the registries. If not set, the Images are only refreshed by their triggers.
+		Events KimupEventsSpec `json:"events,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the REST API settings
+		// API is the REST API of the Kimup instance, authenticated with the Kubernetes tokens. If not set, the API will be disabled.
+		API KimupAPISpec `json:"api,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the tracing settings
+		// Tracing exports the OpenTelemetry traces of the refreshes. If not set, the tracing will be disabled.
+		Tracing KimupTracingSpec `json:"tracing,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the patch-resource action settings
+		// PatchResource configures the resources patched by the patch-resource action. If not set, the Images can only patch the resources of their namespace.
+		PatchResource KimupPatchResourceSpec `json:"patchResource,omitempty"`
+	}
+
+	KimupPatchResourceSpec struct {
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the namespaces of the patched resources
+		// Namespaces is the list of the namespaces where the Images of the other namespaces can patch a resource. If not set, the Images can only patch the resources of their namespace.
+		Namespaces []string `json:"namespaces,omitempty"`
+	}
+
+	// KimupMetricsLabel is a label of the metrics of the images.
+	// +kubebuilder:validation:Enum=namespace;image;rule;action
+	KimupMetricsLabel string
+
+	KimupTracingSpec struct {
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the exporter of the traces
+		// +kubebuilder:validation:Enum=otlp;stdout
+		// Exporter is the exporter of the traces. The OTLP exporter is configured with the OTEL_EXPORTER_OTLP_* environment variables. If not set, the tracing will be disabled.
+		Exporter string `json:"exporter,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the sampling of the traces
+		// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+		// SampleRatio is the ratio of the traces sampled, between 0 and 1. If not set, all the traces will be sampled.
+		SampleRatio string `json:"sampleRatio,omitempty"`
+	}
+
+	KimupAPISpec struct {
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Enable or disable the REST API
+		// Enabled is a boolean that enables or disables the REST API. If not set, the API will be disabled.
+		Enabled bool `json:"enabled,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the port settings
+		// Port is the port number where the API will be exposed. If not set, the default port will be used. See https://pkg.go.dev/github.com/orange-cloudavenue/kube-image-updater@v0.0.1/internal/models#pkg-variables.
+		Port int32 `json:"port,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Enable or disable the web dashboard
+		// Dashboard is a boolean that serves the web dashboard on the REST API. If not set, the dashboard will be disabled.
+		Dashboard bool `json:"dashboard,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the TLS settings
+		// TLSSecretName is the name of a secret of type kubernetes.io/tls containing the certificate of the REST API. Required unless insecure is set.
+		TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Serve the REST API over plain HTTP
+		// Insecure is a boolean that serves the REST API over plain HTTP, e.g. behind a proxy terminating TLS. The bearer tokens of the requests are sent in clear text. If not set, the API will be served with TLS.
+		Insecure bool `json:"insecure,omitempty"`
+	}
+
+	KimupEventsSpec struct {
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the CloudEvents HTTP endpoint settings
+		// HTTP is the endpoint receiving the push notifications as CloudEvents over HTTP. If not set, the endpoint will be disabled.
+		HTTP KimupEventsHTTPSpec `json:"http,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the NATS settings
+		// NATS is the NATS subject carrying the push notifications. If not set, NATS will not be used.
+		NATS *KimupNATSSpec `json:"nats,omitempty"`
+	}
+
+	KimupEventsHTTPSpec struct {
+		KimupProbeSpec `json:",inline"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Manage the authentication of the endpoint
+		// TokenSecretRef is the key of a secret containing the token expected in the Authorization header of the requests ("Bearer <token>"). Required unless insecure is set.
+		TokenSecretRef *corev1.SecretKeySelector `json:"tokenSecretRef,omitempty"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: Accept the requests without token
+		// Insecure is a boolean that accepts the requests without token. Anyone reaching the endpoint can trigger the refreshes of the Images. If not set, the token is required.
+		Insecure bool `json:"insecure,omitempty"`
+	}
+
+	KimupNATSSpec struct {
+		// +kubebuilder:validation:Required
+		// +kubebuilder:description: URL of the NATS server
+		// URL is the URL of the NATS server (e.g. nats://nats.nats:4222).
+		URL string `json:"url"`
+
+		// +kubebuilder:validation:Optional
+		// +kubebuilder:description: NATS subject of the push notifications
+		// Subject is the NATS subject of the push notifications. If not set, the default subject will be used. See https://pkg.go.dev/github.com/orange-cloudavenue/kube-image-updater@v0.0.1/internal/models#pkg-variables.
+		Subject string `json:"subject,omitempty"`
 	}
 
 	KimupProbeSpec struct {
@@ -29,5 +29,38 @@ type (
 		// AlertConfigRef is a reference to a field in an alert configuration.
 		// +optional
 		AlertConfigRef *corev1.LocalObjectReference `json:"alertConfigRef,omitempty"`
+
+		// GitConfigRef is a reference to a git configuration.
+		// +optional
+		GitConfigRef *corev1.LocalObjectReference `json:"gitConfigRef,omitempty"`
+
+		// ResourceRef is a reference to a value of a Flux HelmRelease or an Argo CD Application.
+		// +optional
+		ResourceRef *ResourceFieldSelector `json:"resourceRef,omitempty"`
+	}
+
+	// ResourceFieldSelector selects a Helm value of a Flux HelmRelease or an Argo CD Application.
+	ResourceFieldSelector struct {
+		// Kind is the kind of the resource.
+		// +kubebuilder:validation:Enum=HelmRelease;Application
+		Kind string `json:"kind"`
+
+		// APIVersion is the API version of the resource.
+		// Default is helm.toolkit.fluxcd.io/v2 for HelmRelease and argoproj.io/v1alpha1 for Application.
+		// +optional
+		APIVersion string `json:"apiVersion,omitempty"`
+
+		// Name is the name of the resource.
+		Name string `json:"name"`
+
+		// Namespace is the namespace of the resource. Default is the namespace of the image.
+		// +optional
+		Namespace string `json:"namespace,omitempty"`
+
+		// Path is the dot separated path of the value in spec.values for a HelmRelease
+		// or the name of the Helm parameter in spec.source.helm.parameters for an Application.
+		// +kubebuilder:default:=image.tag
+		// +optional
+		Path string `json:"path,omitempty"`
 	}
 )
@@ -21,7 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -31,7 +32,7 @@ func (in *AlertConfig) DeepCopyInto(out *AlertConfig) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertConfig.
@@ -97,6 +98,36 @@ func (in *AlertConfigSpec) DeepCopyInto(out *AlertConfigSpec) {
 		*out = new(AlertEmailSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(AlertSlackSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = new(AlertTeamsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mattermost != nil {
+		in, out := &in.Mattermost, &out.Mattermost
+		*out = new(AlertMattermostSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Telegram != nil {
+		in, out := &in.Telegram, &out.Telegram
+		*out = new(AlertTelegramSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AlertWebhookSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Digest != nil {
+		in, out := &in.Digest, &out.Digest
+		*out = new(AlertDigestSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertConfigSpec.
@@ -112,6 +143,24 @@ func (in *AlertConfigSpec) DeepCopy() *AlertConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertConfigStatus) DeepCopyInto(out *AlertConfigStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSent != nil {
+		in, out := &in.LastSent, &out.LastSent
+		*out = (*in).DeepCopy()
+	}
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]AlertDigestEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertConfigStatus.
@@ -154,6 +203,37 @@ func (in *AlertCoreStatus) DeepCopy() *AlertCoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertDigestEntry) DeepCopyInto(out *AlertDigestEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertDigestEntry.
+func (in *AlertDigestEntry) DeepCopy() *AlertDigestEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertDigestEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertDigestSpec) DeepCopyInto(out *AlertDigestSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertDigestSpec.
+func (in *AlertDigestSpec) DeepCopy() *AlertDigestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertDigestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertDiscordSpec) DeepCopyInto(out *AlertDiscordSpec) {
 	*out = *in
@@ -195,7 +275,252 @@ func (in *AlertEmailSpec) DeepCopy() *AlertEmailSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *AlertMattermostSpec) DeepCopyInto(out *AlertMattermostSpec) {
+	*out = *in
+	in.WebhookURL.DeepCopyInto(&out.WebhookURL)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertMattermostSpec.
+func (in *AlertMattermostSpec) DeepCopy() *AlertMattermostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertMattermostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSlackSpec) DeepCopyInto(out *AlertSlackSpec) {
+	*out = *in
+	in.WebhookURL.DeepCopyInto(&out.WebhookURL)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSlackSpec.
+func (in *AlertSlackSpec) DeepCopy() *AlertSlackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSlackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertTeamsSpec) DeepCopyInto(out *AlertTeamsSpec) {
+	*out = *in
+	in.WebhookURL.DeepCopyInto(&out.WebhookURL)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTeamsSpec.
+func (in *AlertTeamsSpec) DeepCopy() *AlertTeamsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertTeamsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertTelegramSpec) DeepCopyInto(out *AlertTelegramSpec) {
+	*out = *in
+	in.Token.DeepCopyInto(&out.Token)
+	if in.Chats != nil {
+		in, out := &in.Chats, &out.Chats
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTelegramSpec.
+func (in *AlertTelegramSpec) DeepCopy() *AlertTelegramSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertTelegramSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertWebhookHeader) DeepCopyInto(out *AlertWebhookHeader) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertWebhookHeader.
+func (in *AlertWebhookHeader) DeepCopy() *AlertWebhookHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertWebhookHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertWebhookSpec) DeepCopyInto(out *AlertWebhookSpec) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]AlertWebhookHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertWebhookSpec.
+func (in *AlertWebhookSpec) DeepCopy() *AlertWebhookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertWebhookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImage) DeepCopyInto(out *ClusterImage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImage.
+func (in *ClusterImage) DeepCopy() *ClusterImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageList) DeepCopyInto(out *ClusterImageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageList.
+func (in *ClusterImageList) DeepCopy() *ClusterImageList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageSpec) DeepCopyInto(out *ClusterImageSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ImageSpec.DeepCopyInto(&out.ImageSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageSpec.
+func (in *ClusterImageSpec) DeepCopy() *ClusterImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageStatus) DeepCopyInto(out *ClusterImageStatus) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overridden != nil {
+		in, out := &in.Overridden, &out.Overridden
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageStatus.
+func (in *ClusterImageStatus) DeepCopy() *ClusterImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitAuthSpec) DeepCopyInto(out *GitAuthSpec) {
+	*out = *in
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitAuthSpec.
+func (in *GitAuthSpec) DeepCopy() *GitAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitAuthorSpec) DeepCopyInto(out *GitAuthorSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitAuthorSpec.
+func (in *GitAuthorSpec) DeepCopy() *GitAuthorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitAuthorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitConfig) DeepCopyInto(out *GitConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -203,6 +528,138 @@ func (in *Image) DeepCopyInto(out *Image) {
 	out.Status = in.Status
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfig.
+func (in *GitConfig) DeepCopy() *GitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitConfigList) DeepCopyInto(out *GitConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfigList.
+func (in *GitConfigList) DeepCopy() *GitConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitConfigSpec) DeepCopyInto(out *GitConfigSpec) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(GitAuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Author = in.Author
+	out.File = in.File
+	if in.PullRequest != nil {
+		in, out := &in.PullRequest, &out.PullRequest
+		*out = new(GitPullRequestSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfigSpec.
+func (in *GitConfigSpec) DeepCopy() *GitConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitConfigStatus) DeepCopyInto(out *GitConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfigStatus.
+func (in *GitConfigStatus) DeepCopy() *GitConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitFileSpec) DeepCopyInto(out *GitFileSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitFileSpec.
+func (in *GitFileSpec) DeepCopy() *GitFileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitFileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitPullRequestSpec) DeepCopyInto(out *GitPullRequestSpec) {
+	*out = *in
+	in.Token.DeepCopyInto(&out.Token)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitPullRequestSpec.
+func (in *GitPullRequestSpec) DeepCopy() *GitPullRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitPullRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Image) DeepCopyInto(out *Image) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
 func (in *Image) DeepCopy() *Image {
 	if in == nil {
@@ -296,13 +753,15 @@ func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
 	*out = *in
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
+		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.Triggers != nil {
 		in, out := &in.Triggers, &out.Triggers
 		*out = make([]ImageTrigger, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Rules != nil {
 		in, out := &in.Rules, &out.Rules
@@ -326,6 +785,53 @@ func (in *ImageSpec) DeepCopy() *ImageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSuccessfulSync != nil {
+		in, out := &in.LastSuccessfulSync, &out.LastSuccessfulSync
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduledSync != nil {
+		in, out := &in.NextScheduledSync, &out.NextScheduledSync
+		*out = (*in).DeepCopy()
+	}
+	if in.NewerTagSince != nil {
+		in, out := &in.NewerTagSince, &out.NewerTagSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ImageStatusRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]ImageStatusNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ImageStatusHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CrontabRuns != nil {
+		in, out := &in.CrontabRuns, &out.CrontabRuns
+		*out = make([]ImageStatusCrontabRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
@@ -338,9 +844,107 @@ func (in *ImageStatus) DeepCopy() *ImageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatusAction) DeepCopyInto(out *ImageStatusAction) {
+	*out = *in
+	out.Duration = in.Duration
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatusAction.
+func (in *ImageStatusAction) DeepCopy() *ImageStatusAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatusAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatusCrontabRun) DeepCopyInto(out *ImageStatusCrontabRun) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatusCrontabRun.
+func (in *ImageStatusCrontabRun) DeepCopy() *ImageStatusCrontabRun {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatusCrontabRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatusHistory) DeepCopyInto(out *ImageStatusHistory) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatusHistory.
+func (in *ImageStatusHistory) DeepCopy() *ImageStatusHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatusHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatusNotification) DeepCopyInto(out *ImageStatusNotification) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatusNotification.
+func (in *ImageStatusNotification) DeepCopy() *ImageStatusNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatusNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatusRule) DeepCopyInto(out *ImageStatusRule) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]ImageStatusAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatusRule.
+func (in *ImageStatusRule) DeepCopy() *ImageStatusRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatusRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageTrigger) DeepCopyInto(out *ImageTrigger) {
 	*out = *in
+	if in.Every != nil {
+		in, out := &in.Every, &out.Every
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ImageRef != nil {
+		in, out := &in.ImageRef, &out.ImageRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTrigger.
@@ -380,11 +984,77 @@ func (in *Kimup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KimupAPISpec) DeepCopyInto(out *KimupAPISpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupAPISpec.
+func (in *KimupAPISpec) DeepCopy() *KimupAPISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KimupAPISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KimupEventsHTTPSpec) DeepCopyInto(out *KimupEventsHTTPSpec) {
+	*out = *in
+	out.KimupProbeSpec = in.KimupProbeSpec
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupEventsHTTPSpec.
+func (in *KimupEventsHTTPSpec) DeepCopy() *KimupEventsHTTPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KimupEventsHTTPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KimupEventsSpec) DeepCopyInto(out *KimupEventsSpec) {
+	*out = *in
+	in.HTTP.DeepCopyInto(&out.HTTP)
+	if in.NATS != nil {
+		in, out := &in.NATS, &out.NATS
+		*out = new(KimupNATSSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupEventsSpec.
+func (in *KimupEventsSpec) DeepCopy() *KimupEventsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KimupEventsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KimupExtraSpec) DeepCopyInto(out *KimupExtraSpec) {
 	*out = *in
 	out.Metrics = in.Metrics
+	if in.MetricsLabels != nil {
+		in, out := &in.MetricsLabels, &out.MetricsLabels
+		*out = make([]KimupMetricsLabel, len(*in))
+		copy(*out, *in)
+	}
 	out.Healthz = in.Healthz
+	in.Events.DeepCopyInto(&out.Events)
+	out.API = in.API
+	out.Tracing = in.Tracing
+	in.PatchResource.DeepCopyInto(&out.PatchResource)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupExtraSpec.
@@ -416,19 +1086,19 @@ func (in *KimupInstanceSpec) DeepCopyInto(out *KimupInstanceSpec) {
 	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.NodeSelector != nil {
@@ -440,14 +1110,14 @@ func (in *KimupInstanceSpec) DeepCopyInto(out *KimupInstanceSpec) {
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -496,6 +1166,41 @@ func (in *KimupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KimupNATSSpec) DeepCopyInto(out *KimupNATSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupNATSSpec.
+func (in *KimupNATSSpec) DeepCopy() *KimupNATSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KimupNATSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KimupPatchResourceSpec) DeepCopyInto(out *KimupPatchResourceSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupPatchResourceSpec.
+func (in *KimupPatchResourceSpec) DeepCopy() *KimupPatchResourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KimupPatchResourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KimupProbeSpec) DeepCopyInto(out *KimupProbeSpec) {
 	*out = *in
@@ -514,6 +1219,11 @@ func (in *KimupProbeSpec) DeepCopy() *KimupProbeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KimupSpec) DeepCopyInto(out *KimupSpec) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
@@ -530,19 +1240,19 @@ func (in *KimupSpec) DeepCopyInto(out *KimupSpec) {
 	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.NodeSelector != nil {
@@ -554,19 +1264,19 @@ func (in *KimupSpec) DeepCopyInto(out *KimupSpec) {
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.KimupExtraSpec = in.KimupExtraSpec
+	in.KimupExtraSpec.DeepCopyInto(&out.KimupExtraSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupSpec.
@@ -594,22 +1304,62 @@ func (in *KimupStatus) DeepCopy() *KimupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KimupTracingSpec) DeepCopyInto(out *KimupTracingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KimupTracingSpec.
+func (in *KimupTracingSpec) DeepCopy() *KimupTracingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KimupTracingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFieldSelector) DeepCopyInto(out *ResourceFieldSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFieldSelector.
+func (in *ResourceFieldSelector) DeepCopy() *ResourceFieldSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFieldSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValueFromSource) DeepCopyInto(out *ValueFromSource) {
 	*out = *in
 	if in.SecretKeyRef != nil {
 		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(v1.SecretKeySelector)
+		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ConfigMapKeyRef != nil {
 		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
-		*out = new(v1.ConfigMapKeySelector)
+		*out = new(corev1.ConfigMapKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.AlertConfigRef != nil {
 		in, out := &in.AlertConfigRef, &out.AlertConfigRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.GitConfigRef != nil {
+		in, out := &in.GitConfigRef, &out.GitConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ResourceRef != nil {
+		in, out := &in.ResourceRef, &out.ResourceRef
+		*out = new(ResourceFieldSelector)
 		**out = **in
 	}
 }
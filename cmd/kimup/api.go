@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/dashboard"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/httpserver"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/restapi"
+)
+
+// setupAPI adds the REST API server and its web dashboard if enabled.
+// The Images are read from the cache of the manager.
+// The API receives bearer tokens, so it is served with TLS unless plain HTTP is explicitly allowed.
+func setupAPI(a httpserver.InterfaceServer, c client.Client, k kubernetes.Interface) error {
+	if flag.Lookup(models.APIFlagName).Value.String() != "true" {
+		return nil
+	}
+
+	opts := []httpserver.Option{httpserver.WithAddr(fmt.Sprintf(":%d", restapi.Port))}
+	switch {
+	case restapi.TLSCertFile != "" && restapi.TLSKeyFile != "":
+		// Fail early if the certificate can not be loaded
+		certificate := &certificateLoader{certFile: restapi.TLSCertFile, keyFile: restapi.TLSKeyFile}
+		if _, err := certificate.GetCertificate(nil); err != nil {
+			return fmt.Errorf("unable to load the certificate of the REST API: %w", err)
+		}
+		opts = append(opts, httpserver.WithTLS(&tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certificate.GetCertificate,
+		}))
+	case flag.Lookup(models.APIInsecureFlagName).Value.String() == "true":
+		log.Warn("The REST API is served over plain HTTP, the bearer tokens of the requests are sent in clear text")
+	default:
+		return fmt.Errorf("the REST API requires a certificate (--%s and --%s) or --%s to be served over plain HTTP", models.APITLSCertFileFlagName, models.APITLSKeyFileFlagName, models.APIInsecureFlagName)
+	}
+
+	s, err := a.Add("api", opts...)
+	if err != nil {
+		return err
+	}
+	restapi.New(c, k).Routes(s.Config)
+
+	if flag.Lookup(models.APIDashboardFlagName).Value.String() == "true" {
+		s.Config.Get("/", http.RedirectHandler("/dashboard/", http.StatusFound).ServeHTTP)
+		s.Config.Handle("/dashboard/*", http.StripPrefix("/dashboard", dashboard.Handler()))
+	}
+
+	return nil
+}
+
+// certificateLoader loads the certificate of the REST API and reloads it when the files change,
+// to follow the renewals of the secret without reading the files at each handshake.
+type certificateLoader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// GetCertificate returns the certificate, reloaded if the modification time of the files has changed.
+func (l *certificateLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	modTime, err := l.lastModTime()
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cert != nil && modTime.Equal(l.modTime) {
+		return l.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		// The files may be read during a renewal, the previous certificate is served until the next handshake
+		if l.cert != nil {
+			log.WithError(err).Warn("Unable to reload the certificate of the REST API")
+			return l.cert, nil
+		}
+		return nil, err
+	}
+	l.cert, l.modTime = &cert, modTime
+
+	return l.cert, nil
+}
+
+// lastModTime returns the latest modification time of the certificate and key files.
+func (l *certificateLoader) lastModTime() (time.Time, error) {
+	var modTime time.Time
+	for _, file := range []string{l.certFile, l.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	return modTime, nil
+}
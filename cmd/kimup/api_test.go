@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self-signed certificate and its key with the modification time.
+func writeCertificate(t *testing.T, certFile, keyFile string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "kimup"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func TestCertificateLoader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	writeCertificate(t, certFile, keyFile, modTime)
+
+	l := &certificateLoader{certFile: certFile, keyFile: keyFile}
+	first, err := l.GetCertificate(nil)
+	require.NoError(t, err)
+
+	// The certificate is not reloaded while the files are unchanged
+	got, err := l.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, first, got)
+
+	// The certificate is reloaded when the files are renewed
+	writeCertificate(t, certFile, keyFile, modTime.Add(time.Second))
+	renewed, err := l.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.NotSame(t, first, renewed)
+	assert.NotEqual(t, first.Certificate, renewed.Certificate)
+
+	// The previous certificate is served while the files can not be loaded
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	require.NoError(t, os.Chtimes(keyFile, modTime.Add(2*time.Second), modTime.Add(2*time.Second)))
+	got, err = l.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, renewed, got)
+
+	// The certificate is required at startup
+	_, err = (&certificateLoader{certFile: certFile, keyFile: keyFile}).GetCertificate(nil)
+	require.Error(t, err)
+}
@@ -2,8 +2,15 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/reugn/go-quartz/quartz"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
@@ -13,51 +20,139 @@ import (
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/crontab"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/dependency"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/interval"
 )
 
+// dependencies contains the Images followed by the dependency triggers.
+var dependencies = dependency.New()
+
+// setupTriggers converges the triggers registered for the image with its spec.
 func setupTriggers(x *v1alpha1.Image) {
-	// * Triggers
+	xlog := log.WithFields(logrus.Fields{
+		"namespace": x.Namespace,
+		"name":      x.Name,
+	})
+
+	schedules := []quartz.Trigger{}
 	for _, trigger := range x.Spec.Triggers {
 		switch trigger.Type {
 		case triggers.Crontab:
-			if ok, err := crontab.IsExistingJob(crontab.BuildKey(x.Namespace, x.Name)); err != nil || !ok {
-				if err := crontab.AddCronTab(x.Namespace, x.Name, trigger.Value); err != nil {
-					log.
-						WithError(err).
-						WithFields(logrus.Fields{
-							"crontab":   trigger.Value,
-							"namespace": x.Namespace,
-							"name":      x.Name,
-						}).Error("Error adding cronjob")
-				}
+			cronTrigger, err := crontab.NewTrigger(trigger.Value, crontab.BuildKey(x.Namespace, x.Name))
+			if err != nil {
+				xlog.WithError(err).WithField("crontab", trigger.Value).Error("Error adding cronjob")
+				continue
+			}
+			schedules = append(schedules, cronTrigger)
+		case triggers.Interval:
+			if trigger.Every == nil {
+				continue
 			}
-		case triggers.Webhook:
-			log.Info("Webhook trigger not implemented yet")
+			intervalTrigger, err := interval.New(trigger.Every.Duration, crontab.BuildKey(x.Namespace, x.Name))
+			if err != nil {
+				xlog.WithError(err).WithField("interval", trigger.Every.Duration).Error("Error adding interval")
+				continue
+			}
+			schedules = append(schedules, intervalTrigger)
 		}
 	}
+
+	if _, err := crontab.SyncTriggers(x.Namespace, x.Name, schedules); err != nil {
+		xlog.WithError(err).Error("Error adding cronjob")
+	}
+
+	dependencies.Set(x.Namespace, x.Name, x.Dependencies())
 }
 
-func cleanTriggers(x *v1alpha1.Image) {
-	for _, trigger := range x.Spec.Triggers {
-		switch trigger.Type {
-		case triggers.Crontab:
-			if ok, err := crontab.IsExistingJob(crontab.BuildKey(x.Namespace, x.Name)); err != nil || ok {
-				if err := crontab.RemoveJob(crontab.BuildKey(x.Namespace, x.Name)); err != nil {
-					log.
-						WithError(err).
-						WithFields(logrus.Fields{
-							"namespace": x.Namespace,
-							"name":      x.Name,
-						}).Error("Error removing crontab")
-				}
+// refreshDependents refreshes the Images following the image if its tag has changed.
+func refreshDependents(x *v1alpha1.Image) {
+	for _, dependent := range dependencies.Observe(x.Namespace, x.Name, x.Status.Tag) {
+		log.WithFields(logrus.Fields{
+			"namespace":  x.Namespace,
+			"name":       dependent,
+			"dependency": x.Name,
+			"tag":        x.Status.Tag,
+		}).Info("Dependency trigger refresh")
+		refresh(v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Namespace: x.Namespace, Name: dependent}})
+	}
+}
+
+// setupDigests registers the digest jobs of the existing alert configurations.
+// It permits to send the pending updates queued before a restart.
+func setupDigests(ctx context.Context, kubeClient kubeclient.Interface) {
+	alertConfigs, err := kubeClient.Alert().List(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).Error("Error listing alert configurations")
+		return
+	}
+
+	for _, aC := range alertConfigs.Items {
+		if err := actions.SetupDigest(aC); err != nil {
+			log.
+				WithError(err).
+				WithFields(logrus.Fields{
+					"namespace":   aC.Namespace,
+					"alertConfig": aC.Name,
+				}).Error("Error adding digest")
+		}
+	}
+}
+
+// cleanTriggers removes the triggers registered for the image.
+func cleanTriggers(namespace, name string) {
+	dependencies.Remove(namespace, name)
+
+	if err := crontab.RemoveImageJobs(namespace, name); err != nil {
+		log.
+			WithError(err).
+			WithFields(logrus.Fields{
+				"namespace": namespace,
+				"name":      name,
+			}).Error("Error removing crontab")
+	}
+}
+
+// catchUpMissedRuns refreshes the image for the crontab runs missed since the last run of each trigger,
+// according to the catch-up policy of the trigger.
+func catchUpMissedRuns(k kubeclient.Interface, image *v1alpha1.Image) {
+	for index, trigger := range image.Spec.Triggers {
+		if trigger.Type != triggers.Crontab {
+			continue
+		}
+
+		xlog := log.WithFields(logrus.Fields{
+			"namespace": image.Namespace,
+			"name":      image.Name,
+			"crontab":   trigger.Value,
+			"policy":    trigger.CatchUp,
+		})
+
+		missed, err := crontab.MissedRuns(trigger.Value, crontab.BuildKey(image.Namespace, image.Name), image.LastCrontabRunTime(trigger.Value), time.Now(), v1alpha1.MaxCatchUpRuns)
+		if err != nil {
+			xlog.WithError(err).Error("Error computing the missed crontab runs")
+			continue
+		}
+		if len(missed) == 0 {
+			continue
+		}
+
+		runs := trigger.CatchUp.CatchUpRuns(len(missed))
+		xlog.WithFields(logrus.Fields{
+			"missed": len(missed),
+			"runs":   runs,
+		}).Info("Crontab runs missed while kimup was down")
+		k.Image().Event(image, corev1.EventTypeNormal, "Catch up missed runs", fmt.Sprintf("%d crontab runs missed since %s, %d refreshes triggered (policy %s)", len(missed), missed[0].Format(time.RFC3339), runs, trigger.CatchUp))
+
+		// The refreshes are recorded as runs of the trigger
+		for i := 0; i < runs; i++ {
+			if _, err := triggers.TriggerSchedule(triggers.RefreshImage, image.Namespace, image.Name, index); err != nil {
+				xlog.WithError(err).Error("Error triggering refresh")
 			}
-		case triggers.Webhook:
-			log.Info("Webhook trigger not implemented yet")
 		}
 	}
 }
 
-func refreshIfRequired(an annotations.Annotation, image v1alpha1.Image) {
+func refreshIfRequired(ctx context.Context, an annotations.Annotation, image v1alpha1.Image) {
 	if an.Action().Get() == annotations.ActionRefresh {
 		// * Here is only if the image has annotations.ActionRefresh
 		log.
@@ -65,8 +160,23 @@ func refreshIfRequired(an annotations.Annotation, image v1alpha1.Image) {
 				"namespace": image.Namespace,
 				"name":      image.Name,
 			}).Info("Annotation trigger refresh")
-		refresh(image)
+
+		// The refresh-hint is set by the replica receiving a push notification of an Image it does not own
+		if hint := an.RefreshHint().Get(); hint.Tag != "" {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(hint.TraceContext))
+			if _, err := triggers.TriggerWithTag(ctx, triggers.RefreshImage, image.Namespace, image.Name, hint.Tag); err != nil {
+				log.
+					WithFields(logrus.Fields{
+						"namespace": image.Namespace,
+						"name":      image.Name,
+					}).
+					Error("Error triggering event")
+			}
+		} else {
+			refresh(image)
+		}
 		an.Remove(annotations.KeyAction)
+		an.RefreshHint().Remove()
 	}
 }
 
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/httpserver"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/eventsource"
+)
+
+// setupEventSources subscribes to the push notifications of the registries if enabled.
+// The Images of the pushed repositories are listed from the cache of the manager.
+func setupEventSources(ctx context.Context, a httpserver.InterfaceServer, c client.Client, shard *sharding.Sharder) error {
+	dispatcher := eventsource.NewDispatcher(func(ctx context.Context) ([]v1alpha1.Image, error) {
+		var images v1alpha1.ImageList
+		if err := c.List(ctx, &images); err != nil {
+			return nil, err
+		}
+		return images.Items, nil
+	})
+
+	if flag.Lookup(models.EventsHTTPFlagName).Value.String() == "true" {
+		s, err := a.Add("events", httpserver.WithAddr(fmt.Sprintf(":%d", eventsource.HTTPPort)))
+		if err != nil {
+			return err
+		}
+		token := os.Getenv(eventsource.EnvHTTPToken)
+		if token == "" {
+			if flag.Lookup(models.EventsHTTPInsecureFlagName).Value.String() != "true" {
+				return fmt.Errorf("the endpoint of the push notifications requires a token (%s) or --%s to accept the requests without token", eventsource.EnvHTTPToken, models.EventsHTTPInsecureFlagName)
+			}
+			log.Warn("The endpoint of the push notifications is not authenticated, anyone reaching it can trigger the refreshes of the Images")
+		}
+		// The requests are load balanced across the replicas, the refreshes are routed to the owner of the Image
+		s.Config.Post(eventsource.HTTPPath, dispatcher.WithSharding(shard, c).WithToken(token).Handler().ServeHTTP)
+	}
+
+	if eventsource.NATSURL != "" {
+		broker, err := eventsource.NewNATS(eventsource.NATSURL)
+		if err != nil {
+			return err
+		}
+		if err := dispatcher.Subscribe(ctx, broker, eventsource.NATSSubject); err != nil {
+			return err
+		}
+		log.WithFields(logrus.Fields{
+			"url":     eventsource.NATSURL,
+			"subject": eventsource.NATSSubject,
+		}).Info("Subscribed to the push notifications")
+	}
+
+	return nil
+}
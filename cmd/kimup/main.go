@@ -9,16 +9,31 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/bombsimon/logrusr/v4"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/httpserver"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/metrics"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
-	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/tracing"
 )
 
-var c = make(chan os.Signal, 1)
+var (
+	scheme = runtime.NewScheme()
+	c      = make(chan os.Signal, 1)
+
+	// resyncPeriod is the period after which all the Images are reconciled again.
+	resyncPeriod = 10 * time.Minute
+)
 
 func init() {
 	// Initialize the metrics
@@ -27,15 +42,20 @@ func init() {
 	metrics.Actions()
 	metrics.Rules()
 	metrics.Registry()
+	metrics.Images()
 
-	// Flag "loglevel" is set in log package
-	flag.Parse()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 }
 
 func main() {
+	// Flag "loglevel" is set in log package
+	flag.Parse()
+
 	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
 
 	log.WithField("version", models.Version).Info("Starting kimup", models.Version)
+	ctrl.SetLogger(logrusr.New(log.GetLogger()))
 
 	// kubernetes golang library provide flag "kubeconfig" to specify the path to the kubeconfig file
 	k, err := kubeclient.New(flag.Lookup("kubeconfig").Value.String(), kubeclient.ComponentController)
@@ -46,6 +66,27 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// * Export the traces
+	shutdownTracing, err := tracing.Init(ctx, "kimup")
+	if err != nil {
+		log.WithError(err).Panic("Error setting up the tracing")
+	}
+
+	// * Manager serving the Images from its informers
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: "0", // metrics are served by common metrics server
+		},
+		HealthProbeBindAddress: "0", // healthz is served by common healthz server
+		Cache: cache.Options{
+			SyncPeriod: &resyncPeriod,
+		},
+	})
+	if err != nil {
+		log.WithError(err).Panic("Error creating the manager")
+	}
+
 	// * Config the metrics and healthz server
 	a, waitHTTP := httpserver.Init(ctx, httpserver.WithCustomHandlerForHealth(
 		func() (bool, error) {
@@ -57,96 +98,61 @@ func main() {
 			return true, nil
 		}))
 
+	// * Sharding of the Images across the replicas
+	var shard *sharding.Sharder
+	if name, namespace := os.Getenv(sharding.EnvPodName), os.Getenv(sharding.EnvPodNamespace); name != "" && namespace != "" {
+		shard = sharding.New(k, namespace, name)
+		if err := shard.Start(ctx); err != nil {
+			log.WithError(err).Panic("Error starting the sharding")
+		}
+	} else {
+		log.Warn("Sharding disabled, the environment variables POD_NAME and POD_NAMESPACE are not set")
+	}
+
+	// * Push notifications of the registries
+	if err := setupEventSources(ctx, a, mgr.GetClient(), shard); err != nil {
+		log.WithError(err).Error("Failed to setup the event sources")
+		// send signal to stop the program
+		c <- syscall.SIGINT
+	}
+
+	// * REST API
+	if err := setupAPI(a, mgr.GetClient(), k); err != nil {
+		log.WithError(err).Error("Failed to setup the REST API")
+		// send signal to stop the program
+		c <- syscall.SIGINT
+	}
+
 	if err := a.Run(); err != nil {
 		log.WithError(err).Error("Failed to start HTTP servers")
 		// send signal to stop the program
 		c <- syscall.SIGINT
 	}
 
-	initScheduler(ctx, k)
+	initScheduler(ctx, k, shard)
+	setupDigests(ctx, k)
 
-	go func() {
-		x, xErr, err := k.Image().Watch(ctx)
-		if err != nil {
-			log.WithError(err).Panic("Error watching events")
-		}
+	// * Reconcile the Images
+	if err := (&imageReconciler{
+		Client: mgr.GetClient(),
+		k:      k,
+		shard:  shard,
+	}).SetupWithManager(mgr); err != nil {
+		log.WithError(err).Panic("Error creating the image controller")
+	}
 
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case err, ok := <-xErr:
-				if ok {
-					log.WithError(err).Error("Error watching events from kubernetes")
-				}
-				// Exit the program
-				c <- syscall.SIGINT
-				return
-			case event, ok := <-x:
-				if !ok {
-					return
-				}
-
-				ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-				defer cancel()
-
-				an := annotations.New(ctx, &event.Value)
-
-				switch event.Type {
-				case "ADDED":
-					// Clean old action
-					an.Remove(annotations.KeyAction)
-
-					setupTriggers(&event.Value)
-					refreshIfRequired(an, event.Value)
-					if err := setTagIfNotExists(ctx, k, an, &event.Value); err != nil {
-						log.WithError(err).Error("Error setting tag")
-					}
-
-					if err := k.Image().Update(ctx, event.Value); err != nil {
-						log.WithError(err).Error("Error updating image")
-					}
-
-				case "MODIFIED":
-					switch an.Action().Get() { //nolint:gocritic
-					case annotations.ActionReload:
-
-						// * Here is only if the yaml has been updated and the operator has detected it
-						for _, trigger := range event.Value.Spec.Triggers {
-							switch trigger.Type {
-							case triggers.Crontab:
-								cleanTriggers(&event.Value)
-							case triggers.Webhook:
-								log.Warn("Webhook trigger not implemented yet")
-							}
-						}
-
-						refresh(event.Value)
-
-						// Remove the annotation annotations.AnnotationActionKey in the map[string]string
-						an.Remove(annotations.KeyAction)
-					}
-
-					refreshIfRequired(an, event.Value)
-
-					if err := k.Image().Update(ctx, event.Value); err != nil {
-						log.WithError(err).Error("Error updating image")
-					}
-
-					setupTriggers(&event.Value)
-
-				case "DELETED":
-					cleanTriggers(&event.Value)
-				case "ERROR":
-					log.Error("Error watching events from kubernetes")
-					// exit the program
-					c <- syscall.SIGINT
-				}
-			}
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			log.WithError(err).Error("Error running the manager")
+			// Exit the program
+			c <- syscall.SIGINT
 		}
 	}()
 
 	<-c
 	cancel()
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.WithError(err).Error("Failed to flush the traces")
+	}
 	waitHTTP()
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/metrics"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+)
+
+// imageReconciler converges the triggers of the replica with the Images.
+// The Images are served by the informers of the manager, which resync periodically
+// and reconnect after the errors of the API server.
+type imageReconciler struct {
+	client.Client
+	k     kubeclient.Interface
+	shard *sharding.Sharder
+
+	// seen contains the keys of the Images owned and already reconciled by the replica.
+	seen sync.Map
+}
+
+// Reconcile registers the triggers of the Image on all the replicas.
+// The owner of the Image catches up the missed runs, executes the action annotation and sets the tag.
+func (r *imageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	key := imageKey(req.Namespace, req.Name)
+
+	var image v1alpha1.Image
+	if err := r.Get(ctx, req.NamespacedName, &image); err != nil {
+		if apierrors.IsNotFound(err) {
+			cleanTriggers(req.Namespace, req.Name)
+			metrics.Forget(req.Namespace, req.Name)
+			r.seen.Delete(key)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The triggers are registered on all the replicas, the owner of the Image executes them.
+	setupTriggers(&image)
+	refreshDependents(&image)
+
+	// Only the owner updates the Image.
+	if !r.shard.IsOwner(key) {
+		// The series of the Image are exported by its owner
+		metrics.Forget(req.Namespace, req.Name)
+		r.seen.Delete(key)
+		return ctrl.Result{}, nil
+	}
+
+	original := image.DeepCopy()
+	if image.Annotations == nil {
+		image.Annotations = map[string]string{}
+	}
+	an := annotations.New(ctx, &image)
+
+	if _, seen := r.seen.LoadOrStore(key, true); !seen {
+		catchUpMissedRuns(r.k, &image)
+	}
+
+	if an.Action().Get() == annotations.ActionReload {
+		// * Here is only if the yaml has been updated and the operator has detected it
+		refresh(image)
+		an.Remove(annotations.KeyAction)
+	}
+	refreshIfRequired(ctx, an, image)
+
+	if err := setTagIfNotExists(ctx, r.k, an, &image); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			"namespace": image.Namespace,
+			"name":      image.Name,
+		}).Error("Error setting tag")
+	}
+
+	if equality.Semantic.DeepEqual(original.ObjectMeta, image.ObjectMeta) {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, r.k.Image().Update(ctx, image)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// All the Images are reconciled again when the members of the shard change.
+func (r *imageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("kimup").
+		For(&v1alpha1.Image{})
+
+	if r.shard != nil {
+		events := make(chan ctrlevent.GenericEvent)
+		b = b.WatchesRawSource(source.Channel(events, &handler.EnqueueRequestForObject{}))
+
+		// The listener is registered when the cache is synced to list the Images from the cache
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			if !mgr.GetCache().WaitForCacheSync(ctx) {
+				return nil
+			}
+			r.shard.OnChange(func(_ []string) {
+				go r.enqueueAll(ctx, events)
+			})
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+
+	return b.Complete(r)
+}
+
+// enqueueAll sends all the Images to the controller.
+func (r *imageReconciler) enqueueAll(ctx context.Context, events chan<- ctrlevent.GenericEvent) {
+	var images v1alpha1.ImageList
+	if err := r.List(ctx, &images); err != nil {
+		log.WithError(err).Error("Error listing images")
+		return
+	}
+
+	for i := range images.Items {
+		select {
+		case <-ctx.Done():
+			return
+		case events <- ctrlevent.GenericEvent{Object: &images.Items[i]}:
+		}
+	}
+}
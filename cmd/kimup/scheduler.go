@@ -3,12 +3,15 @@ package main
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/gookit/event"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 
 	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
@@ -18,20 +21,27 @@ import (
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/registry"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/tracing"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/crontab"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/utils"
 )
 
-type locks map[string]*sync.RWMutex
-
-func initScheduler(ctx context.Context, k kubeclient.Interface) {
-	l := make(locks)
+// initScheduler registers the listeners of the events.
+// The events of the Images and AlertConfigs not owned by the replica are ignored.
+func initScheduler(ctx context.Context, k kubeclient.Interface, shard *sharding.Sharder) {
+	// locks holds a mutex per image, the listeners of the events run concurrently
+	var locks sync.Map
 
 	// Start Crontab client
 	crontab.New(ctx)
 	// Add event lock
 	event.On(triggers.RefreshImage.String(), event.ListenerFunc(func(e event.Event) (err error) {
+		if !shard.IsOwner(imageKey(e.Data()["namespace"].(string), e.Data()["image"].(string))) {
+			return nil
+		}
+
 		// Increment the counter for the events
 		metrics.Events().TriggeredTotal.Inc()
 		// Start the timer for the event execution
@@ -43,18 +53,23 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 			imageName     = e.Data()["image"].(string)
 		)
 
-		if l[namespaceName+"/"+imageName] == nil {
-			l[namespaceName+"/"+imageName] = &sync.RWMutex{}
-		}
+		// The span of the refresh is a child of the span of the trigger, if any
+		ctx, span := tracing.Start(triggers.TraceContext(ctx, e), "RefreshImage", tracing.Image(namespaceName, imageName)...)
+		defer func() { tracing.End(span, err) }()
 
 		// Lock the image to prevent concurrent refreshes
-		l[namespaceName+"/"+imageName].Lock()
-		defer l[namespaceName+"/"+imageName].Unlock()
+		mu, _ := locks.LoadOrStore(namespaceName+"/"+imageName, &sync.Mutex{})
+		mu.(*sync.Mutex).Lock()
+		defer mu.(*sync.Mutex).Unlock()
 
 		// Sleep for 1 second to prevent concurrent refreshes
 		time.Sleep(1 * time.Second)
 
+		// ready is true if the rules and the actions of the refresh succeeded
+		var ready bool
+
 		retryErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			ready = false
 			log.Infof("Refreshing image %s in namespace %s", imageName, namespaceName)
 
 			ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
@@ -67,6 +82,14 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 
 				// update the status of the image
 				image.SetStatusTime(time.Now().Format(time.RFC3339))
+				if index, ok := e.Data()["trigger"].(int); ok && index < len(image.Spec.Triggers) && image.Spec.Triggers[index].Type == triggers.Crontab {
+					image.SetCrontabRun(image.Spec.Triggers[index].Value, time.Now())
+				}
+				if next, ok := crontab.NextImageRunTime(namespaceName, imageName); ok {
+					image.Status.NextScheduledSync = &metav1.Time{Time: next}
+				} else {
+					image.Status.NextScheduledSync = nil
+				}
 
 				// Need to get image again to avoid conflicts
 				imageRefreshed, err := k.Image().Get(ctx, namespaceName, imageName)
@@ -90,7 +113,7 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 			}()
 			if err != nil {
 				image.SetStatusResult(v1alpha1.ImageStatusLastSyncErrorGetImage)
-				if err := crontab.RemoveJob(crontab.BuildKey(namespaceName, imageName)); err != nil {
+				if err := crontab.RemoveImageJobs(namespaceName, imageName); err != nil {
 					return err
 				}
 				return err
@@ -104,8 +127,8 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 
 			if image.Spec.ImagePullSecrets != nil {
 				auths, err = k.GetPullSecretsForImage(ctx, image)
-				image.SetStatusResult(v1alpha1.ImageStatusLastSyncErrorPullSecrets)
 				if err != nil {
+					setTagsNotFetched(&image, v1alpha1.ImageStatusLastSyncErrorPullSecrets, err)
 					return err
 				}
 			}
@@ -134,7 +157,7 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 			timerRegistry.ObserveDuration()
 			if err != nil {
 				metrics.Registry().RequestErrorTotal.WithLabelValues(i.GetRegistry()).Inc()
-				image.SetStatusResult(v1alpha1.ImageStatusLastSyncErrorRegistry)
+				setTagsNotFetched(&image, v1alpha1.ImageStatusLastSyncErrorRegistry, err)
 				k.Image().Event(&image, corev1.EventTypeWarning, "Fetch image", fmt.Sprintf("Error fetching image: %v", err))
 				log.WithError(err).Error("Error fetching image")
 				return err
@@ -148,21 +171,47 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 			timerTags.ObserveDuration()
 			if err != nil {
 				metrics.Tags().RequestErrorTotal.Inc()
-				image.SetStatusResult(v1alpha1.ImageStatusLastSyncErrorTags)
+				setTagsNotFetched(&image, v1alpha1.ImageStatusLastSyncErrorTags, err)
 				k.Image().Event(&image, corev1.EventTypeWarning, "Fetch image tags", fmt.Sprintf("Error fetching tags: %v", err))
 				log.WithError(err).Error("Error fetching tags")
 				return err
 			}
 
+			// The tag pushed to the registry may not be listed yet by the registry.
+			// The tag is added to the available tags only if its manifest exists.
+			if hint, ok := e.Data()["tag"].(string); ok && hint != "" && !slices.Contains(tagsAvailable, hint) {
+				if err := re.HasTag(hint); err != nil {
+					log.WithError(err).Warnf("[RefreshImage] tag %s pushed to %s not found in the registry", hint, image.Spec.Image)
+				} else {
+					log.Debugf("[RefreshImage] tag %s pushed to %s added to the available tags", hint, image.Spec.Image)
+					tagsAvailable = append(tagsAvailable, hint)
+				}
+			}
+
 			metrics.Tags().AvailableSum.WithLabelValues(image.Spec.Image).Observe(float64(len(tagsAvailable)))
 			k.Image().Event(&image, corev1.EventTypeNormal, "Fetch image tags", fmt.Sprintf("Found %d tags", len(tagsAvailable)))
+			image.SetCondition(v1alpha1.ImageConditionTagsFetched, metav1.ConditionTrue, v1alpha1.ImageReasonSucceeded, fmt.Sprintf("Found %d tags", len(tagsAvailable)))
+			image.SetStatusLatestTag(tagsAvailable)
+
+			// The first errors of the rules and the actions are reported in the conditions
+			var ruleErr, actionErr *syncError
+			image.Status.Rules = nil
 
 			log.Debugf("[RefreshImage] %d tags available for %s", len(tagsAvailable), image.Spec.Image)
 
 			for _, rule := range image.Spec.Rules {
+				ruleStatus := v1alpha1.ImageStatusRule{
+					Name: rule.Name,
+					Type: string(rule.Type),
+					Time: metav1.Now(),
+				}
+
 				r, err := rules.GetRule(rule.Type)
 				if err != nil {
-					image.SetStatusResult(v1alpha1.ImageStatusLastSyncErrorGetRule)
+					ruleErr = ruleErr.orNew(v1alpha1.ImageStatusLastSyncErrorGetRule, fmt.Errorf("rule %s: %w", rule.Name, err))
+					ruleStatus.Result = v1alpha1.ImageRuleResultError
+					ruleStatus.Message = err.Error()
+					setRuleStatus(&image, ruleStatus)
 					log.Errorf("Error getting rule: %v", err)
 					continue
 				}
@@ -178,14 +227,23 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 				metrics.Rules().EvaluatedTotal.Inc()
 				timerRules := metrics.Rules().EvaluatedDuration.NewTimer()
 
+				_, ruleSpan := tracing.Start(ctx, "rules.Evaluate",
+					attribute.String("kimup.rule.name", rule.Name),
+					attribute.String("kimup.rule.type", string(rule.Type)),
+				)
 				match, newTag, err := r.Evaluate()
+				ruleSpan.SetAttributes(attribute.Bool("kimup.rule.match", match), attribute.String("kimup.rule.new_tag", newTag))
+				tracing.End(ruleSpan, err)
 
 				// Prometheus metrics - Observe the duration of the rule evaluation
 				timerRules.ObserveDuration()
 
 				if err != nil {
 					// Prometheus metrics - Increment the counter for the evaluated rule with error
-					image.SetStatusResult(v1alpha1.ImageStatusLastSyncError)
+					ruleErr = ruleErr.orNew(v1alpha1.ImageStatusLastSyncError, fmt.Errorf("rule %s: %w", rule.Name, err))
+					ruleStatus.Result = v1alpha1.ImageRuleResultError
+					ruleStatus.Message = err.Error()
+					setRuleStatus(&image, ruleStatus)
 					metrics.Rules().EvaluatedErrorTotal.Inc()
 					log.Errorf("Error evaluating rule: %v", err)
 					k.Image().Event(&image, corev1.EventTypeWarning, "Evaluate rule", fmt.Sprintf("Error evaluating rule %s: %v", rule.Type, err))
@@ -194,16 +252,39 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 
 				k.Image().Event(&image, corev1.EventTypeNormal, "Evaluate rule", fmt.Sprintf("Rule %s evaluated", rule.Type))
 
+				ruleStatus.Result = v1alpha1.ImageRuleResultNotMatched
+				if match {
+					ruleStatus.Result = v1alpha1.ImageRuleResultMatched
+					ruleStatus.NewTag = newTag
+				}
+
 				if match {
 					for _, action := range rule.Actions {
+						actionStatus := v1alpha1.ImageStatusAction{
+							Type: action.Type,
+							Time: metav1.Now(),
+						}
+
 						a, err := actions.GetActionWithUntypedName(action.Type)
 						if err != nil {
-							image.SetStatusResult(v1alpha1.ImageStatusLastSyncErrorAction)
+							actionErr = actionErr.orNew(v1alpha1.ImageStatusLastSyncErrorAction, fmt.Errorf("rule %s: action %s: %w", rule.Name, action.Type, err))
+							actionStatus.Result = v1alpha1.ImageActionResultFailed
+							actionStatus.Message = err.Error()
+							addActionStatus(&image, &ruleStatus, actionStatus)
 							k.Image().Event(&image, corev1.EventTypeWarning, "Get action", fmt.Sprintf("Error getting action %s: %v", action.Type, err))
 							log.Errorf("Error getting action: %v", err)
 							continue
 						}
 
+						// Do not send the same alert for the same tag several times
+						if a.GetName().IsAlert() && image.IsAlreadyNotified(rule.Name, action, newTag) {
+							log.Debugf("[RefreshImage] Alert %s already sent for tag %s, skipping", action.Type, newTag)
+							actionStatus.Result = v1alpha1.ImageActionResultSkipped
+							actionStatus.Message = fmt.Sprintf("Alert already sent for tag %s", newTag)
+							addActionStatus(&image, &ruleStatus, actionStatus)
+							continue
+						}
+
 						a.Init(k, models.Tags{
 							Actual:        tag,
 							New:           newTag,
@@ -214,33 +295,184 @@ func initScheduler(ctx context.Context, k kubeclient.Interface) {
 						metrics.Actions().ExecutedTotal.Inc()
 						timerActions := metrics.Actions().ExecutedDuration.NewTimer()
 
-						err = a.Execute(ctx)
+						actionCtx, actionSpan := tracing.Start(ctx, "actions.Execute",
+							attribute.String("kimup.rule.name", rule.Name),
+							attribute.String("kimup.action.type", action.Type),
+						)
+						err = a.Execute(actionCtx)
+						tracing.End(actionSpan, err)
 
 						// Prometheus metrics - Observe the duration of the action execution
-						timerActions.ObserveDuration()
+						actionStatus.Duration = metav1.Duration{Duration: timerActions.ObserveDuration().Round(time.Millisecond)}
 
 						if err != nil {
 							// Prometheus metrics - Increment the counter for the executed action with error
 							metrics.Actions().ExecutedErrorTotal.Inc()
-							image.SetStatusResult(v1alpha1.ImageStatusLastSyncError)
+							actionErr = actionErr.orNew(v1alpha1.ImageStatusLastSyncErrorAction, fmt.Errorf("rule %s: action %s: %w", rule.Name, action.Type, err))
+							actionStatus.Result = v1alpha1.ImageActionResultFailed
+							actionStatus.Message = err.Error()
+							addActionStatus(&image, &ruleStatus, actionStatus)
 							log.Errorf("Error executing action(%s): %v", action.Type, err)
 							k.Image().Event(&image, corev1.EventTypeWarning, "Execute action", fmt.Sprintf("Error executing action %s: %v", action.Type, err))
 							continue
 						}
+						if a.GetName().IsAlert() {
+							image.SetNotified(rule.Name, action, newTag)
+						}
+						actionStatus.Result = v1alpha1.ImageActionResultSucceeded
+						addActionStatus(&image, &ruleStatus, actionStatus)
 						k.Image().Event(&image, corev1.EventTypeNormal, "Execute action", fmt.Sprintf("Action %s executed", action.Type))
 					}
 					log.Debugf("[RefreshImage] Rule %s evaluated: %v -> %s", rule.Type, tag, newTag)
 				}
-			}
 
-			if image.Status.Result == v1alpha1.ImageStatusLastSyncScheduled {
-				image.SetStatusResult(v1alpha1.ImageStatusLastSyncSuccess)
+				setRuleStatus(&image, ruleStatus)
 			}
+
+			ruleErr.setCondition(&image, v1alpha1.ImageConditionRulesEvaluated)
+			actionErr.setCondition(&image, v1alpha1.ImageConditionActionsSucceeded)
+			setSyncResult(&image)
+			observeImage(&image, tagsAvailable)
+			ready = image.IsReady()
+
 			return k.Image().Update(ctx, image)
 		})
 
-		// Prometheus metrics - Increment the counter for the events evaluated with error
-		metrics.Events().TriggerdErrorTotal.Inc()
+		result := metrics.ResultSucceeded
+		if retryErr != nil {
+			// Prometheus metrics - Increment the counter for the events evaluated with error
+			metrics.Events().TriggerdErrorTotal.Inc()
+		}
+		if retryErr != nil || !ready {
+			result = metrics.ResultFailed
+		}
+		metrics.Events().RefreshTotal.WithLabelValues(
+			metrics.Label(metrics.LabelNamespace, namespaceName),
+			metrics.Label(metrics.LabelImage, imageName),
+			result,
+		).Inc()
+
 		return retryErr
 	}), event.Normal)
+
+	// Send the digest of the alert configurations
+	event.On(triggers.SendDigest.String(), event.ListenerFunc(func(e event.Event) (err error) {
+		var (
+			namespaceName   = e.Data()["namespace"].(string)
+			alertConfigName = e.Data()["alertConfig"].(string)
+		)
+
+		if !shard.IsOwner(digestKey(namespaceName, alertConfigName)) {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+
+		if err := actions.SendDigest(ctx, k, namespaceName, alertConfigName); err != nil {
+			log.WithError(err).
+				WithFields(log.Fields{
+					"Namespace":   namespaceName,
+					"AlertConfig": alertConfigName,
+				}).Error("Error sending digest")
+			return err
+		}
+
+		return nil
+	}), event.Normal)
+}
+
+// syncError is the first error of the rules or the actions of a sync.
+type syncError struct {
+	reason v1alpha1.ImageStatusLastSync
+	err    error
+}
+
+// orNew returns the error if it is already set, otherwise a new error.
+func (e *syncError) orNew(reason v1alpha1.ImageStatusLastSync, err error) *syncError {
+	if e != nil {
+		return e
+	}
+
+	return &syncError{reason: reason, err: err}
+}
+
+// setCondition sets the condition to true if there is no error.
+func (e *syncError) setCondition(image *v1alpha1.Image, conditionType string) {
+	if e == nil {
+		image.SetCondition(conditionType, metav1.ConditionTrue, v1alpha1.ImageReasonSucceeded, "")
+		return
+	}
+
+	image.SetCondition(conditionType, metav1.ConditionFalse, string(e.reason), e.err.Error())
+}
+
+// setTagsNotFetched sets the conditions when the tags can not be fetched from the registry.
+// The rules and the actions are not evaluated.
+func setTagsNotFetched(image *v1alpha1.Image, reason v1alpha1.ImageStatusLastSync, err error) {
+	image.SetCondition(v1alpha1.ImageConditionTagsFetched, metav1.ConditionFalse, string(reason), err.Error())
+	image.SetCondition(v1alpha1.ImageConditionRulesEvaluated, metav1.ConditionUnknown, v1alpha1.ImageReasonTagsNotFetched, "")
+	image.SetCondition(v1alpha1.ImageConditionActionsSucceeded, metav1.ConditionUnknown, v1alpha1.ImageReasonTagsNotFetched, "")
+	setSyncResult(image)
+}
+
+// setRuleStatus sets the result of the rule and counts it in the metrics.
+func setRuleStatus(image *v1alpha1.Image, status v1alpha1.ImageStatusRule) {
+	image.SetRuleStatus(status)
+	metrics.Rules().ResultTotal.WithLabelValues(
+		metrics.Label(metrics.LabelNamespace, image.Namespace),
+		metrics.Label(metrics.LabelImage, image.Name),
+		metrics.Label(metrics.LabelRule, status.Name),
+		string(status.Result),
+	).Inc()
+}
+
+// addActionStatus adds the result of the action to the rule and counts it in the metrics.
+func addActionStatus(image *v1alpha1.Image, rule *v1alpha1.ImageStatusRule, status v1alpha1.ImageStatusAction) {
+	rule.AddActionStatus(status)
+	metrics.Actions().ResultTotal.WithLabelValues(
+		metrics.Label(metrics.LabelNamespace, image.Namespace),
+		metrics.Label(metrics.LabelImage, image.Name),
+		metrics.Label(metrics.LabelRule, rule.Name),
+		metrics.Label(metrics.LabelAction, status.Type),
+		string(status.Result),
+	).Inc()
+}
+
+// observeImage sets the lag of the image in its status and in the metrics.
+func observeImage(image *v1alpha1.Image, tags []string) {
+	behind := image.SetStatusVersionsBehind(tags)
+
+	var newerSince, lastSuccess *time.Time
+	if image.Status.NewerTagSince != nil {
+		newerSince = &image.Status.NewerTagSince.Time
+	}
+	if image.Status.LastSuccessfulSync != nil {
+		lastSuccess = &image.Status.LastSuccessfulSync.Time
+	}
+	metrics.ObserveImage(image.Namespace, image.Name, behind, newerSince, lastSuccess)
+}
+
+// setSyncResult sets the Ready condition, the result and the observed generation of the sync.
+func setSyncResult(image *v1alpha1.Image) {
+	image.SetReadyCondition()
+	image.Status.ObservedGeneration = image.Generation
+
+	if image.IsReady() {
+		image.SetStatusResult(v1alpha1.ImageStatusLastSyncSuccess)
+		image.Status.LastSuccessfulSync = &metav1.Time{Time: time.Now()}
+		return
+	}
+
+	image.SetStatusResult(v1alpha1.ImageStatusLastSync(image.GetCondition(v1alpha1.ImageConditionReady).Reason))
+}
+
+// imageKey returns the sharding key of the Image.
+func imageKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// digestKey returns the sharding key of the digest of the AlertConfig.
+func digestKey(namespace, name string) string {
+	return "digest/" + namespace + "/" + name
 }
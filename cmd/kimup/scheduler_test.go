@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/event"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/metrics"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+func TestScheduler_RefreshResult(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The registry lists the tags of the demo image
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case strings.HasSuffix(r.URL.Path, "/tags/list"):
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "demo", "tags": []string{"v0.0.1", "v0.0.2"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	k := fakekubeclient.NewFakeKubeClient().WithDynamicObjects()
+	initScheduler(ctx, k, nil)
+
+	tests := []struct {
+		name   string
+		action string
+		want   string
+	}{
+		{name: "succeeded", action: "apply", want: metrics.ResultSucceeded},
+		{name: "action-failed", action: "unknown", want: metrics.ResultFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, k.CreateFakeImage(v1alpha1.Image{
+				TypeMeta:   metav1.TypeMeta{Kind: "Image", APIVersion: v1alpha1.GroupVersion.String()},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: tt.name},
+				Spec: v1alpha1.ImageSpec{
+					Image:                 strings.TrimPrefix(ts.URL, "https://") + "/demo",
+					BaseTag:               "v0.0.1",
+					InsecureSkipTLSVerify: true,
+					Rules: []v1alpha1.ImageRule{{
+						Name:    "always",
+						Type:    rules.Always,
+						Actions: []v1alpha1.ImageAction{{Type: tt.action}},
+					}},
+				},
+			}))
+
+			// The refresh succeeds even if an action fails, the image is not ready
+			err, _ := event.Fire(triggers.RefreshImage.String(), event.M{"namespace": "default", "image": tt.name})
+			require.NoError(t, err)
+
+			for _, result := range []string{metrics.ResultSucceeded, metrics.ResultFailed} {
+				want := 0.0
+				if result == tt.want {
+					want = 1
+				}
+				assert.Equal(t, want, testutil.ToFloat64(metrics.Events().RefreshTotal.WithLabelValues("default", tt.name, result)), result)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+)
+
+var approveCommand = command{
+	name:        "approve",
+	usage:       "NAME",
+	description: "Approve the pending request of an Image (not supported yet)",
+	run:         runApprove,
+}
+
+// errApproveNotSupported is returned by approve, as the REST API returns 501 on /approve.
+var errApproveNotSupported = errors.New("approve is not supported yet: the request-approval action does not create requests")
+
+func runApprove(_ context.Context, _ *options, fs *flag.FlagSet, args []string) error {
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactArgs(fs, args, 1); err != nil {
+		return err
+	}
+
+	return errApproveNotSupported
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"k8s.io/client-go/util/retry"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+)
+
+var (
+	historyCommand = command{
+		name:        "history",
+		usage:       "NAME",
+		description: "Show the last tags applied to an Image",
+		run:         runHistory,
+	}
+
+	rollbackCommand = command{
+		name:        "rollback",
+		usage:       "NAME [--to TAG]",
+		description: "Apply a previous tag of the history to an Image",
+		run:         runRollback,
+	}
+)
+
+func runHistory(ctx context.Context, o *options, fs *flag.FlagSet, args []string) error {
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactArgs(fs, args, 1); err != nil {
+		return err
+	}
+
+	k, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	image, err := k.Image().Get(ctx, namespace, args[0])
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "TAG\tAPPLIED\tCURRENT")
+	for _, h := range image.Status.History {
+		current := ""
+		if h.Tag == image.Status.Tag {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", h.Tag, h.Time.Format("2006-01-02 15:04:05"), current)
+	}
+
+	return w.Flush()
+}
+
+func runRollback(ctx context.Context, o *options, fs *flag.FlagSet, args []string) error {
+	to := fs.String("to", "", "Tag of the history to apply. The previous tag is used if empty.")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactArgs(fs, args, 1); err != nil {
+		return err
+	}
+
+	k, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	image, err := k.Image().Get(ctx, namespace, args[0])
+	if err != nil {
+		return err
+	}
+
+	tag, err := rollbackTag(image, *to)
+	if err != nil {
+		return err
+	}
+
+	// The tag annotation is used by the mutator, the status tag by the rules of the next sync
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		image, err := k.Image().Get(ctx, namespace, args[0])
+		if err != nil {
+			return err
+		}
+
+		if image.Annotations == nil {
+			image.Annotations = map[string]string{}
+		}
+		image.Annotations[string(annotations.KeyTag)] = tag
+
+		return k.Image().Update(ctx, image)
+	}); err != nil {
+		return err
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		image, err := k.Image().Get(ctx, namespace, args[0])
+		if err != nil {
+			return err
+		}
+
+		image.SetStatusTag(tag)
+		image.AddHistory(tag)
+
+		return k.Image().UpdateStatus(ctx, image)
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.out, "image.kimup.cloudavenue.io/%s rolled back to %s\n", image.Name, tag)
+
+	// The Image is not pinned, the rules of the next sync can apply a newer tag again
+	if len(image.Spec.Triggers) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: the rules of the Image are evaluated from %s at the next sync and a rule matching a newer tag applies it again, change the rules or remove the triggers of the Image to keep %s\n", tag, tag)
+	}
+
+	return nil
+}
+
+// rollbackTag returns the tag of the history to apply.
+func rollbackTag(image v1alpha1.Image, to string) (string, error) {
+	if to != "" {
+		if !slices.ContainsFunc(image.Status.History, func(h v1alpha1.ImageStatusHistory) bool { return h.Tag == to }) {
+			return "", fmt.Errorf("tag %s is not in the history of the image %s", to, image.Name)
+		}
+		if to == image.Status.Tag {
+			return "", fmt.Errorf("tag %s is already the tag of the image %s", to, image.Name)
+		}
+		return to, nil
+	}
+
+	for _, h := range image.Status.History {
+		if h.Tag != image.Status.Tag {
+			return h.Tag, nil
+		}
+	}
+
+	return "", fmt.Errorf("no previous tag in the history of the image %s", image.Name)
+}
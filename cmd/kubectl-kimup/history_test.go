@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+)
+
+func TestRollbackTag(t *testing.T) {
+	newImage := func(tag string, history ...string) v1alpha1.Image {
+		image := v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Name: "demo"}}
+		image.Status.Tag = tag
+		for _, h := range history {
+			image.Status.History = append(image.Status.History, v1alpha1.ImageStatusHistory{Tag: h})
+		}
+		return image
+	}
+
+	tests := []struct {
+		name    string
+		image   v1alpha1.Image
+		to      string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "Previous tag",
+			image: newImage("v1.2.0", "v1.2.0", "v1.1.0", "v1.0.0"),
+			want:  "v1.1.0",
+		},
+		{
+			name:  "Previous tag when the current tag is not the last of the history",
+			image: newImage("v1.1.0", "v1.2.0", "v1.1.0"),
+			want:  "v1.2.0",
+		},
+		{
+			name:  "Tag of the history",
+			image: newImage("v1.2.0", "v1.2.0", "v1.1.0", "v1.0.0"),
+			to:    "v1.0.0",
+			want:  "v1.0.0",
+		},
+		{
+			name:    "Tag not in the history",
+			image:   newImage("v1.2.0", "v1.2.0", "v1.1.0"),
+			to:      "v0.9.0",
+			wantErr: "tag v0.9.0 is not in the history of the image demo",
+		},
+		{
+			name:    "Tag already applied",
+			image:   newImage("v1.2.0", "v1.2.0", "v1.1.0"),
+			to:      "v1.2.0",
+			wantErr: "tag v1.2.0 is already the tag of the image demo",
+		},
+		{
+			name:    "No previous tag",
+			image:   newImage("v1.2.0", "v1.2.0"),
+			wantErr: "no previous tag in the history of the image demo",
+		},
+		{
+			name:    "Empty history",
+			image:   newImage("v1.2.0"),
+			wantErr: "no previous tag in the history of the image demo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rollbackTag(tt.image, tt.to)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
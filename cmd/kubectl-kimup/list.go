@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+)
+
+var listCommand = command{
+	name:        "list",
+	usage:       "[-A]",
+	description: "List the Images with their current and latest tags and the result of their last sync",
+	run:         runList,
+}
+
+func runList(ctx context.Context, o *options, fs *flag.FlagSet, args []string) error {
+	allNamespaces := fs.Bool("A", false, "List the Images of all the namespaces.")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	k, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	var images v1alpha1.ImageList
+	if *allNamespaces {
+		images, err = k.Image().ListAll(ctx, metav1.ListOptions{})
+	} else {
+		images, err = k.Image().List(ctx, namespace, metav1.ListOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.out, 0, 0, 3, ' ', 0)
+	if *allNamespaces {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tIMAGE\tTAG\tLATEST\tRESULT\tLAST-SYNC")
+	for _, image := range images.Items {
+		if *allNamespaces {
+			fmt.Fprintf(w, "%s\t", image.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			image.Name,
+			image.Spec.Image,
+			orNone(image.Status.Tag),
+			orNone(image.Status.LatestTag),
+			orNone(string(image.Status.Result)),
+			age(image.Status.Time),
+		)
+	}
+
+	return w.Flush()
+}
+
+// age returns the time elapsed since the RFC3339 time.
+func age(t string) string {
+	parsed, err := time.Parse(time.RFC3339, t)
+	if err != nil {
+		return "<none>"
+	}
+
+	return duration.HumanDuration(time.Since(parsed))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+
+	return s
+}
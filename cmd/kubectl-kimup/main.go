@@ -0,0 +1,174 @@
+// kubectl-kimup inspects and drives the Images of kimup.
+// It is installed as a kubectl plugin and used as `kubectl kimup <command>`.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+)
+
+type (
+	// command is a subcommand of the plugin.
+	command struct {
+		name        string
+		usage       string
+		description string
+		run         func(ctx context.Context, o *options, fs *flag.FlagSet, args []string) error
+	}
+
+	// options are the flags shared by the commands.
+	options struct {
+		kubeconfig string
+		context    string
+		namespace  string
+		out        io.Writer
+	}
+)
+
+var commands = []command{
+	listCommand,
+	refreshCommand,
+	planCommand,
+	historyCommand,
+	rollbackCommand,
+	approveCommand,
+	testRuleCommand,
+	simulateCommand,
+}
+
+func main() {
+	// The logs of the internal packages are not useful for the user of the plugin
+	log.GetLogger().SetLevel(logrus.WarnLevel)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	err := run(ctx, os.Args[1:])
+	cancel()
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(os.Stdout)
+		return nil
+	}
+
+	if args[0] == "version" {
+		fmt.Fprintln(os.Stdout, models.Version)
+		return nil
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			o := &options{out: os.Stdout}
+			return c.run(ctx, o, o.flags(c), args[1:])
+		}
+	}
+
+	usage(os.Stderr)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Inspect and drive the kimup Images.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  kubectl kimup <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.description)
+	}
+	fmt.Fprintf(w, "  %-10s %s\n", "version", "Print the version of the plugin")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Use \"kubectl kimup <command> -h\" for more information about a command.")
+}
+
+// flags returns the flag set of the command with the flags shared by the commands.
+func (o *options) flags(c command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. The default loading rules of kubectl are used if empty.")
+	fs.StringVar(&o.context, "context", "", "Name of the kubeconfig context to use.")
+	fs.StringVar(&o.namespace, "namespace", "", "Namespace of the Images. The namespace of the context is used if empty.")
+	fs.StringVar(&o.namespace, "n", "", "Shorthand for --namespace.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "%s\n\nUsage:\n  kubectl kimup %s %s\n\nFlags:\n", c.description, c.name, c.usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parse parses the flags, which can be set before or after the arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// client returns the kubernetes client and the namespace of the Images.
+func (o *options) client() (kubeclient.Interface, string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.kubeconfig
+
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{
+		CurrentContext: o.context,
+	})
+
+	restConfig, err := config.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	namespace := o.namespace
+	if namespace == "" {
+		if namespace, _, err = config.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	k, err := kubeclient.NewFromRestConfig(restConfig, kubeclient.ComponentCLI)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return k, namespace, nil
+}
+
+// exactArgs returns an error if the number of arguments is not n.
+func exactArgs(fs *flag.FlagSet, args []string, n int) error {
+	if len(args) != n {
+		fs.Usage()
+		return fmt.Errorf("%s expects %d argument(s), got %d", fs.Name(), n, len(args))
+	}
+
+	return nil
+}
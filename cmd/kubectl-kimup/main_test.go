@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      []string
+		wantTo    string
+		wantNs    string
+		wantError bool
+	}{
+		{
+			name: "Arguments only",
+			args: []string{"demo", "other"},
+			want: []string{"demo", "other"},
+		},
+		{
+			name:   "Flags before the arguments",
+			args:   []string{"--to", "v1.0.0", "-n", "apps", "demo"},
+			want:   []string{"demo"},
+			wantTo: "v1.0.0",
+			wantNs: "apps",
+		},
+		{
+			name:   "Flags after the arguments",
+			args:   []string{"demo", "--to=v1.0.0", "--namespace", "apps"},
+			want:   []string{"demo"},
+			wantTo: "v1.0.0",
+			wantNs: "apps",
+		},
+		{
+			name:   "Flags between the arguments",
+			args:   []string{"demo", "--to", "v1.0.0", "other"},
+			want:   []string{"demo", "other"},
+			wantTo: "v1.0.0",
+		},
+		{
+			name: "No arguments",
+			args: []string{},
+			want: []string{},
+		},
+		{
+			name:      "Unknown flag",
+			args:      []string{"demo", "--unknown"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &options{}
+			fs := o.flags(rollbackCommand)
+			fs.SetOutput(io.Discard)
+			to := fs.String("to", "", "")
+
+			got, err := parse(fs, tt.args)
+			if tt.wantError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTo, *to)
+			assert.Equal(t, tt.wantNs, o.namespace)
+		})
+	}
+}
+
+func TestApprove(t *testing.T) {
+	o := &options{}
+	fs := o.flags(approveCommand)
+	fs.SetOutput(io.Discard)
+
+	err := runApprove(context.Background(), o, fs, []string{"demo"})
+	require.ErrorIs(t, err, errApproveNotSupported)
+
+	// The Image is required
+	err = runApprove(context.Background(), o, fs, []string{})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errApproveNotSupported)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/registry"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/utils"
+)
+
+var planCommand = command{
+	name:        "plan",
+	usage:       "NAME",
+	description: "Show the result of the rules of an Image against the tags of the registry without executing the actions",
+	run:         runPlan,
+}
+
+func runPlan(ctx context.Context, o *options, fs *flag.FlagSet, args []string) error {
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactArgs(fs, args, 1); err != nil {
+		return err
+	}
+
+	k, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	image, err := k.Image().Get(ctx, namespace, args[0])
+	if err != nil {
+		return err
+	}
+
+	tags, err := fetchTags(ctx, k, image)
+	if err != nil {
+		return err
+	}
+
+	tag := image.Status.Tag
+	if tag == "" {
+		tag = image.Spec.BaseTag
+	}
+
+	fmt.Fprintf(o.out, "Image:  %s\nTag:    %s\nLatest: %s\nTags:   %d\n\n", image.Spec.Image, tag, orNone(v1alpha1.LatestTag(tags)), len(tags))
+
+	w := tabwriter.NewWriter(o.out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "RULE\tTYPE\tRESULT\tNEW-TAG\tACTIONS")
+	for _, rule := range image.Spec.Rules {
+		result, newTag, actions := v1alpha1.ImageRuleResultNotMatched, "", "<none>"
+
+		match, t, err := rules.Evaluate(rule.Type, tag, tags, rule.Value)
+		switch {
+		case err != nil:
+			result, newTag = v1alpha1.ImageRuleResultError, err.Error()
+		case match:
+			result, newTag, actions = v1alpha1.ImageRuleResultMatched, t, actionTypes(rule.Actions)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", rule.Name, rule.Type, result, orNone(newTag), actions)
+	}
+
+	return w.Flush()
+}
+
+// fetchTags returns the tags of the image in the registry with the pull secrets of the Image.
+func fetchTags(ctx context.Context, k kubeclient.Interface, image v1alpha1.Image) ([]string, error) {
+	var auths kubeclient.K8sDockerRegistrySecretData
+	if image.Spec.ImagePullSecrets != nil {
+		var err error
+		if auths, err = k.GetPullSecretsForImage(ctx, image); err != nil {
+			return nil, err
+		}
+	}
+
+	auth := auths.Auths[utils.ImageParser(image.Spec.Image).GetRegistry()]
+	re, err := registry.New(ctx, image.Spec.Image, registry.Settings{
+		InsecureTLS: image.Spec.InsecureSkipTLSVerify,
+		Username:    auth.Username,
+		Password:    auth.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return re.Tags()
+}
+
+// actionTypes returns the types of the actions, separated by commas.
+func actionTypes(actions []v1alpha1.ImageAction) string {
+	types := make([]string, 0, len(actions))
+	for _, a := range actions {
+		types = append(types, a.Type)
+	}
+
+	return strings.Join(types, ",")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"k8s.io/client-go/util/retry"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+)
+
+var refreshCommand = command{
+	name:        "refresh",
+	usage:       "NAME...",
+	description: "Refresh the Images by setting the refresh action annotation",
+	run:         runRefresh,
+}
+
+func runRefresh(ctx context.Context, o *options, fs *flag.FlagSet, args []string) error {
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return fmt.Errorf("refresh expects at least one Image")
+	}
+
+	k, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range args {
+		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			image, err := k.Image().Get(ctx, namespace, name)
+			if err != nil {
+				return err
+			}
+
+			if image.Annotations == nil {
+				image.Annotations = map[string]string{}
+			}
+			image.Annotations[string(annotations.KeyAction)] = string(annotations.ActionRefresh)
+
+			return k.Image().Update(ctx, image)
+		}); err != nil {
+			return fmt.Errorf("image %s: %w", name, err)
+		}
+
+		fmt.Fprintf(o.out, "image.kimup.cloudavenue.io/%s refresh requested\n", name)
+	}
+
+	return nil
+}
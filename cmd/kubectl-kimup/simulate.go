@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/registry"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/simulate"
+)
+
+var simulateCommand = command{
+	name:        "simulate",
+	usage:       "(-f FILE | --image IMAGE --rule TYPE[=VALUE]...) [--tag TAG] [--tags-file FILE] [-o text|json] [-v]",
+	description: "Simulate the rules of Images against the tags of the registry or of a file, without cluster",
+	run:         runSimulate,
+}
+
+// ruleFlags are the rules set with the --rule flag.
+type ruleFlags []v1alpha1.ImageRule
+
+func (r *ruleFlags) String() string {
+	return fmt.Sprint(*r)
+}
+
+func (r *ruleFlags) Set(s string) error {
+	ruleType, value, _ := strings.Cut(s, "=")
+	name, err := rules.ParseRuleName(ruleType)
+	if err != nil {
+		return fmt.Errorf("rule %s: %w", ruleType, err)
+	}
+
+	*r = append(*r, v1alpha1.ImageRule{
+		Name:  fmt.Sprintf("%s-%d", name, len(*r)),
+		Type:  name,
+		Value: value,
+		// The apply action is used to chain the rules as the scheduler does
+		Actions: []v1alpha1.ImageAction{{Type: "apply"}},
+	})
+	return nil
+}
+
+func runSimulate(ctx context.Context, o *options, fs *flag.FlagSet, args []string) error {
+	var ruleFlags ruleFlags
+	file := fs.String("f", "", "File containing the Image manifests. Use - for the standard input.")
+	imageName := fs.String("image", "", "Image to simulate when no manifest is used (e.g. ghcr.io/traefik/whoami).")
+	fs.Var(&ruleFlags, "rule", "Rule to simulate with --image, as TYPE or TYPE=VALUE. Can be repeated.")
+	tag := fs.String("tag", "", "Current tag of the image. The tag of the status or the base tag of the Image is used if empty.")
+	tagsFile := fs.String("tags-file", "", "File containing the available tags, one per line. The tags are fetched from the registry if empty.")
+	output := fs.String("o", "text", "Output format: text or json.")
+	verbose := fs.Bool("v", false, "Show the tags rejected by each rule in the text output.")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	var images []v1alpha1.Image
+	switch {
+	case *file != "" && *imageName != "":
+		return errors.New("-f and --image are mutually exclusive")
+	case *file != "":
+		if images, err = readImages(*file); err != nil {
+			return err
+		}
+	case *imageName != "":
+		if len(ruleFlags) == 0 {
+			return errors.New("--image requires at least one --rule")
+		}
+		images = []v1alpha1.Image{{Spec: v1alpha1.ImageSpec{Image: *imageName, BaseTag: *tag, Rules: ruleFlags}}}
+		images[0].Name = *imageName
+	default:
+		fs.Usage()
+		return errors.New("-f or --image is required")
+	}
+
+	var fileTags []string
+	if *tagsFile != "" {
+		if fileTags, err = readTags(*tagsFile); err != nil {
+			return err
+		}
+	}
+
+	results := []simulate.Result{}
+	failed := false
+	for _, image := range images {
+		if *tag != "" {
+			image.Status.Tag = *tag
+		}
+
+		tags := fileTags
+		if *tagsFile == "" {
+			if tags, err = registryTags(ctx, image); err != nil {
+				return fmt.Errorf("image %s: %w", image.Name, err)
+			}
+		}
+
+		result := simulate.Simulate(image, tags)
+		failed = failed || result.HasErrors()
+		results = append(results, result)
+
+		if *output == "text" {
+			printSimulation(o.out, image.Name, result, *verbose)
+		}
+	}
+
+	if *output == "json" {
+		e := json.NewEncoder(o.out)
+		e.SetIndent("", "  ")
+		if err := e.Encode(results); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return errors.New("at least one rule can not be evaluated")
+	}
+
+	return nil
+}
+
+// readImages returns the Images of the YAML or JSON documents of the file.
+// The documents of the other kinds are ignored.
+func readImages(path string) ([]v1alpha1.Image, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	images := []v1alpha1.Image{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var image v1alpha1.Image
+		if err := decoder.Decode(&image); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if image.Kind != "Image" {
+			continue
+		}
+		images = append(images, image)
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no Image found in %s", path)
+	}
+
+	return images, nil
+}
+
+// registryTags returns the tags of the image fetched anonymously from the registry.
+func registryTags(ctx context.Context, image v1alpha1.Image) ([]string, error) {
+	re, err := registry.New(ctx, image.Spec.Image, registry.Settings{
+		InsecureTLS: image.Spec.InsecureSkipTLSVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return re.Tags()
+}
+
+// printSimulation prints the result of the simulation in a human readable form.
+func printSimulation(w io.Writer, name string, result simulate.Result, verbose bool) {
+	fmt.Fprintf(w, "Image %s (%s)\nTag:  %s\nTags: %d\n\n", name, result.Image, result.Tag, result.Tags)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tTYPE\tTAG\tRESULT\tNEW-TAG\tACTIONS")
+	for _, r := range result.Rules {
+		newTag := r.NewTag
+		if r.Error != "" {
+			newTag = r.Error
+		}
+		actions := "<none>"
+		if len(r.Actions) > 0 {
+			actions = strings.Join(r.Actions, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Name, r.Type, r.Tag, r.Result, orNone(newTag), actions)
+	}
+	_ = tw.Flush()
+
+	if verbose {
+		for _, r := range result.Rules {
+			if len(r.Rejected) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "\nRejected by %s:\n", r.Name)
+			tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+			for _, rejection := range r.Rejected {
+				fmt.Fprintf(tw, "  %s\t%s\n", rejection.Tag, rejection.Reason)
+			}
+			_ = tw.Flush()
+		}
+	}
+
+	if result.NewTag != "" {
+		fmt.Fprintf(w, "\n=> %s would be updated from %s to %s\n\n", name, result.Tag, result.NewTag)
+		return
+	}
+	fmt.Fprintf(w, "\n=> %s would keep %s\n\n", name, result.Tag)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+)
+
+var testRuleCommand = command{
+	name:        "test-rule",
+	usage:       "--type TYPE [--value VALUE] --tag TAG [TAGS...]",
+	description: "Evaluate a rule locally against a list of tags",
+	run:         runTestRule,
+}
+
+func runTestRule(_ context.Context, o *options, fs *flag.FlagSet, args []string) error {
+	ruleType := fs.String("type", "", "Type of the rule (e.g. semver-minor).")
+	value := fs.String("value", "", "Value of the rule (e.g. the expression of the regex rule).")
+	tag := fs.String("tag", "", "Current tag of the image.")
+	tagsFile := fs.String("tags-file", "", "File containing the available tags, one per line. Use - for the standard input.")
+	tags, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *tagsFile != "" {
+		fileTags, err := readTags(*tagsFile)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, fileTags...)
+	}
+
+	if *ruleType == "" || *tag == "" {
+		fs.Usage()
+		return fmt.Errorf("--type and --tag are required")
+	}
+
+	name, err := rules.ParseRuleName(*ruleType)
+	if err != nil {
+		return fmt.Errorf("rule %s: %w", *ruleType, err)
+	}
+
+	match, newTag, err := rules.Evaluate(name, *tag, tags, *value)
+	if err != nil {
+		return err
+	}
+
+	if !match {
+		fmt.Fprintf(o.out, "Rule %s does not match: %s is kept\n", name, *tag)
+		return nil
+	}
+
+	fmt.Fprintf(o.out, "Rule %s matches: %s -> %s\n", name, *tag, newTag)
+	return nil
+}
+
+// readTags returns the non-empty lines of the file.
+func readTags(path string) ([]string, error) {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+
+	tags := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if t := strings.TrimSpace(scanner.Text()); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	return tags, scanner.Err()
+}
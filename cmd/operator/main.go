@@ -41,6 +41,7 @@ import (
 	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/metrics"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/tracing"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -110,8 +111,22 @@ func main() {
 		c <- syscall.SIGINT
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// * Export the traces
+	shutdownTracing, err := tracing.Init(ctx, "kimup-operator")
+	if err != nil {
+		log.WithError(err).Panic("Error setting up the tracing")
+	}
+
 	// ! Mutator
 
+	if err := controller.SetupImageIndexWithManager(ctx, mgr); err != nil {
+		log.WithError(err).Error("unable to create index", "index", controller.ImageSpecImageIndex)
+		c <- syscall.SIGINT
+	}
+
 	if err := (&controller.ImageTagMutator{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
@@ -121,6 +136,32 @@ func main() {
 		c <- syscall.SIGINT
 	}
 
+	// ! Validators
+
+	if err := (&controller.ImageValidator{
+		KubeAPIClient: kubeAPIClient,
+	}).SetupWebhookWithManager(mgr); err != nil {
+		log.WithError(err).Error("unable to create webhook", "webhook", "ImageValidator")
+		c <- syscall.SIGINT
+	}
+
+	if err := (&controller.ClusterImageValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		log.WithError(err).Error("unable to create webhook", "webhook", "ClusterImageValidator")
+		c <- syscall.SIGINT
+	}
+
+	if err := (&controller.AlertConfigValidator{
+		KubeAPIClient: kubeAPIClient,
+	}).SetupWebhookWithManager(mgr); err != nil {
+		log.WithError(err).Error("unable to create webhook", "webhook", "AlertConfigValidator")
+		c <- syscall.SIGINT
+	}
+
+	if err := (&controller.KimupValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		log.WithError(err).Error("unable to create webhook", "webhook", "KimupValidator")
+		c <- syscall.SIGINT
+	}
+
 	// ! Reconcilers
 
 	if err = (&controller.ImageReconciler{
@@ -133,6 +174,15 @@ func main() {
 		c <- syscall.SIGINT
 	}
 
+	if err = (&controller.ClusterImageReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("kimup-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		log.WithError(err).Error("unable to create controller", "controller", "ClusterImage")
+		c <- syscall.SIGINT
+	}
+
 	if err = (&controller.KimupReconciler{
 		Client:        mgr.GetClient(),
 		KubeAPIClient: kubeAPIClient,
@@ -153,10 +203,26 @@ func main() {
 		c <- syscall.SIGINT
 	}
 
-	// +kubebuilder:scaffold:builder
+	if err = (&controller.DiscoveryReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("kimup-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		log.WithError(err).Error("unable to create controller", "controller", "Discovery")
+		c <- syscall.SIGINT
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	if err = (&controller.AlertConfigReconciler{
+		Client:        mgr.GetClient(),
+		KubeAPIClient: kubeAPIClient,
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("kimup-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		log.WithError(err).Error(err, "unable to create controller", "controller", "AlertConfig")
+		c <- syscall.SIGINT
+	}
+
+	// +kubebuilder:scaffold:builder
 
 	// * Config the metrics and healthz server
 	a, waitHTTP := httpserver.Init(ctx, httpserver.DisableHealth())
@@ -184,5 +250,8 @@ func main() {
 
 	<-c
 	cancel()
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.WithError(err).Error("Failed to flush the traces")
+	}
 	waitHTTP()
 }
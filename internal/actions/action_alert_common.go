@@ -1,10 +1,28 @@
 package actions
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
 	s "github.com/containrrr/shoutrrr"
 	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
 
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+type (
+	// shoutrrrAlert is an alert sent with the shoutrrr URL built from its section of the AlertConfig.
+	shoutrrrAlert interface {
+		GetName() models.ActionName
+		ConfigValidation() error
+		Render() (string, error)
+		constructURL(ctx context.Context) (string, error)
+	}
 )
 
 func newAlertSender(url string) (*router.ServiceRouter, error) {
@@ -17,3 +35,141 @@ func newAlertSender(url string) (*router.ServiceRouter, error) {
 
 	return sender, nil
 }
+
+// getAlertConfig retrieves the AlertConfig referenced by the action data.
+func (a *action) getAlertConfig(ctx context.Context) (v1alpha1.AlertConfig, error) {
+	alertConfig, err := a.k.GetValueOrValueFrom(ctx, a.image.Namespace, a.data)
+	if err != nil {
+		return v1alpha1.AlertConfig{}, err
+	}
+
+	aC, ok := alertConfig.(v1alpha1.AlertConfig)
+	if !ok {
+		return v1alpha1.AlertConfig{}, ErrInvalidAlertConfig
+	}
+
+	return aC, nil
+}
+
+// getStringValue retrieves the string behind a ValueOrValueFrom in the namespace of the alert configuration.
+func (a *action) getStringValue(ctx context.Context, namespace string, v v1alpha1.ValueOrValueFrom) (string, error) {
+	value, err := a.k.GetValueOrValueFrom(ctx, namespace, v)
+	if err != nil {
+		return "", err
+	}
+
+	x, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("value is not a string")
+	}
+
+	return x, nil
+}
+
+// executeShoutrrrAlert sends the alert to the service of the section of the AlertConfig.
+// setConfig stores the AlertConfig in the spec of the alert before its validation.
+func (a *action) executeShoutrrrAlert(ctx context.Context, alert shoutrrrAlert, section, service string, setConfig func(aC v1alpha1.AlertConfig)) (err error) {
+	aC, err := a.getAlertConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.shouldQueueDigest(aC) {
+		return a.queueDigest(ctx, aC, alert.GetName())
+	}
+	defer func() { a.recordAlertStatus(ctx, aC, err) }()
+
+	setConfig(aC)
+
+	if err := alert.ConfigValidation(); err != nil {
+		return err
+	}
+
+	// Construct URL
+	url, err := alert.constructURL(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to construct URL: %w", err)
+	}
+
+	// Render message
+	message, err := alert.Render()
+	if err != nil {
+		return fmt.Errorf("failed to render message: %w", err)
+	}
+
+	if err := sendAlert(url, message, &types.Params{}); err != nil {
+		return fmt.Errorf("failed to send %s alert: %w", section, err)
+	}
+
+	log.WithField("action", alert.GetName()).Info("Alert sent to " + service)
+
+	return nil
+}
+
+// requireSection returns ErrInvalidAlertConfig if the section of the AlertConfig is not configured.
+func requireSection[T any](section string, spec *T) error {
+	if spec == nil {
+		return fmt.Errorf("%s configuration is empty: %w", section, ErrInvalidAlertConfig)
+	}
+
+	return nil
+}
+
+// requireValue returns ErrInvalidAlertConfig if the value of the field of the section is empty.
+func requireValue(section, field string, v v1alpha1.ValueOrValueFrom) error {
+	if v.Value == "" && v.ValueFrom == nil {
+		return fmt.Errorf("%s %s is empty: %w", section, field, ErrInvalidAlertConfig)
+	}
+
+	return nil
+}
+
+// getURLValue retrieves and parses the URL behind the field of the section of the alert configuration.
+func (a *action) getURLValue(ctx context.Context, namespace, section, field string, v v1alpha1.ValueOrValueFrom) (*url.URL, error) {
+	value, err := a.getStringValue(ctx, namespace, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", field, err)
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %s: %w", section, field, err)
+	}
+
+	return u, nil
+}
+
+// sendAlert creates a sender for the shoutrrr url and sends the message.
+func sendAlert(url, message string, params *types.Params) error {
+	sender, err := newAlertSender(url)
+	if err != nil {
+		return fmt.Errorf("failed to create sender: %w", err)
+	}
+
+	if errS := sender.Send(message, params); errS != nil {
+		return errors.Join(errS...)
+	}
+
+	return nil
+}
+
+// recordAlertStatus writes the result of the alert in the status of the AlertConfig.
+// Failing to update the status never fails the action.
+func (a *action) recordAlertStatus(ctx context.Context, aC v1alpha1.AlertConfig, err error) {
+	// Get the latest version to reduce conflicts between concurrent alerts.
+	latest, errGet := a.k.Alert().Get(ctx, aC.Namespace, aC.Name)
+	if errGet != nil {
+		log.WithError(errGet).WithField("alertConfig", aC.Name).Warn("Failed to get alert config to update its status")
+		return
+	}
+
+	if err != nil {
+		latest.SetStatusError(err)
+	} else {
+		latest.SetStatusSent()
+	}
+
+	if errU := a.k.Alert().UpdateStatus(ctx, latest); errU != nil {
+		log.WithError(errU).WithField("alertConfig", aC.Name).Warn("Failed to update alert config status")
+	}
+}
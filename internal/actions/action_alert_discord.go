@@ -7,7 +7,6 @@ import (
 
 	s "github.com/containrrr/shoutrrr"
 
-	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
 )
@@ -30,19 +29,23 @@ func init() {
 }
 
 // Execute sends the alert message to the Discord channel.
-func (a *alertDiscord) Execute(ctx context.Context) error {
-	alertConfig, err := a.k.GetValueOrValueFrom(ctx, a.image.Namespace, a.data)
+func (a *alertDiscord) Execute(ctx context.Context) (err error) {
+	aC, err := a.getAlertConfig(ctx)
 	if err != nil {
 		return err
 	}
 
-	aC, ok := alertConfig.(v1alpha1.AlertConfig)
-	if ok {
-		a.AlertDiscord = models.AlertDiscord{
-			AlertConfig: aC,
-		}
-	} else {
-		return fmt.Errorf("invalid alert configuration")
+	if a.shouldQueueDigest(aC) {
+		return a.queueDigest(ctx, aC, a.GetName())
+	}
+	defer func() { a.recordAlertStatus(ctx, aC, err) }()
+
+	a.AlertDiscord = models.AlertDiscord{
+		AlertConfig: aC,
+	}
+
+	if err := a.ConfigValidation(); err != nil {
+		return err
 	}
 
 	token, webhookID, err := a.parseWebhookURL(ctx)
@@ -76,8 +79,16 @@ func (a *alertDiscord) Execute(ctx context.Context) error {
 	return nil
 }
 
-// ConfigValidation initializes the alertDiscord action.
+// ConfigValidation validates the alert discord configuration.
 func (a *alertDiscord) ConfigValidation() error {
+	if a.Spec.Discord == nil {
+		return fmt.Errorf("discord configuration is empty: %w", ErrInvalidAlertConfig)
+	}
+
+	if a.Spec.Discord.WebhookURL.Value == "" && a.Spec.Discord.WebhookURL.ValueFrom == nil {
+		return fmt.Errorf("discord webhookURL is empty: %w", ErrInvalidAlertConfig)
+	}
+
 	return nil
 }
 
@@ -86,6 +97,7 @@ func (a *alertDiscord) Render() (string, error) {
 	aT := alertTemplate[models.AlertDiscord]{
 		templateBody:   a.Spec.Discord.TemplateBody,
 		tags:           a.tags,
+		digest:         a.digest,
 		Image:          *a.action.image,
 		AlertInterface: a,
 	}
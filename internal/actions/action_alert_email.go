@@ -7,7 +7,6 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/types"
 
-	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
 )
@@ -30,21 +29,25 @@ func init() {
 }
 
 // Execute sends the alert message via email.
-func (a *alertEmail) Execute(ctx context.Context) error {
-	alertConfig, err := a.k.GetValueOrValueFrom(ctx, a.image.Namespace, a.data)
+func (a *alertEmail) Execute(ctx context.Context) (err error) {
+	aC, err := a.getAlertConfig(ctx)
 	if err != nil {
 		return err
 	}
 
-	aC, ok := alertConfig.(v1alpha1.AlertConfig)
-	if !ok {
-		return fmt.Errorf("invalid alert configuration")
+	if a.shouldQueueDigest(aC) {
+		return a.queueDigest(ctx, aC, a.GetName())
 	}
+	defer func() { a.recordAlertStatus(ctx, aC, err) }()
 
 	a.AlertEmail = models.AlertEmail{
 		AlertConfig: aC,
 	}
 
+	if err := a.ConfigValidation(); err != nil {
+		return err
+	}
+
 	// Construct URL
 	url, err := a.constructURL(ctx)
 	if err != nil {
@@ -81,6 +84,22 @@ func (a *alertEmail) Execute(ctx context.Context) error {
 
 // ConfigValidation validates the alert email configuration.
 func (a *alertEmail) ConfigValidation() error {
+	if a.Spec.Email == nil {
+		return fmt.Errorf("email configuration is empty: %w", ErrInvalidAlertConfig)
+	}
+
+	if a.Spec.Email.Host.Value == "" && a.Spec.Email.Host.ValueFrom == nil {
+		return fmt.Errorf("email host is empty: %w", ErrInvalidAlertConfig)
+	}
+
+	if a.Spec.Email.FromAddress == "" {
+		return fmt.Errorf("email fromAddress is empty: %w", ErrInvalidAlertConfig)
+	}
+
+	if len(a.Spec.Email.ToAddress) == 0 {
+		return fmt.Errorf("email toAddress is empty: %w", ErrInvalidAlertConfig)
+	}
+
 	return nil
 }
 
@@ -89,6 +108,7 @@ func (a *alertEmail) Render() (string, error) {
 	aT := alertTemplate[models.AlertEmail]{
 		templateBody:   a.Spec.Email.TemplateBody,
 		tags:           a.tags,
+		digest:         a.digest,
 		Image:          *a.action.image,
 		AlertInterface: a,
 	}
@@ -154,6 +174,11 @@ func (a *alertEmail) constructURL(ctx context.Context) (string, error) {
 		a.Spec.Email.TemplateSubject = "Kimup - New tag is available for {{ .ImageName }}"
 	}
 
+	// The subject of the digest is not customizable because the image fields are empty.
+	if a.digest != nil {
+		a.Spec.Email.TemplateSubject = "Kimup - New tags are available in {{ .Namespace }}"
+	}
+
 	aT := alertTemplate[models.AlertEmail]{
 		templateBody:   a.Spec.Email.TemplateSubject,
 		tags:           a.tags,
@@ -0,0 +1,107 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+var (
+	_ ActionInterface                               = &alertMattermost{}
+	_ models.AlertInterface[models.AlertMattermost] = &alertMattermost{}
+)
+
+type (
+	// alertMattermost is an action that sends an alert to a Mattermost channel.
+	alertMattermost struct {
+		action
+		models.AlertMattermost
+	}
+)
+
+func init() {
+	register(AlertMattermost, &alertMattermost{})
+}
+
+// Execute sends the alert message to the Mattermost channel.
+func (a *alertMattermost) Execute(ctx context.Context) error {
+	return a.executeShoutrrrAlert(ctx, a, "mattermost", "Mattermost", func(aC v1alpha1.AlertConfig) {
+		a.AlertMattermost = models.AlertMattermost{
+			AlertConfig: aC,
+		}
+	})
+}
+
+// ConfigValidation validates the alert mattermost configuration.
+func (a *alertMattermost) ConfigValidation() error {
+	if err := requireSection("mattermost", a.Spec.Mattermost); err != nil {
+		return err
+	}
+
+	return requireValue("mattermost", "webhookURL", a.Spec.Mattermost.WebhookURL)
+}
+
+// Render renders the alert mattermost message.
+func (a *alertMattermost) Render() (string, error) {
+	aT := alertTemplate[models.AlertMattermost]{
+		templateBody:   a.Spec.Mattermost.TemplateBody,
+		tags:           a.tags,
+		digest:         a.digest,
+		Image:          *a.action.image,
+		AlertInterface: a,
+	}
+	return aT.Render()
+}
+
+// GetSpec returns the alert mattermost spec.
+func (a *alertMattermost) GetSpec() models.AlertMattermost {
+	return a.AlertMattermost
+}
+
+// GetName returns the name of the action.
+func (a *alertMattermost) GetName() models.ActionName {
+	return AlertMattermost
+}
+
+// construct url
+// The webhook URL format is https://mattermost.example.com/hooks/token
+// and the shoutrrr URL format is mattermost://[username@]mattermost.example.com/token[/channel]
+func (a *alertMattermost) constructURL(ctx context.Context) (string, error) {
+	wURL, err := a.getURLValue(ctx, a.GetNamespace(), "mattermost", "webhookURL", a.Spec.Mattermost.WebhookURL)
+	if err != nil {
+		return "", err
+	}
+
+	x := strings.Split(strings.Trim(wURL.Path, "/"), "/")
+	if wURL.Host == "" || len(x) < 2 || x[len(x)-2] != "hooks" {
+		return "", fmt.Errorf("invalid mattermost webhook URL")
+	}
+
+	paths := []string{"", x[len(x)-1]}
+	if a.Spec.Mattermost.Channel != "" {
+		paths = append(paths, a.Spec.Mattermost.Channel)
+	}
+
+	// Query/Param Props
+	params := url.Values{}
+	if a.Spec.Mattermost.Icon != "" {
+		params.Set("icon", a.Spec.Mattermost.Icon)
+	}
+
+	u := url.URL{
+		Scheme:   "mattermost",
+		Host:     wURL.Host,
+		Path:     strings.Join(paths, "/"),
+		RawQuery: params.Encode(),
+	}
+
+	if a.Spec.Mattermost.Username != "" {
+		u.User = url.User(a.Spec.Mattermost.Username)
+	}
+
+	return u.String(), nil
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containrrr/shoutrrr/pkg/services/teams"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+var (
+	_ ActionInterface                          = &alertTeams{}
+	_ models.AlertInterface[models.AlertTeams] = &alertTeams{}
+)
+
+type (
+	// alertTeams is an action that sends an alert to a Microsoft Teams channel.
+	alertTeams struct {
+		action
+		models.AlertTeams
+	}
+)
+
+func init() {
+	register(AlertTeams, &alertTeams{})
+}
+
+// Execute sends the alert message to the Microsoft Teams channel.
+func (a *alertTeams) Execute(ctx context.Context) error {
+	return a.executeShoutrrrAlert(ctx, a, "teams", "Microsoft Teams", func(aC v1alpha1.AlertConfig) {
+		a.AlertTeams = models.AlertTeams{
+			AlertConfig: aC,
+		}
+	})
+}
+
+// ConfigValidation validates the alert teams configuration.
+func (a *alertTeams) ConfigValidation() error {
+	if err := requireSection("teams", a.Spec.Teams); err != nil {
+		return err
+	}
+
+	return requireValue("teams", "webhookURL", a.Spec.Teams.WebhookURL)
+}
+
+// Render renders the alert teams message.
+func (a *alertTeams) Render() (string, error) {
+	aT := alertTemplate[models.AlertTeams]{
+		templateBody:   a.Spec.Teams.TemplateBody,
+		tags:           a.tags,
+		digest:         a.digest,
+		Image:          *a.action.image,
+		AlertInterface: a,
+	}
+	return aT.Render()
+}
+
+// GetSpec returns the alert teams spec.
+func (a *alertTeams) GetSpec() models.AlertTeams {
+	return a.AlertTeams
+}
+
+// GetName returns the name of the action.
+func (a *alertTeams) GetName() models.ActionName {
+	return AlertTeams
+}
+
+// construct url
+// The webhook URL is converted by shoutrrr into the teams://group@tenant/altId/groupOwner format.
+func (a *alertTeams) constructURL(ctx context.Context) (string, error) {
+	wURL, err := a.getURLValue(ctx, a.GetNamespace(), "teams", "webhookURL", a.Spec.Teams.WebhookURL)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := teams.ConfigFromWebhookURL(*wURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid teams webhook URL: %w", err)
+	}
+
+	config.Title = a.Spec.Teams.Title
+	config.Color = a.Spec.Teams.Color
+
+	return config.GetURL().String(), nil
+}
@@ -0,0 +1,173 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/format"
+	"github.com/containrrr/shoutrrr/pkg/services/generic"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+var (
+	_ ActionInterface                            = &alertWebhook{}
+	_ models.AlertInterface[models.AlertWebhook] = &alertWebhook{}
+)
+
+type (
+	// alertWebhook is an action that sends an alert to a generic HTTP endpoint.
+	alertWebhook struct {
+		action
+		models.AlertWebhook
+	}
+)
+
+const (
+	defaultWebhookTemplate = `{
+	"namespace": "{{ .Namespace }}",
+	"name": "{{ .Name }}",
+	"image": "{{ .ImageName }}",
+	"baseTag": "{{ .BaseTag }}",
+	"actualTag": "{{ .ActualTag }}",
+	"newTag": "{{ .NewTag }}"
+}`
+
+	defaultWebhookDigestTemplate = `{
+	"namespace": "{{ .Namespace }}",
+	"updates": [{{ range $i, $u := .Updates }}{{ if $i }},{{ end }}
+		{
+			"name": "{{ $u.Name }}",
+			"image": "{{ $u.ImageName }}",
+			"actualTag": "{{ $u.ActualTag }}",
+			"newTag": "{{ $u.NewTag }}"
+		}{{ end }}
+	]
+}`
+)
+
+func init() {
+	register(AlertWebhook, &alertWebhook{})
+}
+
+// Execute sends the alert payload to the webhook endpoint.
+func (a *alertWebhook) Execute(ctx context.Context) error {
+	return a.executeShoutrrrAlert(ctx, a, "webhook", "webhook", func(aC v1alpha1.AlertConfig) {
+		a.AlertWebhook = models.AlertWebhook{
+			AlertConfig: aC,
+		}
+	})
+}
+
+// ConfigValidation validates the alert webhook configuration.
+func (a *alertWebhook) ConfigValidation() error {
+	if err := requireSection("webhook", a.Spec.Webhook); err != nil {
+		return err
+	}
+
+	if err := requireValue("webhook", "url", a.Spec.Webhook.URL); err != nil {
+		return err
+	}
+
+	for _, h := range a.Spec.Webhook.Headers {
+		if h.Name == "" {
+			return fmt.Errorf("webhook header name is empty: %w", ErrInvalidAlertConfig)
+		}
+	}
+
+	return nil
+}
+
+// Render renders the alert webhook payload.
+// The payload is rendered without HTML escaping because it is sent as is to the endpoint.
+func (a *alertWebhook) Render() (string, error) {
+	templateBody := a.Spec.Webhook.TemplateBody
+	if templateBody == "" {
+		templateBody = defaultWebhookTemplate
+	}
+
+	aT := alertTemplate[models.AlertWebhook]{
+		templateBody:   templateBody,
+		raw:            true,
+		tags:           a.tags,
+		digest:         a.digest,
+		Image:          *a.action.image,
+		AlertInterface: a,
+	}
+	return aT.Render()
+}
+
+// GetSpec returns the alert webhook spec.
+func (a *alertWebhook) GetSpec() models.AlertWebhook {
+	return a.AlertWebhook
+}
+
+// GetName returns the name of the action.
+func (a *alertWebhook) GetName() models.ActionName {
+	return AlertWebhook
+}
+
+// construct url
+// The shoutrrr URL format is generic://host/path?method=POST&contenttype=application/json&@header=value
+func (a *alertWebhook) constructURL(ctx context.Context) (string, error) {
+	u, err := a.getURLValue(ctx, a.GetNamespace(), "webhook", "url", a.Spec.Webhook.URL)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid webhook url scheme %q", u.Scheme)
+	}
+
+	// The query of the endpoint is forwarded by shoutrrr to the endpoint, except the keys of its configuration.
+	// The keys of the configuration are escaped, the keys of the headers and the extra data are rejected.
+	params := url.Values{}
+	pkr := format.NewPropKeyResolver(&generic.Config{})
+	for key, values := range u.Query() {
+		switch {
+		case strings.HasPrefix(key, "@") || strings.HasPrefix(key, "$"):
+			return "", fmt.Errorf("invalid webhook url: the query parameter %q is reserved", key)
+		case slices.Contains(pkr.QueryFields(), format.UnescapeKey(key)) && format.UnescapeKey(key) != key:
+			return "", fmt.Errorf("invalid webhook url: the query parameter %q is reserved", key)
+		case slices.Contains(pkr.QueryFields(), key):
+			params[format.EscapeKey(key)] = values
+		default:
+			params[key] = values
+		}
+	}
+
+	// Query/Param Props
+	params.Set("method", "POST")
+	params.Set("contenttype", "application/json")
+
+	if a.Spec.Webhook.Method != "" {
+		params.Set("method", a.Spec.Webhook.Method)
+	}
+
+	if a.Spec.Webhook.ContentType != "" {
+		params.Set("contenttype", a.Spec.Webhook.ContentType)
+	}
+
+	// * Headers
+	for _, h := range a.Spec.Webhook.Headers {
+		value, err := a.getStringValue(ctx, a.GetNamespace(), h.Value)
+		if err != nil {
+			return "", fmt.Errorf("failed to get header %s: %w", h.Name, err)
+		}
+		// shoutrrr rebuilds the header name from the lower case form (x-api-key => X-Api-Key)
+		params.Set("@"+strings.ToLower(h.Name), value)
+	}
+
+	if u.Scheme == "http" {
+		params.Set("disabletls", "yes")
+	}
+
+	u.Scheme = "generic"
+	u.RawQuery = params.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+var _ ActionInterface = &gitCommit{}
+
+type (
+	// gitCommit is an action that commits the new tag in a git repository.
+	gitCommit struct {
+		action
+		models.GitCommit
+	}
+)
+
+func init() {
+	register(GitCommit, &gitCommit{})
+}
+
+// Execute clones the repository, updates the tag in the configured file
+// and pushes the commit on the configured branch.
+func (a *gitCommit) Execute(ctx context.Context) error {
+	gC, err := a.getGitConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.GitCommit = models.GitCommit{
+		GitConfig: gC,
+	}
+
+	if err := a.ConfigValidation(); err != nil {
+		return err
+	}
+
+	repo, changed, err := a.cloneAndUpdate(ctx, gC)
+	if err != nil {
+		return err
+	}
+
+	xlog := log.WithFields(logrus.Fields{
+		"action":     a.GetName(),
+		"repository": gC.Spec.URL,
+		"path":       gC.Spec.File.Path,
+	})
+
+	if !changed {
+		xlog.Info("Tag is already up to date in the repository")
+		return nil
+	}
+
+	message, err := a.renderGitTemplate(gC.Spec.CommitMessage)
+	if err != nil {
+		return fmt.Errorf("failed to render commit message: %w", err)
+	}
+
+	hash, err := repo.Commit(gC.Spec.File.Path, message, gitAuthor(gC))
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Push(ctx, gitBranch(gC), false); err != nil {
+		return err
+	}
+
+	xlog.WithField("commit", hash).Info("New tag committed in the repository")
+
+	return nil
+}
+
+// ConfigValidation validates the git configuration.
+func (a *gitCommit) ConfigValidation() error {
+	return validateGitConfig(a.GitConfig)
+}
+
+// GetName returns the name of the action.
+func (a *gitCommit) GetName() models.ActionName {
+	return GitCommit
+}
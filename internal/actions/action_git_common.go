@@ -0,0 +1,116 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/gitops"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+const defaultGitCommitMessage = "chore(kimup): update {{ .ImageName }} to {{ .NewTag }}"
+
+var invalidBranchChars = regexp.MustCompile(`[^A-Za-z0-9._/-]+`)
+
+// getGitConfig retrieves the GitConfig referenced by the action data.
+func (a *action) getGitConfig(ctx context.Context) (v1alpha1.GitConfig, error) {
+	gitConfig, err := a.k.GetValueOrValueFrom(ctx, a.image.Namespace, a.data)
+	if err != nil {
+		return v1alpha1.GitConfig{}, err
+	}
+
+	gC, ok := gitConfig.(v1alpha1.GitConfig)
+	if !ok {
+		return v1alpha1.GitConfig{}, ErrInvalidGitConfig
+	}
+
+	return gC, nil
+}
+
+// validateGitConfig validates the fields common to the git actions.
+func validateGitConfig(gC v1alpha1.GitConfig) error {
+	if gC.Spec.URL == "" {
+		return fmt.Errorf("git url is empty: %w", ErrInvalidGitConfig)
+	}
+
+	if gC.Spec.File.Path == "" {
+		return fmt.Errorf("git file path is empty: %w", ErrInvalidGitConfig)
+	}
+
+	if gC.Spec.Auth != nil && gC.Spec.Auth.Password.Value == "" && gC.Spec.Auth.Password.ValueFrom == nil {
+		return fmt.Errorf("git auth password is empty: %w", ErrInvalidGitConfig)
+	}
+
+	return nil
+}
+
+// cloneAndUpdate clones the repository and updates the tag in the configured file.
+// changed is false if the file already contains the new tag.
+func (a *action) cloneAndUpdate(ctx context.Context, gC v1alpha1.GitConfig) (repo *gitops.Repository, changed bool, err error) {
+	if a.GetNewTag() == "" {
+		return nil, false, ErrEmptyNewTag
+	}
+
+	var username, password string
+	if gC.Spec.Auth != nil {
+		if gC.Spec.Auth.Username.Value != "" || gC.Spec.Auth.Username.ValueFrom != nil {
+			if username, err = a.getStringValue(ctx, gC.Namespace, gC.Spec.Auth.Username); err != nil {
+				return nil, false, fmt.Errorf("failed to get git username: %w", err)
+			}
+		}
+		if password, err = a.getStringValue(ctx, gC.Namespace, gC.Spec.Auth.Password); err != nil {
+			return nil, false, fmt.Errorf("failed to get git password: %w", err)
+		}
+	}
+
+	repo, err = gitops.Clone(ctx, gC.Spec.URL, gitBranch(gC), username, password)
+	if err != nil {
+		return nil, false, err
+	}
+
+	content, err := repo.ReadFile(gC.Spec.File.Path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	content, changed, err = gitops.UpdateFile(content, gitops.FileFormat(gC.Spec.File.Format), gC.Spec.File.Key, a.image.Spec.Image, a.GetNewTag())
+	if err != nil || !changed {
+		return repo, false, err
+	}
+
+	return repo, true, repo.WriteFile(gC.Spec.File.Path, content)
+}
+
+// renderGitTemplate renders the template (commit message, pull request title) without HTML escaping.
+func (a *action) renderGitTemplate(templateBody string) (string, error) {
+	if templateBody == "" {
+		templateBody = defaultGitCommitMessage
+	}
+
+	aT := alertTemplate[models.GitCommit]{
+		templateBody: templateBody,
+		raw:          true,
+		tags:         a.tags,
+		Image:        *a.image,
+	}
+	return aT.Render()
+}
+
+// gitBranch returns the branch of the git configuration.
+func gitBranch(gC v1alpha1.GitConfig) string {
+	if gC.Spec.Branch == "" {
+		return "main"
+	}
+
+	return gC.Spec.Branch
+}
+
+// gitAuthor returns the author of the commits.
+func gitAuthor(gC v1alpha1.GitConfig) gitops.Author {
+	return gitops.Author{
+		Name:  gC.Spec.Author.Name,
+		Email: gC.Spec.Author.Email,
+	}
+}
@@ -0,0 +1,156 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/gitops"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+var _ ActionInterface = &gitPullRequest{}
+
+type (
+	// gitPullRequest is an action that opens a pull request with the new tag in a git repository.
+	gitPullRequest struct {
+		action
+		models.GitPullRequest
+	}
+)
+
+const defaultGitBranchPrefix = "kimup/"
+
+func init() {
+	register(GitPullRequest, &gitPullRequest{})
+}
+
+// Execute clones the repository, updates the tag in the configured file,
+// pushes the commit on a dedicated branch and opens a pull request with the provider API.
+func (a *gitPullRequest) Execute(ctx context.Context) error {
+	gC, err := a.getGitConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.GitPullRequest = models.GitPullRequest{
+		GitConfig: gC,
+	}
+
+	if err := a.ConfigValidation(); err != nil {
+		return err
+	}
+
+	repo, changed, err := a.cloneAndUpdate(ctx, gC)
+	if err != nil {
+		return err
+	}
+
+	xlog := log.WithFields(logrus.Fields{
+		"action":     a.GetName(),
+		"repository": gC.Spec.URL,
+		"path":       gC.Spec.File.Path,
+	})
+
+	if !changed {
+		xlog.Info("Tag is already up to date in the repository")
+		return nil
+	}
+
+	message, err := a.renderGitTemplate(gC.Spec.CommitMessage)
+	if err != nil {
+		return fmt.Errorf("failed to render commit message: %w", err)
+	}
+
+	title := message
+	if gC.Spec.PullRequest.Title != "" {
+		if title, err = a.renderGitTemplate(gC.Spec.PullRequest.Title); err != nil {
+			return fmt.Errorf("failed to render pull request title: %w", err)
+		}
+	}
+
+	branch := a.branchName()
+	if err := repo.CreateBranch(branch); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+
+	if _, err := repo.Commit(gC.Spec.File.Path, message, gitAuthor(gC)); err != nil {
+		return err
+	}
+
+	// The branch is dedicated to the tag, it is overwritten if it already exists.
+	if err := repo.Push(ctx, branch, true); err != nil {
+		return err
+	}
+
+	token, err := a.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	url, err := gitops.CreatePullRequest(ctx, gitops.Provider(gC.Spec.PullRequest.Provider), gC.Spec.PullRequest.APIURL, token, gitops.PullRequest{
+		RepositoryURL: gC.Spec.URL,
+		Head:          branch,
+		Base:          gitBranch(gC),
+		Title:         title,
+		Body:          fmt.Sprintf("Kimup updates the image %s from %s to %s.", a.image.Spec.Image, a.GetActualTag(), a.GetNewTag()),
+	})
+	if err != nil {
+		return err
+	}
+
+	xlog.WithFields(logrus.Fields{
+		"branch":      branch,
+		"pullRequest": url,
+	}).Info("Pull request opened in the repository")
+
+	return nil
+}
+
+// ConfigValidation validates the git configuration.
+func (a *gitPullRequest) ConfigValidation() error {
+	if err := validateGitConfig(a.GitConfig); err != nil {
+		return err
+	}
+
+	if a.Spec.PullRequest == nil {
+		return fmt.Errorf("git pullRequest configuration is empty: %w", ErrInvalidGitConfig)
+	}
+
+	return nil
+}
+
+// GetName returns the name of the action.
+func (a *gitPullRequest) GetName() models.ActionName {
+	return GitPullRequest
+}
+
+// branchName returns the branch dedicated to the new tag of the image.
+func (a *gitPullRequest) branchName() string {
+	prefix := a.Spec.PullRequest.BranchPrefix
+	if prefix == "" {
+		prefix = defaultGitBranchPrefix
+	}
+
+	return prefix + invalidBranchChars.ReplaceAllString(a.image.Name+"-"+a.GetNewTag(), "-")
+}
+
+// token returns the token used to call the provider API.
+func (a *gitPullRequest) token(ctx context.Context) (string, error) {
+	v := a.Spec.PullRequest.Token
+	if v.Value == "" && v.ValueFrom == nil {
+		if a.Spec.Auth == nil {
+			return "", fmt.Errorf("git pullRequest token is empty: %w", ErrInvalidGitConfig)
+		}
+		v = a.Spec.Auth.Password
+	}
+
+	token, err := a.getStringValue(ctx, a.Namespace, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request token: %w", err)
+	}
+
+	return token, nil
+}
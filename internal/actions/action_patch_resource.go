@@ -0,0 +1,85 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+var _ ActionInterface = &patchResource{}
+
+type (
+	// patchResource is an action that sets the new tag in the Helm values
+	// of a Flux HelmRelease or an Argo CD Application.
+	patchResource struct {
+		action
+	}
+)
+
+func init() {
+	register(PatchResource, &patchResource{})
+}
+
+// Execute sets the new tag at the path of the resource referenced by the action data
+// and records an event on the image when the resource is updated.
+//
+// Returns:
+//   - error: `ErrEmptyNewTag` if the new tag is empty, `ErrInvalidResourceRef` if the data does not reference a resource.
+func (a *patchResource) Execute(ctx context.Context) error {
+	if a.GetNewTag() == "" {
+		return ErrEmptyNewTag
+	}
+
+	if a.data.ValueFrom == nil || a.data.ValueFrom.ResourceRef == nil {
+		return ErrInvalidResourceRef
+	}
+
+	ref := *a.data.ValueFrom.ResourceRef
+	if ref.Name == "" {
+		return fmt.Errorf("resource name is empty: %w", ErrInvalidResourceRef)
+	}
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = a.image.Namespace
+	}
+
+	path := ref.Path
+	if path == "" {
+		path = kubeclient.DefaultResourcePath
+	}
+
+	xlog := log.WithContext(ctx).WithFields(logrus.Fields{
+		"action":    a.GetName(),
+		"kind":      ref.Kind,
+		"name":      ref.Name,
+		"namespace": namespace,
+		"path":      path,
+	})
+
+	changed, err := a.k.Resource().SetValue(ctx, a.image.Namespace, ref, a.GetNewTag())
+	if err != nil {
+		return fmt.Errorf("failed to patch %s %s/%s: %w", ref.Kind, namespace, ref.Name, err)
+	}
+
+	if !changed {
+		xlog.Info("Tag is already up to date in the resource")
+		return nil
+	}
+
+	xlog.WithField("tag", a.GetNewTag()).Info("Resource patched with the new tag")
+	a.k.Image().Event(a.image, corev1.EventTypeNormal, "Patch resource", fmt.Sprintf("%s %s/%s patched: %s set to %s (was %s)", ref.Kind, namespace, ref.Name, path, a.GetNewTag(), a.GetActualTag()))
+
+	return nil
+}
+
+// GetName returns the name of the action.
+func (a *patchResource) GetName() models.ActionName {
+	return PatchResource
+}
@@ -25,6 +25,10 @@ type (
 		image *v1alpha1.Image
 		k     kubeclient.Interface
 		data  v1alpha1.ValueOrValueFrom
+		// digest is set when the action sends the digest of the pending updates
+		digest *alertDigest
+		// direct is set when the alert is sent immediately even if the AlertConfig has a digest (e.g. the test alerts)
+		direct bool
 	}
 )
 
@@ -34,6 +38,17 @@ const (
 	Apply        models.ActionName = "apply"
 	AlertDiscord models.ActionName = "alert-discord"
 	AlertEmail   models.ActionName = "alert-email"
+
+	AlertSlack      models.ActionName = "alert-slack"
+	AlertTeams      models.ActionName = "alert-teams"
+	AlertMattermost models.ActionName = "alert-mattermost"
+	AlertTelegram   models.ActionName = "alert-telegram"
+	AlertWebhook    models.ActionName = "alert-webhook"
+
+	GitCommit      models.ActionName = "git-commit"
+	GitPullRequest models.ActionName = "git-pull-request"
+
+	PatchResource models.ActionName = "patch-resource"
 )
 
 func register(name models.ActionName, action ActionInterface) {
@@ -3,6 +3,8 @@ package actions
 import (
 	"bytes"
 	"html/template"
+	"io"
+	textTemplate "text/template"
 
 	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
@@ -18,6 +20,15 @@ const (
 	Available tags:
 {{ range .AvailableTags -}}
 	- {{ . }}
+{{ end }}
+	`
+
+	defaultDigestTemplate = `
+	Kimup digest for namespace {{ .Namespace }}:
+	{{ len .Updates }} image(s) have a new tag available
+
+{{ range .Updates -}}
+	- {{ .Name }}: **{{ .ImageName }}:{{ .ActualTag }}** -> **{{ .NewTag }}**
 {{ end }}
 	`
 )
@@ -25,7 +36,11 @@ const (
 type (
 	alertTemplate[T any] struct {
 		templateBody string
-		tags         models.Tags
+		// raw disables the HTML escaping of the rendered values
+		raw  bool
+		tags models.Tags
+		// digest renders the pending updates instead of a single update
+		digest *alertDigest
 		v1alpha1.Image
 		models.AlertInterface[T]
 	}
@@ -41,15 +56,33 @@ type (
 		NewTag        string
 		ActualTag     string
 		AvailableTags []string
+
+		// * Digest
+		Updates []v1alpha1.AlertDigestEntry
 	}
 )
 
 func (a *alertTemplate[T]) Render() (string, error) {
+	if a.digest != nil {
+		a.templateBody = a.digest.templateBody
+	}
+
 	if a.templateBody == "" {
 		a.templateBody = defaultAlertTemplate
 	}
 
-	t, err := template.New("alert").Parse(a.templateBody)
+	var (
+		t interface {
+			Execute(wr io.Writer, data any) error
+		}
+		err error
+	)
+
+	if a.raw {
+		t, err = textTemplate.New("alert").Parse(a.templateBody)
+	} else {
+		t, err = template.New("alert").Parse(a.templateBody)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -65,6 +98,10 @@ func (a *alertTemplate[T]) Render() (string, error) {
 		AvailableTags: a.tags.AvailableTags,
 	}
 
+	if a.digest != nil {
+		data.Updates = a.digest.entries
+	}
+
 	var tpl bytes.Buffer
 	if err := t.Execute(&tpl, data); err != nil {
 		return "", err
@@ -0,0 +1,222 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/crontab"
+)
+
+type (
+	// alertDigest is the list of pending updates sent in one message.
+	alertDigest struct {
+		templateBody string
+		entries      []v1alpha1.AlertDigestEntry
+	}
+)
+
+// digestSchedules stores the schedule of the registered digest jobs.
+var digestSchedules = struct {
+	sync.Mutex
+	m map[string]string
+}{m: make(map[string]string)}
+
+// SetupDigest registers, updates or removes the digest job of the AlertConfig
+// according to its digest configuration.
+func SetupDigest(aC v1alpha1.AlertConfig) error {
+	key := crontab.BuildDigestKey(aC.Namespace, aC.Name)
+
+	digestSchedules.Lock()
+	defer digestSchedules.Unlock()
+
+	schedule, registered := digestSchedules.m[key]
+
+	if aC.Spec.Digest == nil {
+		if !registered {
+			return nil
+		}
+		delete(digestSchedules.m, key)
+		return crontab.RemoveJob(key)
+	}
+
+	if registered {
+		if schedule == aC.Spec.Digest.Schedule {
+			return nil
+		}
+		if err := crontab.RemoveJob(key); err != nil {
+			return err
+		}
+		delete(digestSchedules.m, key)
+	}
+
+	if err := crontab.AddDigestCronTab(aC.Namespace, aC.Name, aC.Spec.Digest.Schedule); err != nil {
+		return err
+	}
+	digestSchedules.m[key] = aC.Spec.Digest.Schedule
+
+	return nil
+}
+
+// removeDigest removes the digest job of a deleted AlertConfig.
+func removeDigest(namespace, name string) error {
+	return SetupDigest(v1alpha1.AlertConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	})
+}
+
+// setDigest sets the pending updates sent by the action.
+func (a *action) setDigest(d *alertDigest) {
+	a.digest = d
+}
+
+// setDirect sends the alert immediately, without adding it to the digest of the AlertConfig.
+func (a *action) setDirect() {
+	a.direct = true
+}
+
+// shouldQueueDigest returns true if the alert is added to the digest of the AlertConfig instead of being sent.
+func (a *action) shouldQueueDigest(aC v1alpha1.AlertConfig) bool {
+	return aC.Spec.Digest != nil && a.digest == nil && !a.direct
+}
+
+// queueDigest adds the update in the pending updates of the AlertConfig.
+// The update is sent by the next digest.
+func (a *action) queueDigest(ctx context.Context, aC v1alpha1.AlertConfig, name models.ActionName) error {
+	if err := SetupDigest(aC); err != nil {
+		return fmt.Errorf("failed to register digest: %w", err)
+	}
+
+	entry := v1alpha1.AlertDigestEntry{
+		Action:    name.String(),
+		Name:      a.image.Name,
+		ImageName: a.image.Spec.Image,
+		ActualTag: a.tags.Actual,
+		NewTag:    a.tags.New,
+		Time:      metav1.Now(),
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest, err := a.k.Alert().Get(ctx, aC.Namespace, aC.Name)
+		if err != nil {
+			return err
+		}
+
+		latest.AddPending(entry)
+		return a.k.Alert().UpdateStatus(ctx, latest)
+	}); err != nil {
+		return fmt.Errorf("failed to queue alert for digest: %w", err)
+	}
+
+	log.WithFields(logrus.Fields{
+		"action":      name,
+		"alertConfig": aC.Name,
+	}).Info("Alert queued for digest")
+
+	return nil
+}
+
+// SendDigest sends the pending updates of the AlertConfig in one message per alert
+// and removes them from the AlertConfig status.
+func SendDigest(ctx context.Context, k kubeclient.Interface, namespace, name string) error {
+	aC, err := k.Alert().Get(ctx, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return removeDigest(namespace, name)
+		}
+		return err
+	}
+
+	if err := SetupDigest(aC); err != nil {
+		return err
+	}
+
+	if aC.Spec.Digest == nil || len(aC.Status.Pending) == 0 {
+		return nil
+	}
+
+	pending := make(map[string][]v1alpha1.AlertDigestEntry)
+	for _, p := range aC.Status.Pending {
+		pending[p.Action] = append(pending[p.Action], p)
+	}
+
+	image := v1alpha1.Image{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: aC.Namespace,
+			Name:      aC.Name,
+		},
+	}
+	data := alertConfigRef(aC)
+
+	var (
+		errs []error
+		sent []v1alpha1.AlertDigestEntry
+	)
+
+	for _, s := range alertSections(aC) {
+		entries, ok := pending[s.name.String()]
+		if !ok || s.newAction == nil {
+			continue
+		}
+		delete(pending, s.name.String())
+
+		templateBody := aC.Spec.Digest.TemplateBody
+		if templateBody == "" {
+			templateBody = defaultDigestTemplate
+			if s.name == AlertWebhook {
+				templateBody = defaultWebhookDigestTemplate
+			}
+		}
+
+		a := s.newAction(aC)
+		a.Init(k, models.Tags{}, &image, data)
+		a.setDigest(&alertDigest{
+			templateBody: templateBody,
+			entries:      entries,
+		})
+
+		if err := a.Execute(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		sent = append(sent, entries...)
+	}
+
+	// The alerts removed from the AlertConfig can not be sent anymore.
+	for action, entries := range pending {
+		log.WithFields(logrus.Fields{
+			"action":      action,
+			"alertConfig": aC.Name,
+		}).Warn("Alert is not configured anymore, pending updates are dropped")
+		sent = append(sent, entries...)
+	}
+
+	if len(sent) > 0 {
+		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			latest, err := k.Alert().Get(ctx, aC.Namespace, aC.Name)
+			if err != nil {
+				return err
+			}
+
+			latest.RemovePending(sent)
+			return k.Alert().UpdateStatus(ctx, latest)
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove pending updates: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
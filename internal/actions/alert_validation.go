@@ -0,0 +1,279 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/reugn/go-quartz/quartz"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+type (
+	// alertAction is an alert action that can validate its configuration.
+	alertAction interface {
+		ActionInterface
+		ConfigValidation() error
+		setDigest(d *alertDigest)
+		setDirect()
+	}
+
+	// alertSection describes a configured section of an AlertConfig.
+	alertSection struct {
+		name         models.ActionName
+		templateBody string
+		// raw disables the HTML escaping of the rendered values
+		raw bool
+		// values are the ValueOrValueFrom fields of the section
+		values []v1alpha1.ValueOrValueFrom
+		// newAction returns a new alert action loaded with the AlertConfig
+		newAction func(aC v1alpha1.AlertConfig) alertAction
+	}
+)
+
+// alertSections returns the configured sections of the AlertConfig.
+func alertSections(aC v1alpha1.AlertConfig) (sections []alertSection) {
+	if x := aC.Spec.Discord; x != nil {
+		sections = append(sections, alertSection{
+			name:         AlertDiscord,
+			templateBody: x.TemplateBody,
+			values:       []v1alpha1.ValueOrValueFrom{x.WebhookURL},
+			newAction: func(aC v1alpha1.AlertConfig) alertAction {
+				return &alertDiscord{AlertDiscord: models.AlertDiscord{AlertConfig: aC}}
+			},
+		})
+	}
+
+	if x := aC.Spec.Email; x != nil {
+		sections = append(sections, alertSection{
+			name:         AlertEmail,
+			templateBody: x.TemplateBody,
+			values:       []v1alpha1.ValueOrValueFrom{x.Host, x.Port, x.Username, x.Password},
+			newAction: func(aC v1alpha1.AlertConfig) alertAction {
+				return &alertEmail{AlertEmail: models.AlertEmail{AlertConfig: aC}}
+			},
+		})
+		if x.TemplateSubject != "" {
+			sections = append(sections, alertSection{
+				name:         AlertEmail,
+				templateBody: x.TemplateSubject,
+			})
+		}
+	}
+
+	if x := aC.Spec.Slack; x != nil {
+		sections = append(sections, alertSection{
+			name:         AlertSlack,
+			templateBody: x.TemplateBody,
+			values:       []v1alpha1.ValueOrValueFrom{x.WebhookURL},
+			newAction: func(aC v1alpha1.AlertConfig) alertAction {
+				return &alertSlack{AlertSlack: models.AlertSlack{AlertConfig: aC}}
+			},
+		})
+	}
+
+	if x := aC.Spec.Teams; x != nil {
+		sections = append(sections, alertSection{
+			name:         AlertTeams,
+			templateBody: x.TemplateBody,
+			values:       []v1alpha1.ValueOrValueFrom{x.WebhookURL},
+			newAction: func(aC v1alpha1.AlertConfig) alertAction {
+				return &alertTeams{AlertTeams: models.AlertTeams{AlertConfig: aC}}
+			},
+		})
+	}
+
+	if x := aC.Spec.Mattermost; x != nil {
+		sections = append(sections, alertSection{
+			name:         AlertMattermost,
+			templateBody: x.TemplateBody,
+			values:       []v1alpha1.ValueOrValueFrom{x.WebhookURL},
+			newAction: func(aC v1alpha1.AlertConfig) alertAction {
+				return &alertMattermost{AlertMattermost: models.AlertMattermost{AlertConfig: aC}}
+			},
+		})
+	}
+
+	if x := aC.Spec.Telegram; x != nil {
+		sections = append(sections, alertSection{
+			name:         AlertTelegram,
+			templateBody: x.TemplateBody,
+			values:       []v1alpha1.ValueOrValueFrom{x.Token},
+			newAction: func(aC v1alpha1.AlertConfig) alertAction {
+				return &alertTelegram{AlertTelegram: models.AlertTelegram{AlertConfig: aC}}
+			},
+		})
+	}
+
+	if x := aC.Spec.Webhook; x != nil {
+		values := []v1alpha1.ValueOrValueFrom{x.URL}
+		for _, h := range x.Headers {
+			values = append(values, h.Value)
+		}
+
+		templateBody := x.TemplateBody
+		if templateBody == "" {
+			templateBody = defaultWebhookTemplate
+		}
+
+		sections = append(sections, alertSection{
+			name:         AlertWebhook,
+			templateBody: templateBody,
+			raw:          true,
+			values:       values,
+			newAction: func(aC v1alpha1.AlertConfig) alertAction {
+				return &alertWebhook{AlertWebhook: models.AlertWebhook{AlertConfig: aC}}
+			},
+		})
+	}
+
+	return sections
+}
+
+// testAlertImage returns the fake image used to render and send test alerts.
+func testAlertImage(aC v1alpha1.AlertConfig) (v1alpha1.Image, models.Tags) {
+	return v1alpha1.Image{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: aC.Namespace,
+			Name:      "kimup-test",
+		},
+		Spec: v1alpha1.ImageSpec{
+			Image:   "registry.example.com/kimup/test",
+			BaseTag: "v1.0.0",
+		},
+	}, models.Tags{
+		Actual:        "v1.0.0",
+		New:           "v1.1.0",
+		AvailableTags: []string{"v1.0.0", "v1.0.1", "v1.1.0"},
+	}
+}
+
+// alertConfigRef returns the action data referencing the AlertConfig.
+func alertConfigRef(aC v1alpha1.AlertConfig) v1alpha1.ValueOrValueFrom {
+	return v1alpha1.ValueOrValueFrom{
+		ValueFrom: &v1alpha1.ValueFromSource{
+			AlertConfigRef: &corev1.LocalObjectReference{
+				Name: aC.Name,
+			},
+		},
+	}
+}
+
+// ValidateAlertConfig checks that the AlertConfig has at least one alert configured
+// and that every configured alert has its required fields.
+func ValidateAlertConfig(aC v1alpha1.AlertConfig) error {
+	sections := alertSections(aC)
+	if len(sections) == 0 {
+		return fmt.Errorf("no alert is configured: %w", ErrInvalidAlertConfig)
+	}
+
+	var errs []error
+	if aC.Spec.Digest != nil {
+		if _, err := quartz.NewCronTrigger(aC.Spec.Digest.Schedule); err != nil {
+			errs = append(errs, fmt.Errorf("digest schedule is invalid (%w): %w", err, ErrInvalidAlertConfig))
+		}
+	}
+
+	for _, s := range sections {
+		if s.newAction == nil {
+			continue
+		}
+		if err := s.newAction(aC).ConfigValidation(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateAlertTemplates parses and renders every template of the AlertConfig with fake data.
+func ValidateAlertTemplates(aC v1alpha1.AlertConfig) error {
+	image, tags := testAlertImage(aC)
+
+	var errs []error
+	for _, s := range alertSections(aC) {
+		aT := alertTemplate[any]{
+			templateBody: s.templateBody,
+			raw:          s.raw,
+			tags:         tags,
+			Image:        image,
+		}
+		if _, err := aT.Render(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid template: %w", s.name, err))
+		}
+
+		if s.newAction != nil && aC.Spec.Digest != nil && aC.Spec.Digest.TemplateBody != "" {
+			aT.digest = &alertDigest{
+				templateBody: aC.Spec.Digest.TemplateBody,
+				entries: []v1alpha1.AlertDigestEntry{
+					{
+						Action:    s.name.String(),
+						Name:      image.Name,
+						ImageName: image.Spec.Image,
+						ActualTag: tags.Actual,
+						NewTag:    tags.New,
+						Time:      metav1.Now(),
+					},
+				},
+			}
+			if _, err := aT.Render(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid digest template: %w", s.name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ResolveAlertCredentials checks that every secret and configmap referenced by the AlertConfig
+// exists and contains the expected key.
+func ResolveAlertCredentials(ctx context.Context, k kubeclient.InterfaceKubernetes, aC v1alpha1.AlertConfig) error {
+	var errs []error
+	for _, s := range alertSections(aC) {
+		for _, v := range s.values {
+			if v.ValueFrom == nil {
+				continue
+			}
+
+			value, err := k.GetValueOrValueFrom(ctx, aC.Namespace, v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+				continue
+			}
+
+			if _, ok := value.(string); !ok {
+				errs = append(errs, fmt.Errorf("%s: value is not a string", s.name))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SendTestAlert sends a test notification with fake data for every alert configured in the AlertConfig.
+// The notifications are sent immediately, even if the AlertConfig has a digest.
+func SendTestAlert(ctx context.Context, k kubeclient.Interface, aC v1alpha1.AlertConfig) error {
+	image, tags := testAlertImage(aC)
+	data := alertConfigRef(aC)
+
+	var errs []error
+	for _, s := range alertSections(aC) {
+		if s.newAction == nil {
+			continue
+		}
+
+		a := s.newAction(aC)
+		a.Init(k, tags, &image, data)
+		a.setDirect()
+		if err := a.Execute(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
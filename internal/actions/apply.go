@@ -38,6 +38,7 @@ func (a *apply) Execute(ctx context.Context) error {
 
 	// update the image with the new tag
 	a.image.SetStatusTag(a.GetNewTag())
+	a.image.AddHistory(a.GetNewTag())
 
 	return nil
 }
@@ -8,4 +8,13 @@ var (
 
 	// ErrEmptyNewTag is returned when the new tag is empty
 	ErrEmptyNewTag = errors.New("new tag is empty")
+
+	// ErrInvalidAlertConfig is returned when the alert configuration can not be used by the action
+	ErrInvalidAlertConfig = errors.New("invalid alert configuration")
+
+	// ErrInvalidGitConfig is returned when the git configuration can not be used by the action
+	ErrInvalidGitConfig = errors.New("invalid git configuration")
+
+	// ErrInvalidResourceRef is returned when the action data does not reference a resource
+	ErrInvalidResourceRef = errors.New("invalid resource reference")
 )
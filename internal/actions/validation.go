@@ -0,0 +1,193 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+// IsAlertConfigured returns true if the alert of the action is configured in the AlertConfig.
+func IsAlertConfigured(aC v1alpha1.AlertConfig, name models.ActionName) bool {
+	for _, s := range alertSections(aC) {
+		if s.name == name && s.newAction != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateImageAction checks the action of an image before it is created.
+// The action must be registered and its data must reference the resource expected by the action.
+// The referenced resources that do not exist yet are returned as warnings
+// because they can be created after the image. The referenced resources that can not be read,
+// e.g. on a transient error of the API server, are also returned as warnings.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - k: The kubernetes client used to read the referenced resources.
+//   - namespace: The namespace of the image.
+//   - action: The action to validate.
+//
+// Returns:
+//   - warnings: The list of the referenced resources not found or not read.
+//   - error: `ErrActionNotFound` if the action is not registered or the reason why the data is not valid.
+func ValidateImageAction(ctx context.Context, k kubeclient.Interface, namespace string, action v1alpha1.ImageAction) (warnings []string, err error) {
+	name, valueFrom, err := validateActionData(action)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case name.IsAlert():
+		aC, err := k.Alert().Get(ctx, namespace, valueFrom.AlertConfigRef.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			return []string{fmt.Sprintf("AlertConfig %s not found in namespace %s", valueFrom.AlertConfigRef.Name, namespace)}, nil
+		case err != nil:
+			return []string{fmt.Sprintf("unable to verify the AlertConfig %s in namespace %s: %v", valueFrom.AlertConfigRef.Name, namespace, err)}, nil
+		case !IsAlertConfigured(aC, name):
+			return nil, fmt.Errorf("%w: the AlertConfig %s has no configuration for the action %s", ErrInvalidAlertConfig, aC.Name, name)
+		}
+
+	case name == GitCommit || name == GitPullRequest:
+		gC, err := k.GitConfig().Get(ctx, namespace, valueFrom.GitConfigRef.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			return []string{fmt.Sprintf("GitConfig %s not found in namespace %s", valueFrom.GitConfigRef.Name, namespace)}, nil
+		case err != nil:
+			return []string{fmt.Sprintf("unable to verify the GitConfig %s in namespace %s: %v", valueFrom.GitConfigRef.Name, namespace, err)}, nil
+		case name == GitPullRequest && gC.Spec.PullRequest == nil:
+			return nil, fmt.Errorf("%w: the GitConfig %s has no pullRequest configuration required by the action %s", ErrInvalidGitConfig, gC.Name, name)
+		}
+	}
+
+	if name == PatchResource && valueFrom.ResourceRef.Namespace != "" && valueFrom.ResourceRef.Namespace != namespace {
+		return []string{fmt.Sprintf("the resource of namespace %s is patched only if the namespace is allowed in the patchResource.namespaces of the Kimup", valueFrom.ResourceRef.Namespace)}, nil
+	}
+
+	return validateKeyRefs(ctx, k, namespace, *valueFrom)
+}
+
+// ValidateClusterImageAction checks the action of a cluster image before it is created.
+// The referenced resources are not checked because they are read in each namespace selected by the cluster image.
+//
+// Parameters:
+//   - action: The action to validate.
+//
+// Returns:
+//   - error: `ErrActionNotFound` if the action is not registered or the reason why the data is not valid.
+func ValidateClusterImageAction(action v1alpha1.ImageAction) error {
+	_, _, err := validateActionData(action)
+	return err
+}
+
+// validateActionData checks that the action is registered and that its data references the resource expected by the action.
+func validateActionData(action v1alpha1.ImageAction) (models.ActionName, *v1alpha1.ValueFromSource, error) {
+	name, err := ParseActionName(action.Type)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", err, action.Type)
+	}
+
+	if err := validateValueOrValueFrom(action.Data); err != nil {
+		return "", nil, err
+	}
+
+	valueFrom := action.Data.ValueFrom
+	if valueFrom == nil {
+		valueFrom = &v1alpha1.ValueFromSource{}
+	}
+
+	switch {
+	case name.IsAlert():
+		if valueFrom.AlertConfigRef == nil {
+			return "", nil, fmt.Errorf("%w: the action %s requires data.valueFrom.alertConfigRef", ErrInvalidAlertConfig, name)
+		}
+
+	case name == GitCommit || name == GitPullRequest:
+		if valueFrom.GitConfigRef == nil {
+			return "", nil, fmt.Errorf("%w: the action %s requires data.valueFrom.gitConfigRef", ErrInvalidGitConfig, name)
+		}
+
+	case name == PatchResource:
+		if valueFrom.ResourceRef == nil {
+			return "", nil, fmt.Errorf("%w: the action %s requires data.valueFrom.resourceRef", ErrInvalidResourceRef, name)
+		}
+		if _, err := kubeclient.ResourceGroupVersionResource(*valueFrom.ResourceRef); err != nil {
+			return "", nil, fmt.Errorf("%w: %w", ErrInvalidResourceRef, err)
+		}
+	}
+
+	return name, valueFrom, nil
+}
+
+// validateValueOrValueFrom checks that the ValueFrom references at most one source.
+func validateValueOrValueFrom(v v1alpha1.ValueOrValueFrom) error {
+	if v.ValueFrom == nil {
+		return nil
+	}
+
+	count := 0
+	for _, set := range []bool{
+		v.ValueFrom.SecretKeyRef != nil,
+		v.ValueFrom.ConfigMapKeyRef != nil,
+		v.ValueFrom.AlertConfigRef != nil,
+		v.ValueFrom.GitConfigRef != nil,
+		v.ValueFrom.ResourceRef != nil,
+	} {
+		if set {
+			count++
+		}
+	}
+
+	if count != 1 {
+		return fmt.Errorf("data.valueFrom must reference exactly one source, %d found", count)
+	}
+
+	return nil
+}
+
+// validateKeyRefs returns a warning if the secret or the configmap referenced does not contain the key.
+func validateKeyRefs(ctx context.Context, k kubeclient.Interface, namespace string, valueFrom v1alpha1.ValueFromSource) (warnings []string, err error) {
+	if ref := valueFrom.SecretKeyRef; ref != nil {
+		secret, err := k.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			return []string{fmt.Sprintf("Secret %s not found in namespace %s", ref.Name, namespace)}, nil
+		case err != nil:
+			return []string{fmt.Sprintf("unable to verify the Secret %s in namespace %s: %v", ref.Name, namespace, err)}, nil
+		case !secretHasKey(secret, ref.Key):
+			return []string{fmt.Sprintf("key %s not found in Secret %s", ref.Key, ref.Name)}, nil
+		}
+	}
+
+	if ref := valueFrom.ConfigMapKeyRef; ref != nil {
+		cm, err := k.CoreV1().ConfigMaps(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			return []string{fmt.Sprintf("ConfigMap %s not found in namespace %s", ref.Name, namespace)}, nil
+		case err != nil:
+			return []string{fmt.Sprintf("unable to verify the ConfigMap %s in namespace %s: %v", ref.Name, namespace, err)}, nil
+		}
+		if _, ok := cm.Data[ref.Key]; !ok {
+			return []string{fmt.Sprintf("key %s not found in ConfigMap %s", ref.Key, ref.Name)}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func secretHasKey(secret *corev1.Secret, key string) bool {
+	if _, ok := secret.Data[key]; ok {
+		return true
+	}
+	_, ok := secret.StringData[key]
+	return ok
+}
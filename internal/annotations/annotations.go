@@ -27,6 +27,14 @@ var (
 	KeyCheckSum      AnnotationKey = "kimup.cloudavenue.io" + "/checksum"
 	KeyEnabled       AnnotationKey = "kimup.cloudavenue.io" + "/enabled"
 	KeyFailurePolicy AnnotationKey = "kimup.cloudavenue.io" + "/failure-policy"
+	KeyTestSend      AnnotationKey = "kimup.cloudavenue.io" + "/test-send"
+	KeyMutations     AnnotationKey = "kimup.cloudavenue.io" + "/mutations"
+	KeyPin           AnnotationKey = "kimup.cloudavenue.io" + "/pin"
+	KeyRefreshHint   AnnotationKey = "kimup.cloudavenue.io" + "/refresh-hint"
+
+	KeyDiscovery         AnnotationKey = "kimup.cloudavenue.io" + "/discovery"
+	KeyDiscoveryTemplate AnnotationKey = "kimup.cloudavenue.io" + "/discovery-template"
+	KeyDiscovered        AnnotationKey = "kimup.cloudavenue.io" + "/discovered"
 )
 
 type (
@@ -0,0 +1,101 @@
+package annotations
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// * Discovery
+
+type (
+	Discovery struct {
+		mode     DiscoveryMode
+		template string
+	}
+
+	DiscoveryMode string
+
+	Discovered struct {
+		a     *Annotation
+		value []DiscoveredImage
+	}
+
+	// DiscoveredImage is an image used by the Pods of a namespace and not managed by an Image.
+	DiscoveredImage struct {
+		// Image is the image without tag.
+		Image string `json:"image"`
+		// BaseTag is the tag running in the namespace.
+		BaseTag string `json:"baseTag"`
+		// Pods is the list of the Pods running the image.
+		Pods []string `json:"pods"`
+	}
+)
+
+const (
+	// DiscoveryModeCreate creates the Images of the discovered images.
+	DiscoveryModeCreate DiscoveryMode = "create"
+
+	// DiscoveryModeReport only reports the discovered images in the namespace annotations.
+	DiscoveryModeReport DiscoveryMode = "report"
+)
+
+// Discovery returns the discovery annotations of a namespace.
+// The discovery annotation sets the mode of the discovery (create or report)
+// and the discovery-template annotation is the name of the ConfigMap containing
+// the template of the Images.
+func (a *Annotation) Discovery() Discovery {
+	return Discovery{
+		mode:     DiscoveryMode(strings.ToLower(strings.TrimSpace(a.annotations[string(KeyDiscovery)]))),
+		template: a.annotations[string(KeyDiscoveryTemplate)],
+	}
+}
+
+// IsEnabled returns true if the discovery mode is valid.
+func (a Discovery) IsEnabled() bool {
+	return a.mode == DiscoveryModeCreate || a.mode == DiscoveryModeReport
+}
+
+func (a Discovery) Mode() DiscoveryMode {
+	return a.mode
+}
+
+// Template returns the name of the ConfigMap containing the template of the Images.
+func (a Discovery) Template() string {
+	return a.template
+}
+
+// * Discovered
+
+// Discovered returns the discovered annotation. It is set on the namespaces
+// in report mode with the list of the images not managed by an Image.
+func (a *Annotation) Discovered() Discovered {
+	d := Discovered{
+		a: a,
+	}
+
+	if v, ok := a.annotations[string(KeyDiscovered)]; ok {
+		_ = json.Unmarshal([]byte(v), &d.value)
+	}
+
+	return d
+}
+
+func (a Discovered) Get() []DiscoveredImage {
+	return a.value
+}
+
+// Set replaces the discovered images. The annotation is removed if the list is empty.
+func (a Discovered) Set(images []DiscoveredImage) error {
+	if len(images) == 0 {
+		a.a.Remove(KeyDiscovered)
+		return nil
+	}
+
+	x, err := json.Marshal(images)
+	if err != nil {
+		return err
+	}
+
+	a.a.annotations[string(KeyDiscovered)] = string(x)
+	return nil
+}
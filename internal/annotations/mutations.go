@@ -0,0 +1,73 @@
+package annotations
+
+import (
+	"encoding/json"
+)
+
+// * Mutations
+
+type (
+	Mutations struct {
+		a     *Annotation
+		value []ContainerMutation
+	}
+
+	// ContainerMutation records the image of a container rewritten by the mutator.
+	ContainerMutation struct {
+		// Container is the name of the container.
+		Container string `json:"container"`
+		// OriginalImage is the image of the container before the mutation.
+		OriginalImage string `json:"originalImage"`
+		// Image is the image set by the mutator.
+		Image string `json:"image"`
+		// ImageRef is the namespace/name of the Image that decided the mutation.
+		ImageRef string `json:"imageRef"`
+		// Time is the time of the decision in RFC3339 format.
+		Time string `json:"time"`
+	}
+)
+
+// Mutations returns the mutations annotation. It is set on the Pods by the mutator
+// with the list of the containers whose image has been rewritten.
+func (a *Annotation) Mutations() Mutations {
+	m := Mutations{
+		a: a,
+	}
+
+	if v, ok := a.annotations[string(KeyMutations)]; ok {
+		_ = json.Unmarshal([]byte(v), &m.value)
+	}
+
+	return m
+}
+
+func (a Mutations) Get() []ContainerMutation {
+	return a.value
+}
+
+// Set adds the mutations to the annotation.
+// The previous mutation of a container is replaced.
+func (a Mutations) Set(mutations ...ContainerMutation) error {
+	value := make([]ContainerMutation, 0, len(a.value)+len(mutations))
+	for _, m := range a.value {
+		replaced := false
+		for _, n := range mutations {
+			if m.Container == n.Container {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			value = append(value, m)
+		}
+	}
+	value = append(value, mutations...)
+
+	x, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	a.a.annotations[string(KeyMutations)] = string(x)
+	return nil
+}
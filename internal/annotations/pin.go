@@ -0,0 +1,43 @@
+package annotations
+
+import "strings"
+
+// * Pin
+
+type (
+	Pin struct {
+		value []string
+	}
+)
+
+// Pin returns the pin annotation. When set on a Pod, the mutator keeps the image of the
+// pinned containers. The value is a comma separated list of container names,
+// or `*` (`true`) to pin all the containers of the Pod.
+func (a *Annotation) Pin() Pin {
+	p := Pin{}
+
+	if v, ok := a.annotations[string(KeyPin)]; ok {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				p.value = append(p.value, name)
+			}
+		}
+	}
+
+	return p
+}
+
+func (a Pin) Get() []string {
+	return a.value
+}
+
+// IsPinned returns true if the container is pinned.
+func (a Pin) IsPinned(container string) bool {
+	for _, name := range a.value {
+		if name == container || name == "*" || name == "true" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package annotations
+
+import "encoding/json"
+
+// * RefreshHint
+
+type (
+	RefreshHint struct {
+		a     *Annotation
+		value RefreshHintValue
+	}
+
+	// RefreshHintValue is the JSON value of the refresh-hint annotation.
+	RefreshHintValue struct {
+		// Tag is the tag pushed to the registry
+		Tag string `json:"tag,omitempty"`
+		// TraceContext is the trace context of the push notification
+		TraceContext map[string]string `json:"traceContext,omitempty"`
+	}
+)
+
+// RefreshHint returns the refresh-hint annotation. It is set with the refresh action
+// when a push notification is received by a replica not owning the Image,
+// the owner refreshes the Image with the pushed tag as hint and removes the annotation.
+func (a *Annotation) RefreshHint() RefreshHint {
+	rh := RefreshHint{
+		a: a,
+	}
+
+	if v, ok := a.annotations[string(KeyRefreshHint)]; ok {
+		_ = json.Unmarshal([]byte(v), &rh.value)
+	}
+
+	return rh
+}
+
+func (a RefreshHint) Get() RefreshHintValue {
+	return a.value
+}
+
+// Remove removes the refresh-hint annotation.
+func (a RefreshHint) Remove() {
+	a.a.Remove(KeyRefreshHint)
+}
@@ -0,0 +1,36 @@
+package annotations
+
+import "strconv"
+
+// * TestSend
+
+type (
+	TestSend struct {
+		a     *Annotation
+		value bool
+	}
+)
+
+// TestSend returns the test-send annotation. When set to true on an AlertConfig,
+// the operator sends a test notification and removes the annotation.
+func (a *Annotation) TestSend() TestSend {
+	ts := TestSend{
+		a: a,
+	}
+
+	if v, ok := a.annotations[string(KeyTestSend)]; ok {
+		boolValue, _ := strconv.ParseBool(v)
+		ts.value = boolValue
+	}
+
+	return ts
+}
+
+func (a TestSend) Get() bool {
+	return a.value
+}
+
+// Remove removes the test-send annotation.
+func (a TestSend) Remove() {
+	a.a.Remove(KeyTestSend)
+}
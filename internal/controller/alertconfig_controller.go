@@ -16,6 +16,144 @@ limitations under the License.
 
 package controller
 
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kimupv1alpha1 "github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+)
+
+// AlertConfigReconciler reconciles a AlertConfig object
+type AlertConfigReconciler struct {
+	client.Client
+	KubeAPIClient *kubeclient.Client
+	Scheme        *runtime.Scheme
+	Recorder      record.EventRecorder
+}
+
+type AlertConfigEvent string
+
+const (
+	AlertConfigValidation AlertConfigEvent = "AlertConfigValidation"
+	AlertConfigTestSend   AlertConfigEvent = "AlertConfigTestSend"
+)
+
+// alertConfigResyncPeriod is the period used to validate again the AlertConfig.
+// Secrets and configmaps are not watched, a periodic resync detects credentials changes.
+const alertConfigResyncPeriod = 10 * time.Minute
+
 // +kubebuilder:rbac:groups=kimup.cloudavenue.io,resources=alertconfigs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kimup.cloudavenue.io,resources=alertconfigs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=kimup.cloudavenue.io,resources=alertconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+// Reconcile validates the AlertConfig (configuration, templates and credentials),
+// sends a test notification if requested with the test-send annotation
+// and writes the result in the AlertConfig status.
+func (r *AlertConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	xlog := log.WithContext(ctx).WithFields(logrus.Fields{
+		"namespace": req.Namespace,
+		"name":      req.Name,
+	})
+
+	var alertConfig kimupv1alpha1.AlertConfig
+
+	if err := r.Client.Get(ctx, req.NamespacedName, &alertConfig); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			xlog.WithError(err).Error("could not get the alertconfig object")
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	xlog.Info("Reconciling AlertConfig")
+
+	// * Validation
+	errConfig := actions.ValidateAlertConfig(alertConfig)
+	errTemplate := actions.ValidateAlertTemplates(alertConfig)
+	errCredentials := actions.ResolveAlertCredentials(ctx, r.KubeAPIClient, alertConfig)
+
+	// * Test send
+	an := annotations.New(ctx, &alertConfig)
+	if an.TestSend().Get() {
+		an.TestSend().Remove()
+		if err := r.Client.Update(ctx, &alertConfig); err != nil {
+			xlog.WithError(err).Error("unable to remove the test-send annotation")
+			return ctrl.Result{}, err
+		}
+
+		switch {
+		case errConfig != nil || errTemplate != nil || errCredentials != nil:
+			r.Recorder.Event(&alertConfig, "Warning", string(AlertConfigTestSend), "Test notification skipped: the alert configuration is not valid")
+		default:
+			if err := actions.SendTestAlert(ctx, r.KubeAPIClient, alertConfig); err != nil {
+				xlog.WithError(err).Warn("failed to send test notification")
+				r.Recorder.Event(&alertConfig, "Warning", string(AlertConfigTestSend), fmt.Sprintf("Failed to send test notification: %v", err))
+			} else {
+				r.Recorder.Event(&alertConfig, "Normal", string(AlertConfigTestSend), "Test notification sent")
+			}
+
+			// The alert actions update lastSent/lastError, get the latest version of the object.
+			if err := r.Client.Get(ctx, req.NamespacedName, &alertConfig); err != nil {
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+		}
+	}
+
+	// * Status
+	if errTemplate != nil {
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionTemplateValid, metav1.ConditionFalse, kimupv1alpha1.AlertConfigReasonTemplateInvalid, errTemplate.Error())
+	} else {
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionTemplateValid, metav1.ConditionTrue, kimupv1alpha1.AlertConfigReasonValid, "All templates are valid")
+	}
+
+	if errCredentials != nil {
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionCredentialsResolved, metav1.ConditionFalse, kimupv1alpha1.AlertConfigReasonCredentialsNotFound, errCredentials.Error())
+	} else {
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionCredentialsResolved, metav1.ConditionTrue, kimupv1alpha1.AlertConfigReasonResolved, "All credentials are resolved")
+	}
+
+	switch {
+	case errConfig != nil:
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionReady, metav1.ConditionFalse, kimupv1alpha1.AlertConfigReasonInvalid, errConfig.Error())
+	case errTemplate != nil:
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionReady, metav1.ConditionFalse, kimupv1alpha1.AlertConfigReasonTemplateInvalid, "One or more templates are invalid")
+	case errCredentials != nil:
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionReady, metav1.ConditionFalse, kimupv1alpha1.AlertConfigReasonCredentialsNotFound, "One or more credentials can not be resolved")
+	default:
+		alertConfig.SetCondition(kimupv1alpha1.AlertConfigConditionReady, metav1.ConditionTrue, kimupv1alpha1.AlertConfigReasonValid, "Alert configuration is valid")
+	}
+
+	if !alertConfig.IsReady() && alertConfig.Status.ObservedGeneration != alertConfig.Generation {
+		r.Recorder.Event(&alertConfig, "Warning", string(AlertConfigValidation), "Alert configuration is not valid")
+	}
+
+	alertConfig.Status.ObservedGeneration = alertConfig.Generation
+
+	if err := r.Status().Update(ctx, &alertConfig); err != nil {
+		xlog.WithError(err).Error("unable to update AlertConfig status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: alertConfigResyncPeriod}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AlertConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kimupv1alpha1.AlertConfig{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+)
+
+// +kubebuilder:webhook:path=/validate-kimup-cloudavenue-io-v1alpha1-alertconfig,mutating=false,failurePolicy=fail,groups=kimup.cloudavenue.io,resources=alertconfigs,sideEffects=None,verbs=create;update,versions=v1alpha1,name=alertconfig.validator.kimup.cloudavenue.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &AlertConfigValidator{}
+
+// AlertConfigValidator rejects the AlertConfigs that can not be used by the alert actions.
+type AlertConfigValidator struct {
+	KubeAPIClient kubeclient.Interface
+}
+
+// SetupWebhookWithManager registers the validating webhook of the AlertConfigs.
+func (v *AlertConfigValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.AlertConfig{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the AlertConfig on creation.
+func (v *AlertConfigValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+// ValidateUpdate validates the AlertConfig on update.
+// The updates that do not change the spec, e.g. the annotations, the finalizers and the status written by kimup, are always accepted.
+func (v *AlertConfigValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	if oldAC, ok := oldObj.(*v1alpha1.AlertConfig); ok {
+		if newAC, ok := newObj.(*v1alpha1.AlertConfig); ok && equality.Semantic.DeepEqual(oldAC.Spec, newAC.Spec) {
+			return nil, nil
+		}
+	}
+
+	return v.validate(ctx, newObj)
+}
+
+// ValidateDelete accepts the deletion of any AlertConfig.
+func (v *AlertConfigValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate rejects the AlertConfig if its configuration or its templates are not valid.
+// The secrets and configmaps not found are returned as warnings because they can be created after.
+func (v *AlertConfigValidator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	aC, ok := obj.(*v1alpha1.AlertConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected an AlertConfig but got a %T", obj)
+	}
+
+	var (
+		errs     field.ErrorList
+		warnings admission.Warnings
+		spec     = field.NewPath("spec")
+	)
+
+	if err := actions.ValidateAlertConfig(*aC); err != nil {
+		errs = append(errs, field.Invalid(spec, aC.Name, err.Error()))
+	}
+
+	if err := actions.ValidateAlertTemplates(*aC); err != nil {
+		errs = append(errs, field.Invalid(spec, aC.Name, err.Error()))
+	}
+
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("AlertConfig").GroupKind(), aC.Name, errs)
+	}
+
+	if err := actions.ResolveAlertCredentials(ctx, v.KubeAPIClient, *aC); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kimupv1alpha1 "github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+)
+
+// ClusterImageReconciler reconciles a ClusterImage object.
+// It creates an Image from the ClusterImage in each selected namespace.
+// A namespace defining its own Image for the same image overrides the ClusterImage:
+// the Image created from the ClusterImage is removed from this namespace.
+type ClusterImageReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+const (
+	ClusterImageUpdate ImageEvent = "ClusterImageUpdate"
+)
+
+// +kubebuilder:rbac:groups=kimup.cloudavenue.io,resources=clusterimages,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=kimup.cloudavenue.io,resources=clusterimages/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kimup.cloudavenue.io,resources=clusterimages/finalizers,verbs=update
+
+// Reconcile creates, updates and deletes the Images managed by the ClusterImage.
+// The Images are garbage collected by Kubernetes when the ClusterImage is deleted.
+func (r *ClusterImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	xlog := log.WithContext(ctx).WithFields(logrus.Fields{
+		"name": req.Name,
+	})
+
+	var clusterImage kimupv1alpha1.ClusterImage
+	if err := r.Client.Get(ctx, req.NamespacedName, &clusterImage); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			xlog.WithError(err).Error("could not get the clusterimage object")
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	xlog.Info("Reconciling ClusterImage")
+
+	selector := labels.Everything()
+	if clusterImage.Spec.NamespaceSelector != nil {
+		s, err := metav1.LabelSelectorAsSelector(clusterImage.Spec.NamespaceSelector)
+		if err != nil {
+			r.Recorder.Event(&clusterImage, "Warning", string(ClusterImageUpdate), fmt.Sprintf("Invalid namespace selector: %v", err))
+			return ctrl.Result{}, nil
+		}
+		selector = s
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var managed kimupv1alpha1.ImageList
+	if err := r.List(ctx, &managed, client.MatchingLabels{kimupv1alpha1.LabelClusterImage: clusterImage.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := kimupv1alpha1.ClusterImageStatus{
+		ObservedGeneration: clusterImage.Generation,
+	}
+	selected := map[string]bool{}
+
+	for _, ns := range namespaces.Items {
+		if ns.DeletionTimestamp != nil {
+			continue
+		}
+
+		overridden, err := r.isOverridden(ctx, &clusterImage, ns.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if overridden {
+			status.Overridden = append(status.Overridden, ns.Name)
+			continue
+		}
+
+		if err := r.createOrUpdateImage(ctx, &clusterImage, ns.Name); err != nil {
+			xlog.WithError(err).WithField("namespace", ns.Name).Error("unable to create or update Image")
+			r.Recorder.Event(&clusterImage, "Warning", string(ClusterImageUpdate), fmt.Sprintf("Failed to create or update the image in namespace %s: %v", ns.Name, err))
+			return ctrl.Result{}, err
+		}
+
+		selected[ns.Name] = true
+		status.Namespaces = append(status.Namespaces, ns.Name)
+	}
+
+	// Remove the Images of the namespaces no longer selected or overridden.
+	// The label can be set by the users, only the Images controlled by the ClusterImage are removed.
+	for _, image := range managed.Items {
+		if selected[image.Namespace] || !metav1.IsControlledBy(&image, &clusterImage) {
+			continue
+		}
+
+		if err := r.Delete(ctx, &image); client.IgnoreNotFound(err) != nil {
+			xlog.WithError(err).WithField("namespace", image.Namespace).Error("unable to delete Image")
+			return ctrl.Result{}, err
+		}
+	}
+
+	slices.Sort(status.Namespaces)
+	slices.Sort(status.Overridden)
+
+	if !equality.Semantic.DeepEqual(clusterImage.Status, status) {
+		clusterImage.Status = status
+		if err := r.Status().Update(ctx, &clusterImage); err != nil {
+			xlog.WithError(err).Error("unable to update ClusterImage status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// isOverridden returns true if the namespace defines its own Image for the image of the ClusterImage
+// or if an Image not managed by the ClusterImage already uses its name.
+func (r *ClusterImageReconciler) isOverridden(ctx context.Context, clusterImage *kimupv1alpha1.ClusterImage, namespace string) (bool, error) {
+	var images kimupv1alpha1.ImageList
+	if err := r.List(ctx, &images, client.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+
+	for _, image := range images.Items {
+		if image.IsManagedByClusterImage() {
+			continue
+		}
+
+		if image.Spec.Image == clusterImage.Spec.Image || image.Name == clusterImage.Name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// createOrUpdateImage creates the Image of the ClusterImage in the namespace
+// or restores its spec and its label if they have been modified.
+func (r *ClusterImageReconciler) createOrUpdateImage(ctx context.Context, clusterImage *kimupv1alpha1.ClusterImage, namespace string) error {
+	var image kimupv1alpha1.Image
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: clusterImage.Name}, &image)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	if err != nil {
+		image = kimupv1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterImage.Name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					kimupv1alpha1.LabelClusterImage: clusterImage.Name,
+				},
+			},
+			Spec: *clusterImage.Spec.ImageSpec.DeepCopy(),
+		}
+		if err := ctrl.SetControllerReference(clusterImage, &image, r.Scheme); err != nil {
+			return err
+		}
+
+		return r.Create(ctx, &image)
+	}
+
+	if !metav1.IsControlledBy(&image, clusterImage) {
+		return fmt.Errorf("image %s is not controlled by the ClusterImage", image.Name)
+	}
+
+	if equality.Semantic.DeepEqual(image.Spec, clusterImage.Spec.ImageSpec) && image.Labels[kimupv1alpha1.LabelClusterImage] == clusterImage.Name {
+		return nil
+	}
+
+	if image.Labels == nil {
+		image.Labels = map[string]string{}
+	}
+	image.Labels[kimupv1alpha1.LabelClusterImage] = clusterImage.Name
+	image.Spec = *clusterImage.Spec.ImageSpec.DeepCopy()
+	return r.Update(ctx, &image)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterImageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kimupv1alpha1.ClusterImage{}).
+		Owns(&kimupv1alpha1.Image{}).
+		// The namespace-level Images override the ClusterImages
+		Watches(&kimupv1alpha1.Image{}, handler.EnqueueRequestsFromMapFunc(r.requestsForImage)).
+		// The labels of the namespaces are used by the namespace selectors
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.requestsForAll)).
+		Complete(r)
+}
+
+// requestsForImage returns the ClusterImages overridden by the Image.
+func (r *ClusterImageReconciler) requestsForImage(ctx context.Context, o client.Object) []reconcile.Request {
+	image, ok := o.(*kimupv1alpha1.Image)
+	if !ok || image.IsManagedByClusterImage() {
+		return nil
+	}
+
+	var clusterImages kimupv1alpha1.ClusterImageList
+	if err := r.List(ctx, &clusterImages); err != nil {
+		log.WithContext(ctx).WithError(err).Error("unable to list ClusterImages")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, ci := range clusterImages.Items {
+		if ci.Spec.Image == image.Spec.Image || ci.Name == image.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ci.Name}})
+		}
+	}
+
+	return requests
+}
+
+// requestsForAll returns all the ClusterImages.
+func (r *ClusterImageReconciler) requestsForAll(ctx context.Context, _ client.Object) []reconcile.Request {
+	var clusterImages kimupv1alpha1.ClusterImageList
+	if err := r.List(ctx, &clusterImages); err != nil {
+		log.WithContext(ctx).WithError(err).Error("unable to list ClusterImages")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusterImages.Items))
+	for _, ci := range clusterImages.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ci.Name}})
+	}
+
+	return requests
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+)
+
+// +kubebuilder:webhook:path=/validate-kimup-cloudavenue-io-v1alpha1-clusterimage,mutating=false,failurePolicy=fail,groups=kimup.cloudavenue.io,resources=clusterimages,sideEffects=None,verbs=create;update,versions=v1alpha1,name=clusterimage.validator.kimup.cloudavenue.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &ClusterImageValidator{}
+
+// ClusterImageValidator rejects the ClusterImages whose Images would be rejected by the ImageValidator.
+type ClusterImageValidator struct{}
+
+// SetupWebhookWithManager registers the validating webhook of the ClusterImages.
+func (v *ClusterImageValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.ClusterImage{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the ClusterImage on creation.
+func (v *ClusterImageValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate validates the ClusterImage on update.
+// The updates that do not change the spec are always accepted.
+func (v *ClusterImageValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	if oldClusterImage, ok := oldObj.(*v1alpha1.ClusterImage); ok {
+		if newClusterImage, ok := newObj.(*v1alpha1.ClusterImage); ok && equality.Semantic.DeepEqual(oldClusterImage.Spec, newClusterImage.Spec) {
+			return nil, nil
+		}
+	}
+
+	return v.validate(newObj)
+}
+
+// ValidateDelete accepts the deletion of any ClusterImage.
+func (v *ClusterImageValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the namespace selector and the spec of the Images.
+// The resources referenced by the actions are not checked because they are read in each selected namespace.
+func (v *ClusterImageValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	clusterImage, ok := obj.(*v1alpha1.ClusterImage)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterImage but got a %T", obj)
+	}
+
+	spec := field.NewPath("spec")
+
+	errs, warnings := validateImageSpec(clusterImage.Name, clusterImage.Spec.ImageSpec, spec, func(action v1alpha1.ImageAction) ([]string, error) {
+		return nil, actions.ValidateClusterImageAction(action)
+	})
+
+	if clusterImage.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(clusterImage.Spec.NamespaceSelector); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("namespaceSelector"), clusterImage.Spec.NamespaceSelector, err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("ClusterImage").GroupKind(), clusterImage.Name, errs)
+	}
+
+	return warnings, nil
+}
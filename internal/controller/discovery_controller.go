@@ -0,0 +1,311 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	kimupv1alpha1 "github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+// DiscoveryReconciler discovers the images used by the Pods of the namespaces
+// enabled for kimup and not yet managed by an Image.
+// Depending on the discovery annotation of the namespace, the Images are created
+// from the template of the namespace or only reported in the namespace annotations.
+type DiscoveryReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+const (
+	ImageDiscovery ImageEvent = "ImageDiscovery"
+
+	// DiscoveryManagedBy is the value of the managed-by label of the Images created by the discovery.
+	DiscoveryManagedBy = "kimup-discovery"
+
+	// DiscoveryTemplateKey is the key of the template in the discovery ConfigMap.
+	DiscoveryTemplateKey = "template"
+)
+
+// DefaultDiscoveryTemplate is the template of the discovered Images when the namespace does not define one.
+// The image is checked every 12 hours and the patch versions are applied.
+var DefaultDiscoveryTemplate = kimupv1alpha1.ImageSpec{
+	Triggers: []kimupv1alpha1.ImageTrigger{
+		{Type: triggers.Crontab, Value: "00 00 */12 * * *"},
+	},
+	Rules: []kimupv1alpha1.ImageRule{
+		{
+			Name:    "Automatic update patch version",
+			Type:    rules.SemverPatch,
+			Actions: []kimupv1alpha1.ImageAction{{Type: "apply"}},
+		},
+	},
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+// Reconcile discovers the images of the namespace.
+func (r *DiscoveryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	xlog := log.WithContext(ctx).WithFields(logrus.Fields{
+		"namespace": req.Name,
+	})
+
+	var ns corev1.Namespace
+	if err := r.Client.Get(ctx, req.NamespacedName, &ns); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	an := annotations.New(ctx, &ns)
+	discovery := an.Discovery()
+
+	if !an.Enabled().Get() || !discovery.IsEnabled() || ns.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	xlog.Info("Discovering images")
+
+	discovered, err := r.discover(ctx, ns.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if discovery.Mode() == annotations.DiscoveryModeReport {
+		if slices.EqualFunc(an.Discovered().Get(), discovered, discoveredImageIsEqual) {
+			return ctrl.Result{}, nil
+		}
+
+		if err := an.Discovered().Set(discovered); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Update(ctx, &ns); err != nil {
+			xlog.WithError(err).Error("unable to update the discovered images of the namespace")
+			return ctrl.Result{}, err
+		}
+
+		for _, d := range discovered {
+			r.Recorder.Event(&ns, "Normal", string(ImageDiscovery), fmt.Sprintf("Image %s:%s is not managed by kimup", d.Image, d.BaseTag))
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if len(discovered) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	template, err := r.template(ctx, ns.Name, discovery.Template())
+	if err != nil {
+		xlog.WithError(err).Error("unable to get the discovery template")
+		r.Recorder.Event(&ns, "Warning", string(ImageDiscovery), fmt.Sprintf("Invalid discovery template: %v", err))
+		return ctrl.Result{}, nil
+	}
+
+	for _, d := range discovered {
+		image := kimupv1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      discoveredImageName(d.Image),
+				Namespace: ns.Name,
+				Labels: map[string]string{
+					KubernetesManagedByLabelKey: DiscoveryManagedBy,
+				},
+			},
+			Spec: *template.DeepCopy(),
+		}
+		image.Spec.Image = d.Image
+		image.Spec.BaseTag = d.BaseTag
+
+		err := r.Create(ctx, &image)
+		if apierrors.IsAlreadyExists(err) {
+			// The name is already used by an Image of another image
+			image.Name = fmt.Sprintf("%s-%s", image.Name, shortHash(d.Image))
+			err = client.IgnoreAlreadyExists(r.Create(ctx, &image))
+		}
+		if err != nil {
+			if !apierrors.IsInvalid(err) {
+				return ctrl.Result{}, err
+			}
+
+			// The template does not fit the image (e.g. a semver rule for the latest tag), the other images are still created
+			xlog.WithError(err).WithField("image", d.Image).Warn("Image rejected by the validation")
+			r.Recorder.Event(&ns, "Warning", string(ImageDiscovery), fmt.Sprintf("Image %s:%s not created: %v", d.Image, d.BaseTag, err))
+			continue
+		}
+
+		xlog.WithField("image", d.Image).Info("Image created by the discovery")
+		r.Recorder.Event(&ns, "Normal", string(ImageDiscovery), fmt.Sprintf("Image %s created for %s:%s", image.Name, d.Image, d.BaseTag))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// discover returns the images used by the Pods of the namespace and not managed by an Image.
+// The images are sorted by name.
+func (r *DiscoveryReconciler) discover(ctx context.Context, namespace string) ([]annotations.DiscoveredImage, error) {
+	var images kimupv1alpha1.ImageList
+	if err := r.List(ctx, &images, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	managed := map[string]bool{}
+	for _, image := range images.Items {
+		managed[image.Spec.Image] = true
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	found := map[string]*annotations.DiscoveredImage{}
+	for _, pod := range pods.Items {
+		// The init containers are not mutated, their images are not discovered
+		for _, container := range pod.Spec.Containers {
+			name, tag, ok := parseContainerImage(container.Image)
+			if !ok || managed[name] {
+				continue
+			}
+
+			d, ok := found[name]
+			if !ok {
+				d = &annotations.DiscoveredImage{Image: name, BaseTag: tag}
+				found[name] = d
+			}
+			if !slices.Contains(d.Pods, pod.Name) {
+				d.Pods = append(d.Pods, pod.Name)
+			}
+		}
+	}
+
+	discovered := make([]annotations.DiscoveredImage, 0, len(found))
+	for _, d := range found {
+		slices.Sort(d.Pods)
+		discovered = append(discovered, *d)
+	}
+	slices.SortFunc(discovered, func(a, b annotations.DiscoveredImage) int {
+		return strings.Compare(a.Image, b.Image)
+	})
+
+	return discovered, nil
+}
+
+// template returns the template of the Images from the ConfigMap of the namespace.
+// The DefaultDiscoveryTemplate is returned if the name is empty.
+func (r *DiscoveryReconciler) template(ctx context.Context, namespace, name string) (kimupv1alpha1.ImageSpec, error) {
+	if name == "" {
+		return DefaultDiscoveryTemplate, nil
+	}
+
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &cm); err != nil {
+		return kimupv1alpha1.ImageSpec{}, err
+	}
+
+	data, ok := cm.Data[DiscoveryTemplateKey]
+	if !ok {
+		return kimupv1alpha1.ImageSpec{}, fmt.Errorf("key %s not found in configmap %s", DiscoveryTemplateKey, name)
+	}
+
+	var spec kimupv1alpha1.ImageSpec
+	if err := yaml.Unmarshal([]byte(data), &spec); err != nil {
+		return kimupv1alpha1.ImageSpec{}, err
+	}
+
+	if len(spec.Triggers) == 0 || len(spec.Rules) == 0 {
+		return kimupv1alpha1.ImageSpec{}, fmt.Errorf("the template of configmap %s must define triggers and rules", name)
+	}
+
+	return spec, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DiscoveryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("discovery").
+		For(&corev1.Namespace{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(requestForNamespace)).
+		Watches(&kimupv1alpha1.Image{}, handler.EnqueueRequestsFromMapFunc(requestForNamespace)).
+		Complete(r)
+}
+
+// requestForNamespace returns the namespace of the object.
+func requestForNamespace(_ context.Context, o client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: o.GetNamespace()}}}
+}
+
+// parseContainerImage returns the image without tag and the tag of the image of a container.
+// The tag is latest if the image has no tag. It returns false if the image is referenced only by digest.
+func parseContainerImage(image string) (name, tag string, ok bool) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return "", "", false
+	}
+
+	named, ok := ref.(reference.Named)
+	if !ok {
+		return "", "", false
+	}
+
+	if tagged, ok := ref.(reference.Tagged); ok {
+		return named.Name(), tagged.Tag(), true
+	}
+
+	if _, ok := ref.(reference.Digested); ok {
+		return "", "", false
+	}
+
+	return named.Name(), "latest", true
+}
+
+// discoveredImageName returns the name of the Image created for the image.
+// It is the last element of the image path.
+func discoveredImageName(image string) string {
+	name := strings.ToLower(image[strings.LastIndex(image, "/")+1:])
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-")
+	if len(name) > 50 {
+		name = strings.Trim(name[:50], "-")
+	}
+	if name == "" {
+		name = shortHash(image)
+	}
+
+	return name
+}
+
+// shortHash returns a short hash of the value.
+func shortHash(value string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+func discoveredImageIsEqual(a, b annotations.DiscoveredImage) bool {
+	return a.Image == b.Image && a.BaseTag == b.BaseTag && slices.Equal(a.Pods, b.Pods)
+}
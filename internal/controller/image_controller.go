@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -81,6 +82,7 @@ func (r *ImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	if err != nil || !equal {
 		an.Action().Set(annotations.ActionReload)
 		image.SetStatusResult(kimupv1alpha1.ImageStatusLastSyncScheduled)
+		image.SetCondition(kimupv1alpha1.ImageConditionReady, metav1.ConditionUnknown, kimupv1alpha1.ImageReasonScheduled, "Image configuration has changed")
 		r.Recorder.Event(&image, "Normal", string(ImageUpdate), "Image configuration has changed. Reloading image.")
 	}
 
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/metrics"
+)
+
+// ImageSpecImageIndex is the name of the cache index of the Images by spec.image.
+const ImageSpecImageIndex = "spec.image"
+
+// IndexImageSpecImage returns the values of the ImageSpecImageIndex for the object.
+func IndexImageSpecImage(o client.Object) []string {
+	image, ok := o.(*v1alpha1.Image)
+	if !ok || image.Spec.Image == "" {
+		return nil
+	}
+
+	return []string{image.Spec.Image}
+}
+
+// SetupImageIndexWithManager registers the spec.image index of the Images in the cache of the manager
+// and exposes the staleness of the cache in the mutator metrics.
+func SetupImageIndexWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1alpha1.Image{}, ImageSpecImageIndex, IndexImageSpecImage); err != nil {
+		return err
+	}
+
+	informer, err := mgr.GetCache().GetInformer(ctx, &v1alpha1.Image{})
+	if err != nil {
+		return err
+	}
+
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			// The objects of the initial list are not recent writes
+			if !isInInitialList {
+				observeImageCacheStaleness(obj)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			observeImageCacheStaleness(obj)
+		},
+	}); err != nil {
+		return err
+	}
+
+	// CacheSynced is set once the informer has listed all the Images.
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		metrics.Mutator().CacheSynced.Set(0)
+		if mgr.GetCache().WaitForCacheSync(ctx) {
+			metrics.Mutator().CacheSynced.Set(1)
+		}
+		<-ctx.Done()
+		return nil
+	}))
+}
+
+// observeImageCacheStaleness observes the delay between the last write of the Image
+// and its reception by the cache.
+// The time of the last write is the most recent time of the managed fields.
+func observeImageCacheStaleness(obj interface{}) {
+	image, ok := obj.(*v1alpha1.Image)
+	if !ok {
+		return
+	}
+
+	var lastWrite time.Time
+	for _, mf := range image.GetManagedFields() {
+		if mf.Time != nil && mf.Time.After(lastWrite) {
+			lastWrite = mf.Time.Time
+		}
+	}
+
+	if lastWrite.IsZero() {
+		return
+	}
+
+	metrics.Mutator().CacheStaleness.Observe(time.Since(lastWrite).Seconds())
+	metrics.Mutator().CacheLastEventTimestamp.SetToCurrentTime()
+}
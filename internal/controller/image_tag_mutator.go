@@ -5,33 +5,53 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/metrics"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/tracing"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/podcreate"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/utils"
 )
 
 func (i *ImageTagMutator) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	mgr.GetWebhookServer().Register("/mutate/image-tag", &webhook.Admission{Handler: i.SetupHandler()})
-	return nil
+	// The refreshes requested by the webhook are patched by the manager
+	return mgr.Add(i)
 }
 
 func (i *ImageTagMutator) SetupHandler() admission.Handler {
 	i.decoder = admission.NewDecoder(i.Scheme)
+	i.refreshes = make(chan types.NamespacedName, refreshQueueSize)
 	return i
 }
 
-// +kubebuilder:webhook:path=/mutate/image-tag,mutating=true,failurePolicy=fail,groups="",resources=pods,sideEffects=None,verbs=create;update,versions=v1,name=mutator.kimup.cloudavenue.io,admissionReviewVersions=v1
+// refreshQueueSize is the number of refreshes waiting to be patched.
+// The refreshes requested when the queue is full are dropped.
+const refreshQueueSize = 100
 
-var _ admission.Handler = &ImageTagMutator{}
+// +kubebuilder:webhook:path=/mutate/image-tag,mutating=true,failurePolicy=fail,groups="",resources=pods,sideEffects=NoneOnDryRun,verbs=create;update,versions=v1,name=mutator.kimup.cloudavenue.io,admissionReviewVersions=v1
+
+var (
+	_ admission.Handler              = &ImageTagMutator{}
+	_ manager.LeaderElectionRunnable = &ImageTagMutator{}
+)
 
 // podAnnotator annotates Pods
 type ImageTagMutator struct {
@@ -39,9 +59,19 @@ type ImageTagMutator struct {
 	KubeAPIClient *kubeclient.Client
 	Scheme        *runtime.Scheme
 	decoder       admission.Decoder
+
+	// refreshes is the queue of the Images to refresh, drained by Start
+	refreshes chan types.NamespacedName
 }
 
 func (i *ImageTagMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, span := tracing.Start(ctx, "admission.Handle",
+		attribute.String("kimup.pod.namespace", req.Namespace),
+		attribute.String("kimup.pod.name", req.Name),
+		attribute.String("kimup.admission.operation", string(req.Operation)),
+	)
+	defer span.End()
+
 	log := logf.FromContext(ctx)
 
 	pod := &corev1.Pod{}
@@ -51,11 +81,22 @@ func (i *ImageTagMutator) Handle(ctx context.Context, req admission.Request) adm
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
+	mutations := []annotations.ContainerMutation{}
+	warnings := []string{}
+	an := annotations.New(ctx, pod)
+	pin := an.Pin()
+	refreshed := map[string]bool{}
+
 	for c, container := range pod.Spec.Containers {
+		if pin.IsPinned(container.Name) {
+			log.Info(fmt.Sprintf("Container %s is pinned, keeping image %s", container.Name, container.Image))
+			continue
+		}
+
 		imageP := utils.ImageParser(container.Image)
 
 		// find the image associated with the pod
-		image, err := i.KubeAPIClient.Image().Find(ctx, pod.Namespace, imageP.GetImageWithoutTag())
+		image, err := i.findImage(ctx, pod.Namespace, imageP.GetImageWithoutTag())
 		if err != nil {
 			// increment the total number of errors
 			metrics.Mutator().PatchErrorTotal.Inc()
@@ -64,11 +105,43 @@ func (i *ImageTagMutator) Handle(ctx context.Context, req admission.Request) adm
 			continue
 		}
 
-		log.Info(fmt.Sprintf("Mutating container %s with image %s to %s", container.Name, container.Image, image.GetImageWithTag()))
+		// Refresh the image when a new Pod using it is admitted
+		if req.Operation == admissionv1.Create && !ptr.Deref(req.DryRun, false) && !refreshed[image.Name] && podcreate.ShouldRefresh(image, time.Now()) {
+			refreshed[image.Name] = true
+			i.requestRefresh(ctx, image)
+		}
 
 		// Set the image to the pod
-		if image.ImageIsEqual(container.Image) {
-			pod.Spec.Containers[c].Image = image.GetImageWithTag()
+		if !image.ImageIsEqual(container.Image) || container.Image == image.GetImageWithTag() {
+			continue
+		}
+
+		if !image.ShouldMutate(imageP.GetTag()) {
+			log.Info(fmt.Sprintf("Keeping image %s of container %s (mutation policy %s)", container.Image, container.Name, image.Spec.MutationPolicy))
+			continue
+		}
+
+		log.Info(fmt.Sprintf("Mutating container %s with image %s to %s", container.Name, container.Image, image.GetImageWithTag()))
+		pod.Spec.Containers[c].Image = image.GetImageWithTag()
+
+		mutations = append(mutations, annotations.ContainerMutation{
+			Container:     container.Name,
+			OriginalImage: container.Image,
+			Image:         image.GetImageWithTag(),
+			ImageRef:      image.Namespace + "/" + image.Name,
+			Time:          time.Now().UTC().Format(time.RFC3339),
+		})
+		warnings = append(warnings, fmt.Sprintf("image of container %s rewritten by kimup from %s to %s (Image %s/%s)", container.Name, container.Image, image.GetImageWithTag(), image.Namespace, image.Name))
+	}
+
+	if len(mutations) > 0 {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+
+		an = annotations.New(ctx, pod)
+		if err := an.Mutations().Set(mutations...); err != nil {
+			log.Error(err, "Failed to set the mutations annotation")
 		}
 	}
 
@@ -79,5 +152,69 @@ func (i *ImageTagMutator) Handle(ctx context.Context, req admission.Request) adm
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
 
-	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	resp := admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	span.SetAttributes(attribute.Int("kimup.admission.mutations", len(mutations)))
+	if len(mutations) > 0 {
+		// The audit annotations are prefixed with the name of the webhook by the API server
+		if x, err := json.Marshal(mutations); err == nil {
+			resp.AuditAnnotations = map[string]string{"mutations": string(x)}
+		}
+		resp.Warnings = warnings
+	}
+
+	return resp
+}
+
+// requestRefresh queues the refresh of the image. The admission of the Pod does not wait for the API server:
+// the refresh action is set on the image by Start and the refresh is executed by kimup.
+func (i *ImageTagMutator) requestRefresh(ctx context.Context, image v1alpha1.Image) {
+	select {
+	case i.refreshes <- types.NamespacedName{Namespace: image.Namespace, Name: image.Name}:
+	default:
+		logf.FromContext(ctx).Info("Refresh queue full, dropping the refresh of the image", "image", image.Namespace+"/"+image.Name)
+	}
+}
+
+// Start sets the refresh action on the images queued by requestRefresh until the context is done.
+func (i *ImageTagMutator) Start(ctx context.Context) error {
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, annotations.KeyAction, annotations.ActionRefresh)))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case key := <-i.refreshes:
+			image := &v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+			if err := i.Patch(ctx, image, patch); err != nil {
+				logf.FromContext(ctx).Error(err, "Failed to request the refresh of the image", "image", key.String())
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false because the webhook is served by every replica of the operator.
+func (i *ImageTagMutator) NeedLeaderElection() bool {
+	return false
+}
+
+// findImage finds the image by its image name in the cache of the manager.
+// The lookup uses the ImageSpecImageIndex of the cache, the cache is filled by the List and Watch calls of the manager.
+func (i *ImageTagMutator) findImage(ctx context.Context, namespace, imageName string) (v1alpha1.Image, error) {
+	images := &v1alpha1.ImageList{}
+	if err := i.List(ctx, images, client.InNamespace(namespace), client.MatchingFields{ImageSpecImageIndex: imageName}); err != nil {
+		return v1alpha1.Image{}, err
+	}
+
+	if len(images.Items) == 0 {
+		return v1alpha1.Image{}, fmt.Errorf("image %s %w", imageName, kubeclient.ErrNotFound)
+	}
+
+	// The Images defined in the namespace take precedence over the Images created from a ClusterImage
+	for _, image := range images.Items {
+		if !image.IsManagedByClusterImage() {
+			return image, nil
+		}
+	}
+
+	return images.Items[0], nil
 }
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/crontab"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/interval"
+)
+
+// +kubebuilder:webhook:path=/validate-kimup-cloudavenue-io-v1alpha1-image,mutating=false,failurePolicy=fail,groups=kimup.cloudavenue.io,resources=images,sideEffects=None,verbs=create;update,versions=v1alpha1,name=image.validator.kimup.cloudavenue.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &ImageValidator{}
+
+// ImageValidator rejects the Images that can not be scheduled or evaluated by kimup.
+type ImageValidator struct {
+	KubeAPIClient kubeclient.Interface
+}
+
+// SetupWebhookWithManager registers the validating webhook of the Images.
+func (v *ImageValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Image{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the Image on creation.
+func (v *ImageValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+// ValidateUpdate validates the Image on update.
+// The updates that do not change the spec, e.g. the annotations and the status written by kimup, are always accepted.
+func (v *ImageValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	if oldImage, ok := oldObj.(*v1alpha1.Image); ok {
+		if newImage, ok := newObj.(*v1alpha1.Image); ok && equality.Semantic.DeepEqual(oldImage.Spec, newImage.Spec) {
+			return nil, nil
+		}
+	}
+
+	return v.validate(ctx, newObj)
+}
+
+// ValidateDelete accepts the deletion of any Image.
+func (v *ImageValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *ImageValidator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	image, ok := obj.(*v1alpha1.Image)
+	if !ok {
+		return nil, fmt.Errorf("expected an Image but got a %T", obj)
+	}
+
+	errs, warnings := validateImageSpec(image.Name, image.Spec, field.NewPath("spec"), func(action v1alpha1.ImageAction) ([]string, error) {
+		return actions.ValidateImageAction(ctx, v.KubeAPIClient, image.Namespace, action)
+	})
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("Image").GroupKind(), image.Name, errs)
+	}
+
+	return warnings, nil
+}
+
+// validateImageSpec checks the triggers and the rules of the spec of an Image or of a ClusterImage.
+// The actions are checked by validateAction which returns the warnings and the reason why the action is not valid.
+func validateImageSpec(name string, imageSpec v1alpha1.ImageSpec, spec *field.Path, validateAction func(v1alpha1.ImageAction) ([]string, error)) (field.ErrorList, admission.Warnings) {
+	var (
+		errs     field.ErrorList
+		warnings admission.Warnings
+	)
+
+	for i, trigger := range imageSpec.Triggers {
+		path := spec.Child("triggers").Index(i)
+		switch trigger.Type {
+		case triggers.Crontab:
+			if err := crontab.Validate(trigger.Value); err != nil {
+				errs = append(errs, field.Invalid(path.Child("value"), trigger.Value, fmt.Sprintf("invalid crontab expression (6 fields with the seconds): %v", err)))
+			}
+		case triggers.Interval:
+			if trigger.Every == nil {
+				errs = append(errs, field.Required(path.Child("every"), "the interval trigger requires an interval"))
+			} else if err := interval.Validate(trigger.Every.Duration); err != nil {
+				errs = append(errs, field.Invalid(path.Child("every"), trigger.Every.Duration.String(), err.Error()))
+			}
+		case triggers.Dependency:
+			switch {
+			case trigger.ImageRef == nil || trigger.ImageRef.Name == "":
+				errs = append(errs, field.Required(path.Child("imageRef", "name"), "the dependency trigger requires the name of an Image"))
+			case trigger.ImageRef.Name == name:
+				errs = append(errs, field.Invalid(path.Child("imageRef", "name"), trigger.ImageRef.Name, "an Image can not depend on itself"))
+			}
+		case triggers.Webhook, triggers.OnPodCreate:
+		default:
+			errs = append(errs, field.NotSupported(path.Child("type"), trigger.Type, []string{
+				string(triggers.Crontab),
+				string(triggers.Webhook),
+				string(triggers.Interval),
+				string(triggers.OnPodCreate),
+				string(triggers.Dependency),
+			}))
+		}
+	}
+
+	ruleNames := map[string]bool{}
+	for i, rule := range imageSpec.Rules {
+		path := spec.Child("rules").Index(i)
+
+		if ruleNames[rule.Name] {
+			errs = append(errs, field.Duplicate(path.Child("name"), rule.Name))
+		}
+		ruleNames[rule.Name] = true
+
+		if err := rules.Validate(rule.Type, imageSpec.BaseTag, rule.Value); err != nil {
+			errs = append(errs, field.Invalid(path, rule.Type, err.Error()))
+		}
+
+		for j, action := range rule.Actions {
+			w, err := validateAction(action)
+			if err != nil {
+				errs = append(errs, field.Invalid(path.Child("actions").Index(j), action.Type, err.Error()))
+			}
+			for _, warning := range w {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", path.Child("actions").Index(j), warning))
+			}
+		}
+	}
+
+	return errs, warnings
+}
@@ -36,6 +36,9 @@ type KimupReconciler struct {
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=helmreleases,verbs=get;update;patch
+//+kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kimup.cloudavenue.io,resources=gitconfigs,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+// +kubebuilder:webhook:path=/validate-kimup-cloudavenue-io-v1alpha1-kimup,mutating=false,failurePolicy=fail,groups=kimup.cloudavenue.io,resources=kimups,sideEffects=None,verbs=create;update,versions=v1alpha1,name=kimup.validator.kimup.cloudavenue.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &KimupValidator{}
+
+// KimupValidator rejects the Kimups that can not be deployed.
+type KimupValidator struct{}
+
+// SetupWebhookWithManager registers the validating webhook of the Kimups.
+func (v *KimupValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Kimup{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the Kimup on creation.
+func (v *KimupValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate validates the Kimup on update.
+func (v *KimupValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(newObj)
+}
+
+// ValidateDelete accepts the deletion of any Kimup.
+func (v *KimupValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the image of the controller, the ports of the probes and the TLS of the REST API.
+func (v *KimupValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	ki, ok := obj.(*v1alpha1.Kimup)
+	if !ok {
+		return nil, fmt.Errorf("expected a Kimup but got a %T", obj)
+	}
+
+	var (
+		errs field.ErrorList
+		spec = field.NewPath("spec")
+	)
+
+	if ki.Spec.Image != "" {
+		if _, err := reference.ParseNormalizedNamed(ki.Spec.Image); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("image"), ki.Spec.Image, err.Error()))
+		}
+	}
+
+	metricsPort, healthzPort := models.MetricsDefaultPort, models.HealthzDefaultPort
+	if ki.Spec.Metrics.Port != 0 {
+		metricsPort = ki.Spec.Metrics.Port
+	}
+	if ki.Spec.Healthz.Port != 0 {
+		healthzPort = ki.Spec.Healthz.Port
+	}
+
+	for _, p := range []struct {
+		path *field.Path
+		port int32
+	}{
+		{spec.Child("metrics", "port"), metricsPort},
+		{spec.Child("healthz", "port"), healthzPort},
+	} {
+		for _, msg := range validation.IsValidPortNum(int(p.port)) {
+			errs = append(errs, field.Invalid(p.path, p.port, msg))
+		}
+	}
+
+	if ki.Spec.Metrics.Enabled && ki.Spec.Healthz.Enabled && metricsPort == healthzPort {
+		errs = append(errs, field.Duplicate(spec.Child("healthz", "port"), healthzPort))
+	}
+
+	// The REST API receives bearer tokens, the plain HTTP must be explicitly allowed
+	if ki.Spec.API.Enabled && ki.Spec.API.TLSSecretName == "" && !ki.Spec.API.Insecure {
+		errs = append(errs, field.Required(spec.Child("api", "tlsSecretName"), "the REST API receives bearer tokens, set the TLS secret of the API or insecure to serve it over plain HTTP"))
+	}
+
+	// The push notifications trigger the refreshes, the requests without token must be explicitly allowed
+	if ki.Spec.Events.HTTP.Enabled && ki.Spec.Events.HTTP.TokenSecretRef == nil && !ki.Spec.Events.HTTP.Insecure {
+		errs = append(errs, field.Required(spec.Child("events", "http", "tokenSecretRef"), "the push notifications trigger the refreshes of the Images, set the token of the endpoint or insecure to accept the requests without token"))
+	}
+
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("Kimup").GroupKind(), ki.Name, errs)
+	}
+
+	return nil, nil
+}
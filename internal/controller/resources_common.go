@@ -2,6 +2,8 @@ package controller
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -9,6 +11,8 @@ import (
 
 	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/eventsource"
 )
 
 type Object struct {
@@ -56,6 +60,78 @@ func buildKimupArgs(extra v1alpha1.KimupExtraSpec) (args []string) {
 		}
 
 		args = append(args, fmt.Sprintf("--%s=%s", models.MetricsPathFlagName, metricsPath))
+
+		// set the labels of the metrics of the images
+		if len(extra.MetricsLabels) > 0 {
+			args = append(args, fmt.Sprintf("--%s=%s", models.MetricsLabelsFlagName, joinMetricsLabels(extra.MetricsLabels)))
+		}
+	}
+
+	if extra.Events.HTTP.Enabled {
+		// enable the push notifications endpoint
+		args = append(args, fmt.Sprintf("--%s", models.EventsHTTPFlagName))
+
+		// set the events port
+		eventsPort := extra.Events.HTTP.Port
+		if eventsPort == 0 {
+			eventsPort = models.EventsHTTPDefaultPort
+		}
+		args = append(args, fmt.Sprintf("--%s=%d", models.EventsHTTPPortFlagName, eventsPort))
+
+		// set the events path
+		eventsPath := extra.Events.HTTP.Path
+		if eventsPath == "" {
+			eventsPath = models.EventsHTTPDefaultPath
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", models.EventsHTTPPathFlagName, eventsPath))
+
+		// the token is set in the environment by buildKimupEnv
+		if extra.Events.HTTP.TokenSecretRef == nil && extra.Events.HTTP.Insecure {
+			args = append(args, fmt.Sprintf("--%s", models.EventsHTTPInsecureFlagName))
+		}
+	}
+
+	if extra.Events.NATS != nil {
+		args = append(args, fmt.Sprintf("--%s=%s", models.EventsNATSURLFlagName, extra.Events.NATS.URL))
+
+		// set the NATS subject
+		subject := extra.Events.NATS.Subject
+		if subject == "" {
+			subject = models.EventsNATSDefaultSubject
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", models.EventsNATSSubjectFlagName, subject))
+	}
+
+	if extra.API.Enabled {
+		// enable the REST API
+		args = append(args, fmt.Sprintf("--%s", models.APIFlagName))
+		args = append(args, fmt.Sprintf("--%s=%d", models.APIPortFlagName, apiPort(extra)))
+
+		if extra.API.Dashboard {
+			args = append(args, fmt.Sprintf("--%s", models.APIDashboardFlagName))
+		}
+
+		// serve the REST API with the certificate mounted by buildKimupVolumes
+		if extra.API.TLSSecretName != "" {
+			args = append(args, fmt.Sprintf("--%s=%s", models.APITLSCertFileFlagName, path.Join(models.APITLSDefaultDir, corev1.TLSCertKey)))
+			args = append(args, fmt.Sprintf("--%s=%s", models.APITLSKeyFileFlagName, path.Join(models.APITLSDefaultDir, corev1.TLSPrivateKeyKey)))
+		} else if extra.API.Insecure {
+			args = append(args, fmt.Sprintf("--%s", models.APIInsecureFlagName))
+		}
+	}
+
+	if extra.Tracing.Exporter != "" {
+		// export the traces
+		args = append(args, fmt.Sprintf("--%s=%s", models.TracingExporterFlagName, extra.Tracing.Exporter))
+
+		if extra.Tracing.SampleRatio != "" {
+			args = append(args, fmt.Sprintf("--%s=%s", models.TracingSampleRatioFlagName, extra.Tracing.SampleRatio))
+		}
+	}
+
+	if len(extra.PatchResource.Namespaces) > 0 {
+		// allow the patch-resource action to patch the resources of these namespaces
+		args = append(args, fmt.Sprintf("--%s=%s", models.PatchResourceNamespacesFlagName, strings.Join(extra.PatchResource.Namespaces, ",")))
 	}
 
 	args = append(args, fmt.Sprintf("--%s=%s", models.LogLevelFlagName, extra.LogLevel))
@@ -63,6 +139,63 @@ func buildKimupArgs(extra v1alpha1.KimupExtraSpec) (args []string) {
 	return args
 }
 
+// buildKimupEnv returns the environment variables of the Kimup container.
+// The name and the namespace of the pod are used by the sharding of the Images across the replicas.
+// The token of the events endpoint is read from its secret.
+func buildKimupEnv(env []corev1.EnvVar, extra v1alpha1.KimupExtraSpec) []corev1.EnvVar {
+	if extra.Events.HTTP.Enabled && extra.Events.HTTP.TokenSecretRef != nil {
+		env = append([]corev1.EnvVar{{
+			Name:      eventsource.EnvHTTPToken,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: extra.Events.HTTP.TokenSecretRef},
+		}}, env...)
+	}
+
+	return append([]corev1.EnvVar{
+		{
+			Name: sharding.EnvPodName,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+			},
+		},
+		{
+			Name: sharding.EnvPodNamespace,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+			},
+		},
+	}, env...)
+}
+
+// apiTLSVolumeName is the name of the volume of the certificate of the REST API.
+const apiTLSVolumeName = "api-tls"
+
+// buildKimupVolumes returns the volumes of the Kimup pod.
+func buildKimupVolumes(extra v1alpha1.KimupExtraSpec) []corev1.Volume {
+	if !extra.API.Enabled || extra.API.TLSSecretName == "" {
+		return nil
+	}
+
+	return []corev1.Volume{{
+		Name: apiTLSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: extra.API.TLSSecretName},
+		},
+	}}
+}
+
+// buildKimupVolumeMounts returns the volume mounts of the Kimup container.
+func buildKimupVolumeMounts(extra v1alpha1.KimupExtraSpec) []corev1.VolumeMount {
+	if !extra.API.Enabled || extra.API.TLSSecretName == "" {
+		return nil
+	}
+
+	return []corev1.VolumeMount{{
+		Name:      apiTLSVolumeName,
+		MountPath: models.APITLSDefaultDir,
+		ReadOnly:  true,
+	}}
+}
+
 func buildReadinessProbe(extra v1alpha1.KimupExtraSpec) *corev1.Probe {
 	if !extra.Healthz.Enabled {
 		return nil
@@ -125,6 +258,26 @@ func buildContainerPorts(extra v1alpha1.KimupExtraSpec) (ports []corev1.Containe
 		})
 	}
 
+	if extra.Events.HTTP.Enabled {
+		// set the events port
+		eventsPort := extra.Events.HTTP.Port
+		if eventsPort == 0 {
+			eventsPort = models.EventsHTTPDefaultPort
+		}
+
+		ports = append(ports, corev1.ContainerPort{
+			Name:          models.EventsHTTPFlagName,
+			ContainerPort: eventsPort,
+		})
+	}
+
+	if extra.API.Enabled {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          models.APIFlagName,
+			ContainerPort: apiPort(extra),
+		})
+	}
+
 	return ports
 }
 
@@ -159,5 +312,46 @@ func buildServicePorts(extra v1alpha1.KimupExtraSpec) (ports []corev1.ServicePor
 		})
 	}
 
+	if extra.Events.HTTP.Enabled {
+		// set the events port
+		eventsPort := extra.Events.HTTP.Port
+		if eventsPort == 0 {
+			eventsPort = models.EventsHTTPDefaultPort
+		}
+
+		ports = append(ports, corev1.ServicePort{
+			Name:       models.EventsHTTPFlagName,
+			Port:       eventsPort,
+			TargetPort: intstr.FromString(models.EventsHTTPFlagName),
+		})
+	}
+
+	if extra.API.Enabled {
+		ports = append(ports, corev1.ServicePort{
+			Name:       models.APIFlagName,
+			Port:       apiPort(extra),
+			TargetPort: intstr.FromString(models.APIFlagName),
+		})
+	}
+
 	return ports
 }
+
+// apiPort returns the port of the REST API.
+func apiPort(extra v1alpha1.KimupExtraSpec) int32 {
+	if extra.API.Port == 0 {
+		return models.APIDefaultPort
+	}
+
+	return extra.API.Port
+}
+
+// joinMetricsLabels returns the labels of the metrics of the images separated by commas.
+func joinMetricsLabels(labels []v1alpha1.KimupMetricsLabel) string {
+	s := make([]string, len(labels))
+	for i, label := range labels {
+		s[i] = string(label)
+	}
+
+	return strings.Join(s, ",")
+}
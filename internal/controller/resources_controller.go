@@ -56,7 +56,12 @@ func GetKimupControllerResources(ctx context.Context, ki v1alpha1.Kimup) []Objec
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: utils.ToPTR(int32(1)),
+			Replicas: func() *int32 {
+				if ki.Spec.Replicas == nil {
+					return utils.ToPTR(int32(1))
+				}
+				return ki.Spec.Replicas
+			}(),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app":                     name,
@@ -90,6 +95,8 @@ func GetKimupControllerResources(ctx context.Context, ki v1alpha1.Kimup) []Objec
 							Args: func() []string {
 								return buildKimupArgs(ki.Spec.KimupExtraSpec)
 							}(),
+							Env:             buildKimupEnv(ki.Spec.Env, ki.Spec.KimupExtraSpec),
+							VolumeMounts:    buildKimupVolumeMounts(ki.Spec.KimupExtraSpec),
 							ReadinessProbe:  buildReadinessProbe(ki.Spec.KimupExtraSpec),
 							LivenessProbe:   buildLivenessProbe(ki.Spec.KimupExtraSpec),
 							ImagePullPolicy: corev1.PullIfNotPresent,
@@ -101,6 +108,7 @@ func GetKimupControllerResources(ctx context.Context, ki v1alpha1.Kimup) []Objec
 							}(),
 						},
 					},
+					Volumes:                   buildKimupVolumes(ki.Spec.KimupExtraSpec),
 					Affinity:                  ki.Spec.Affinity,
 					NodeSelector:              ki.Spec.NodeSelector,
 					Tolerations:               ki.Spec.Tolerations,
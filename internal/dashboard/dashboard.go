@@ -0,0 +1,36 @@
+// Package dashboard is the web dashboard of the kimup controller.
+// The dashboard is a static page embedded in the binary, reading the Images from the REST API
+// with the Kubernetes token of the user. The accesses are checked by the REST API.
+package dashboard
+
+import (
+	"embed"
+	"flag"
+	"io/fs"
+	"net/http"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+//go:embed static
+var static embed.FS
+
+func init() {
+	flag.Bool(models.APIDashboardFlagName, false, "Serve the web dashboard on the REST API server.")
+}
+
+// Handler returns the handler serving the files of the dashboard.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		// The static directory is embedded in the binary
+		panic(err)
+	}
+
+	fileServer := http.FileServer(http.FS(files))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		fileServer.ServeHTTP(w, r)
+	})
+}
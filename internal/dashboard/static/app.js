@@ -0,0 +1,170 @@
+// kimup dashboard: the Images are read from the REST API with the token of the user.
+"use strict";
+
+const state = { images: [], token: sessionStorage.getItem("kimup-token") || "" };
+const $ = (selector) => document.querySelector(selector);
+
+async function api(method, path) {
+  const response = await fetch(path, {
+    method,
+    headers: { Authorization: "Bearer " + state.token },
+  });
+  const body = await response.json().catch(() => ({}));
+  if (!response.ok) {
+    throw new Error(body.error || response.statusText);
+  }
+  return body;
+}
+
+function message(text, error) {
+  $("#message").textContent = text || "";
+  $("#message").className = error ? "error" : "";
+}
+
+function cell(row, text, className) {
+  const td = row.insertCell();
+  td.textContent = text || "";
+  if (className) {
+    td.className = className;
+  }
+  return td;
+}
+
+function date(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+function fillSelect(select, values) {
+  const current = select.value;
+  select.length = 1;
+  [...new Set(values)].sort().forEach((v) => select.add(new Option(v, v)));
+  select.value = current;
+}
+
+function render() {
+  const namespace = $("#namespace").value;
+  const registry = $("#registry").value;
+  const search = $("#search").value.toLowerCase();
+  const errorsOnly = $("#errors").checked;
+
+  const tbody = $("#images tbody");
+  tbody.replaceChildren();
+
+  state.images
+    .filter((i) => !namespace || i.namespace === namespace)
+    .filter((i) => !registry || i.registry === registry)
+    .filter((i) => !search || i.name.toLowerCase().includes(search) || i.image.toLowerCase().includes(search))
+    .filter((i) => !errorsOnly || i.error)
+    .forEach((image) => {
+      const row = tbody.insertRow();
+      if (image.error) {
+        row.className = "failed";
+        row.title = image.error;
+      }
+      cell(row, image.namespace);
+      cell(row, image.name);
+      cell(row, image.image);
+      cell(row, image.tag);
+      cell(row, image.latestTag);
+      cell(row, (image.pending || []).map((p) => p.tag + " (" + p.rule + ")").join(", "), "pending");
+      cell(row, image.result, image.error ? "error" : "");
+      cell(row, date(image.lastSync));
+      cell(row, date(image.nextSync));
+
+      const actions = cell(row, "");
+      const details = document.createElement("button");
+      details.textContent = "Details";
+      details.onclick = () => showDetails(image);
+      const refresh = document.createElement("button");
+      refresh.textContent = "Refresh";
+      refresh.onclick = () => refreshImage(image);
+      actions.append(details, " ", refresh);
+    });
+
+  $("#images").hidden = false;
+}
+
+async function load() {
+  try {
+    state.images = await api("GET", "../images");
+    fillSelect($("#namespace"), state.images.map((i) => i.namespace));
+    fillSelect($("#registry"), state.images.map((i) => i.registry));
+    $("#filters").hidden = false;
+    message(state.images.length + " images");
+    render();
+  } catch (e) {
+    message(e.message, true);
+  }
+}
+
+async function refreshImage(image) {
+  try {
+    await api("POST", "../images/" + encodeURIComponent(image.namespace) + "/" + encodeURIComponent(image.name) + "/refresh");
+    message("Refresh requested for " + image.namespace + "/" + image.name);
+  } catch (e) {
+    message(e.message, true);
+  }
+}
+
+async function showDetails(image) {
+  const dialog = $("#details");
+  dialog.querySelector("h2").textContent = image.namespace + "/" + image.name;
+  dialog.querySelector(".error").textContent = image.error || "";
+
+  const alerts = dialog.querySelector(".alerts tbody");
+  alerts.replaceChildren();
+  (image.notifications || []).forEach((n) => {
+    const row = alerts.insertRow();
+    cell(row, n.rule);
+    cell(row, n.action);
+    cell(row, n.tag);
+    cell(row, date(n.time));
+  });
+
+  const history = dialog.querySelector(".history tbody");
+  history.replaceChildren();
+  try {
+    const entries = await api("GET", "../images/" + encodeURIComponent(image.namespace) + "/" + encodeURIComponent(image.name) + "/history");
+    entries.forEach((h) => {
+      const row = history.insertRow();
+      cell(row, h.tag);
+      cell(row, date(h.time));
+    });
+  } catch (e) {
+    message(e.message, true);
+  }
+
+  dialog.showModal();
+}
+
+function signedIn() {
+  $("#login").hidden = !!state.token;
+  $("#logout").hidden = !state.token;
+  if (state.token) {
+    load();
+  }
+}
+
+$("#login").onsubmit = (event) => {
+  event.preventDefault();
+  state.token = $("#token").value.trim();
+  sessionStorage.setItem("kimup-token", state.token);
+  $("#token").value = "";
+  signedIn();
+};
+
+$("#logout").onclick = () => {
+  state.token = "";
+  state.images = [];
+  sessionStorage.removeItem("kimup-token");
+  $("#filters").hidden = true;
+  $("#images").hidden = true;
+  message("");
+  signedIn();
+};
+
+["#namespace", "#registry", "#errors"].forEach((s) => ($(s).onchange = render));
+$("#search").oninput = render;
+$("#reload").onclick = load;
+
+signedIn();
@@ -0,0 +1,181 @@
+package gitops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// FileFormat is the format of the file updated with the new tag.
+	FileFormat string
+)
+
+const (
+	// FormatKustomize updates the newTag of the image in the images field of a kustomization.
+	FormatKustomize FileFormat = "kustomize"
+
+	// FormatHelm updates the tag in a Helm values file.
+	FormatHelm FileFormat = "helm"
+
+	// FormatYAML updates the tag at a path in a YAML file.
+	FormatYAML FileFormat = "yaml"
+
+	// DefaultHelmKey is the path of the tag in the Helm values files.
+	DefaultHelmKey = "image.tag"
+)
+
+var (
+	ErrKeyNotFound   = errors.New("key not found")
+	ErrInvalidFormat = errors.New("invalid file format")
+)
+
+// UpdateFile updates the tag of the image in the content of the file.
+// It returns the new content and true if the content has changed.
+//
+// Parameters:
+//   - content: The content of the file.
+//   - format: The format of the file.
+//   - key: The dot separated path of the tag (helm and yaml formats).
+//   - image: The image without tag (kustomize format).
+//   - tag: The new tag.
+func UpdateFile(content []byte, format FileFormat, key, image, tag string) ([]byte, bool, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, false, fmt.Errorf("file is empty: %w", ErrKeyNotFound)
+	}
+
+	var (
+		changed bool
+		err     error
+	)
+
+	switch format {
+	case FormatKustomize, "":
+		changed, err = updateKustomization(doc.Content[0], image, tag)
+	case FormatHelm:
+		if key == "" {
+			key = DefaultHelmKey
+		}
+		changed, err = updateKey(doc.Content[0], key, tag)
+	case FormatYAML:
+		if key == "" {
+			return nil, false, fmt.Errorf("key is required for the yaml format: %w", ErrInvalidFormat)
+		}
+		changed, err = updateKey(doc.Content[0], key, tag)
+	default:
+		return nil, false, fmt.Errorf("%s: %w", format, ErrInvalidFormat)
+	}
+	if err != nil || !changed {
+		return content, false, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, false, fmt.Errorf("failed to encode file: %w", err)
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+// updateKustomization sets the newTag of the image in the images field.
+// The image is added to the images field if it is not found.
+func updateKustomization(root *yaml.Node, image, tag string) (bool, error) {
+	if root.Kind != yaml.MappingNode {
+		return false, fmt.Errorf("kustomization is not a mapping: %w", ErrInvalidFormat)
+	}
+
+	images := mappingValue(root, "images")
+	if images == nil {
+		images = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, scalar("images"), images)
+	}
+
+	for _, item := range images.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+
+		name := mappingValue(item, "name")
+		newName := mappingValue(item, "newName")
+		if (name == nil || name.Value != image) && (newName == nil || newName.Value != image) {
+			continue
+		}
+
+		return setMappingValue(item, "newTag", tag), nil
+	}
+
+	images.Content = append(images.Content, &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			scalar("name"), scalar(image),
+			scalar("newTag"), scalar(tag),
+		},
+	})
+
+	return true, nil
+}
+
+// updateKey sets the value at the dot separated path.
+func updateKey(root *yaml.Node, key, value string) (bool, error) {
+	parts := strings.Split(key, ".")
+	node := root
+	for _, part := range parts[:len(parts)-1] {
+		if node.Kind != yaml.MappingNode {
+			return false, fmt.Errorf("%s: %w", key, ErrKeyNotFound)
+		}
+		node = mappingValue(node, part)
+		if node == nil {
+			return false, fmt.Errorf("%s: %w", key, ErrKeyNotFound)
+		}
+	}
+
+	if node.Kind != yaml.MappingNode || mappingValue(node, parts[len(parts)-1]) == nil {
+		return false, fmt.Errorf("%s: %w", key, ErrKeyNotFound)
+	}
+
+	return setMappingValue(node, parts[len(parts)-1], value), nil
+}
+
+// mappingValue returns the value of the key in the mapping node.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// setMappingValue sets the scalar value of the key in the mapping node.
+// It returns true if the value has changed.
+func setMappingValue(node *yaml.Node, key, value string) bool {
+	if v := mappingValue(node, key); v != nil {
+		if v.Kind == yaml.ScalarNode && v.Value == value {
+			return false
+		}
+		v.Kind = yaml.ScalarNode
+		v.Tag = "!!str"
+		v.Value = value
+		v.Content = nil
+		return true
+	}
+
+	node.Content = append(node.Content, scalar(key), scalar(value))
+	return true
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,157 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// Provider is the git provider used to open the pull requests.
+	Provider string
+
+	// PullRequest is the pull request to open.
+	PullRequest struct {
+		// RepositoryURL is the URL of the git repository.
+		RepositoryURL string
+		// Head is the branch containing the changes.
+		Head string
+		// Base is the branch the changes are pulled into.
+		Base  string
+		Title string
+		Body  string
+	}
+)
+
+const (
+	ProviderGitHub Provider = "github"
+	ProviderGitLab Provider = "gitlab"
+	ProviderGitea  Provider = "gitea"
+
+	DefaultGitHubAPIURL = "https://api.github.com"
+	DefaultGitLabAPIURL = "https://gitlab.com/api/v4"
+)
+
+var (
+	ErrProviderNotSupported = errors.New("provider not supported")
+	ErrPullRequestFailed    = errors.New("failed to open pull request")
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// CreatePullRequest opens a pull request (merge request for GitLab) with the provider API.
+// An already existing pull request for the same branches is not considered as an error.
+// It returns the URL of the pull request when the provider returns it.
+func CreatePullRequest(ctx context.Context, provider Provider, apiURL, token string, pr PullRequest) (string, error) {
+	repository, err := repositoryPath(pr.RepositoryURL)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		endpoint string
+		payload  any
+		headers  = map[string]string{}
+	)
+
+	switch provider {
+	case ProviderGitHub:
+		if apiURL == "" {
+			apiURL = DefaultGitHubAPIURL
+		}
+		endpoint = fmt.Sprintf("%s/repos/%s/pulls", strings.TrimSuffix(apiURL, "/"), repository)
+		payload = map[string]string{"title": pr.Title, "head": pr.Head, "base": pr.Base, "body": pr.Body}
+		headers["Authorization"] = "Bearer " + token
+		headers["Accept"] = "application/vnd.github+json"
+	case ProviderGitLab:
+		if apiURL == "" {
+			apiURL = DefaultGitLabAPIURL
+		}
+		endpoint = fmt.Sprintf("%s/projects/%s/merge_requests", strings.TrimSuffix(apiURL, "/"), url.PathEscape(repository))
+		payload = map[string]string{"title": pr.Title, "source_branch": pr.Head, "target_branch": pr.Base, "description": pr.Body}
+		headers["PRIVATE-TOKEN"] = token
+	case ProviderGitea:
+		if apiURL == "" {
+			return "", fmt.Errorf("apiURL is required for gitea: %w", ErrProviderNotSupported)
+		}
+		endpoint = fmt.Sprintf("%s/repos/%s/pulls", strings.TrimSuffix(apiURL, "/"), repository)
+		payload = map[string]string{"title": pr.Title, "head": pr.Head, "base": pr.Base, "body": pr.Body}
+		headers["Authorization"] = "token " + token
+	default:
+		return "", fmt.Errorf("%s: %w", provider, ErrProviderNotSupported)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPullRequestFailed, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK:
+		var result struct {
+			HTMLURL string `json:"html_url"`
+			WebURL  string `json:"web_url"`
+		}
+		_ = json.Unmarshal(respBody, &result)
+		if result.WebURL != "" {
+			return result.WebURL, nil
+		}
+		return result.HTMLURL, nil
+	case isAlreadyExists(provider, resp.StatusCode, respBody):
+		return "", nil
+	default:
+		return "", fmt.Errorf("%w: %s: %s", ErrPullRequestFailed, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+}
+
+// isAlreadyExists returns true if the provider refused the pull request because it already exists.
+func isAlreadyExists(provider Provider, statusCode int, body []byte) bool {
+	switch provider {
+	case ProviderGitHub:
+		// GitHub returns 422 with "A pull request already exists for ..."
+		return statusCode == http.StatusUnprocessableEntity && bytes.Contains(body, []byte("already exists"))
+	case ProviderGitLab, ProviderGitea:
+		return statusCode == http.StatusConflict
+	}
+
+	return false
+}
+
+// repositoryPath returns the path of the repository (owner/name) from its URL.
+func repositoryPath(repositoryURL string) (string, error) {
+	u, err := url.Parse(repositoryURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if !strings.Contains(path, "/") {
+		return "", fmt.Errorf("invalid repository URL %s: the path must contain the owner and the name", repositoryURL)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,161 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+type (
+	// Repository is a git repository cloned in memory.
+	Repository struct {
+		repo *git.Repository
+		fs   billy.Filesystem
+		auth transport.AuthMethod
+	}
+
+	// Author is the author of the commits.
+	Author struct {
+		Name  string
+		Email string
+	}
+)
+
+const (
+	DefaultAuthorName  = "kimup"
+	DefaultAuthorEmail = "kimup@cloudavenue.io"
+	defaultUsername    = "kimup"
+)
+
+// Clone clones the branch of the repository in memory.
+// The username and password are used for the HTTP basic authentication if the password is not empty.
+func Clone(ctx context.Context, url, branch, username, password string) (*Repository, error) {
+	r := &Repository{
+		fs: memfs.New(),
+	}
+
+	if password != "" {
+		if username == "" {
+			username = defaultUsername
+		}
+		r.auth = &githttp.BasicAuth{
+			Username: username,
+			Password: password,
+		}
+	}
+
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), r.fs, &git.CloneOptions{
+		URL:           url,
+		Auth:          r.auth,
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+		SingleBranch:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone repository %s: %w", url, err)
+	}
+	r.repo = repo
+
+	return r, nil
+}
+
+// ReadFile returns the content of the file.
+func (r *Repository) ReadFile(path string) ([]byte, error) {
+	f, err := r.fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// WriteFile replaces the content of the file.
+func (r *Repository) WriteFile(path string, content []byte) error {
+	f, err := r.fs.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(content)
+	return err
+}
+
+// CreateBranch creates the branch from HEAD and checks it out.
+func (r *Repository) CreateBranch(branch string) error {
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return w.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: true,
+		Keep:   true,
+	})
+}
+
+// Commit commits the file with the message and returns the hash of the commit.
+func (r *Repository) Commit(path, message string, author Author) (string, error) {
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := w.Add(path); err != nil {
+		return "", fmt.Errorf("failed to add file %s: %w", path, err)
+	}
+
+	if author.Name == "" {
+		author.Name = DefaultAuthorName
+	}
+	if author.Email == "" {
+		author.Email = DefaultAuthorEmail
+	}
+
+	hash, err := w.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  author.Name,
+			Email: author.Email,
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return hash.String(), nil
+}
+
+// Push pushes the branch to the origin remote.
+// If force is true, the remote branch is overwritten.
+func (r *Repository) Push(ctx context.Context, branch string, force bool) error {
+	ref := plumbing.NewBranchReferenceName(branch)
+	refSpec := config.RefSpec(fmt.Sprintf("%s:%s", ref, ref))
+	if force {
+		refSpec = "+" + refSpec
+	}
+
+	err := r.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       r.auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to push branch %s: %w", branch, err)
+	}
+
+	return nil
+}
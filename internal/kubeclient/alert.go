@@ -80,3 +80,21 @@ func (a *AlertObj) Update(ctx context.Context, namespace string, alert v1alpha1.
 
 	return nil
 }
+
+// UpdateStatus updates the status of an existing alert.
+//
+// Parameters:
+//   - ctx: The context for the operation, which can be used for cancellation and deadlines.
+//   - alert: The alert object whose status needs to be updated.
+//
+// Returns:
+//   - An error if the update operation fails; otherwise, it returns nil.
+func (a *AlertObj) UpdateStatus(ctx context.Context, alert v1alpha1.AlertConfig) error {
+	u, err := encodeUnstructured(alert)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.alertClient.Namespace(alert.Namespace).UpdateStatus(ctx, u, v1.UpdateOptions{})
+	return err
+}
@@ -43,6 +43,8 @@ type (
 	InterfaceKimup interface {
 		Image() *ImageObj
 		Alert() *AlertObj
+		GitConfig() *GitConfigObj
+		Resource() *ResourceObj
 		Mutator() *MutatorObj
 	}
 
@@ -52,6 +54,7 @@ type (
 const (
 	ComponentOperator   component = "kimup-operator"
 	ComponentController component = "kimup-controller"
+	ComponentCLI        component = "kubectl-kimup"
 )
 
 func init() {
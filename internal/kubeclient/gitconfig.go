@@ -0,0 +1,54 @@
+package kubeclient
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+)
+
+type (
+	GitConfigObj struct {
+		InterfaceKubernetes
+		gitConfigClient dynamic.NamespaceableResourceInterface
+	}
+)
+
+// GitConfig() returns a git config object
+func (c *Client) GitConfig() *GitConfigObj {
+	return NewGitConfig(c)
+}
+
+func NewGitConfig(k InterfaceKubernetes) *GitConfigObj {
+	return &GitConfigObj{
+		InterfaceKubernetes: k,
+		gitConfigClient: k.DynamicResource(schema.GroupVersionResource{
+			Group:    v1alpha1.GroupVersion.Group,
+			Version:  v1alpha1.GroupVersion.Version,
+			Resource: "gitconfigs",
+		}),
+	}
+}
+
+// Get retrieves a GitConfig object by its name within the specified namespace.
+func (g *GitConfigObj) Get(ctx context.Context, namespace, name string) (v1alpha1.GitConfig, error) {
+	u, err := g.gitConfigClient.Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return v1alpha1.GitConfig{}, err
+	}
+
+	return decodeUnstructured[v1alpha1.GitConfig](u)
+}
+
+// List retrieves the list of GitConfig objects from the specified namespace.
+func (g *GitConfigObj) List(ctx context.Context, namespace string, opts v1.ListOptions) (v1alpha1.GitConfigList, error) {
+	u, err := g.gitConfigClient.Namespace(namespace).List(ctx, opts)
+	if err != nil {
+		return v1alpha1.GitConfigList{}, err
+	}
+
+	return decodeUnstructured[v1alpha1.GitConfigList](u)
+}
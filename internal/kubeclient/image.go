@@ -15,7 +15,6 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
-	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
 )
 
 type (
@@ -149,48 +148,6 @@ func (i *ImageObj) Find(ctx context.Context, namespace, imageName string) (v1alp
 	return v1alpha1.Image{}, fmt.Errorf("image %s %w", imageName, ErrNotFound)
 }
 
-// Watch watches for changes to the image object.
-// It takes a context and the namespace as parameters.
-// Returns a channel of WatchInterface[v1alpha1.Image] and an error if the operation fails.
-func (i *ImageObj) Watch(ctx context.Context) (chan WatchInterface[v1alpha1.Image], chan error, error) {
-	x, err := i.imageClient.Watch(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, nil, err
-	}
-
-	ch := make(chan WatchInterface[v1alpha1.Image])
-	chErr := make(chan error)
-	go func() {
-		defer close(chErr)
-
-		for {
-			select {
-			case <-ctx.Done():
-				close(ch)
-				x.Stop()
-				chErr <- fmt.Errorf("watch channel closed")
-				return
-			case event, ok := <-x.ResultChan():
-				if !ok {
-					close(ch)
-					chErr <- fmt.Errorf("watch channel closed")
-					return
-				}
-
-				image, err := decodeUnstructured[v1alpha1.Image](event.Object.(*unstructured.Unstructured))
-				if err != nil {
-					log.WithError(err).Error("Failed to decode image")
-					continue
-				}
-
-				ch <- WatchInterface[v1alpha1.Image]{Type: event.Type, Value: image}
-			}
-		}
-	}()
-
-	return ch, chErr, nil
-}
-
 // UpdateStatus updates the status of the image object.
 // It takes a context and a image object as parameters.
 // Returns an error if the operation fails; otherwise, it returns nil.
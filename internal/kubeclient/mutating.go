@@ -119,7 +119,7 @@ func (a *MutatorObj) buildMutatingWebhookConfiguration(svc admissionregistration
 	return admissionregistrationv1.MutatingWebhook{
 		Name:                    matchConditionBuilder.GetName(),
 		AdmissionReviewVersions: []string{"v1", "v1beta1"},
-		SideEffects:             utils.ToPTR(admissionregistrationv1.SideEffectClassNone),
+		SideEffects:             utils.ToPTR(admissionregistrationv1.SideEffectClassNoneOnDryRun),
 		ClientConfig: admissionregistrationv1.WebhookClientConfig{
 			Service: &svc,
 		},
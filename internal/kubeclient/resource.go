@@ -0,0 +1,271 @@
+package kubeclient
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+type (
+	// ResourceObj reads and patches the Helm values of the Flux HelmRelease
+	// and Argo CD Application resources.
+	ResourceObj struct {
+		InterfaceKubernetes
+	}
+)
+
+const (
+	KindHelmRelease = "HelmRelease"
+	KindApplication = "Application"
+
+	DefaultHelmReleaseAPIVersion = "helm.toolkit.fluxcd.io/v2"
+	DefaultApplicationAPIVersion = "argoproj.io/v1alpha1"
+
+	// DefaultResourcePath is the default path of the tag in the Helm values.
+	DefaultResourcePath = "image.tag"
+)
+
+var (
+	ErrResourceKindNotSupported    = errors.New("resource kind not supported")
+	ErrResourceValueNotFound       = errors.New("value not found in resource")
+	ErrResourceNamespaceNotAllowed = errors.New("resource namespace not allowed")
+
+	// resourceNamespaces is the comma separated list of the namespaces where the resources
+	// referenced by the Images of the other namespaces can be read and patched.
+	resourceNamespaces string
+)
+
+func init() {
+	flag.StringVar(&resourceNamespaces, models.PatchResourceNamespacesFlagName, "", "Comma separated list of the namespaces where the patch-resource action can patch the resources referenced by the Images of other namespaces.")
+}
+
+// ResourceNamespaceAllowed returns true if an Image of the namespace can reference a resource of the resource namespace.
+// The Images can always reference the resources of their namespace, the other namespaces must be allowed with the
+// patch-resource-namespaces flag because kimup reads and patches the resources with its own permissions.
+func ResourceNamespaceAllowed(namespace, resourceNamespace string) bool {
+	if resourceNamespace == "" || resourceNamespace == namespace {
+		return true
+	}
+
+	return slices.ContainsFunc(strings.Split(resourceNamespaces, ","), func(ns string) bool {
+		return strings.TrimSpace(ns) == resourceNamespace
+	})
+}
+
+// Resource returns a resource object
+func (c *Client) Resource() *ResourceObj {
+	return NewResource(c)
+}
+
+func NewResource(k InterfaceKubernetes) *ResourceObj {
+	return &ResourceObj{
+		InterfaceKubernetes: k,
+	}
+}
+
+// GetValue returns the value selected by the reference.
+// The namespace is used if the reference does not specify one.
+// A reference to another namespace returns `ErrResourceNamespaceNotAllowed` unless the namespace is allowed.
+func (r *ResourceObj) GetValue(ctx context.Context, namespace string, ref v1alpha1.ResourceFieldSelector) (string, error) {
+	u, err := r.get(ctx, namespace, ref)
+	if err != nil {
+		return "", err
+	}
+
+	switch ref.Kind {
+	case KindHelmRelease:
+		value, found, err := unstructured.NestedFieldNoCopy(u.Object, helmReleaseFields(ref)...)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return "", fmt.Errorf("%s: %w", resourcePath(ref), ErrResourceValueNotFound)
+		}
+		return fmt.Sprint(value), nil
+	case KindApplication:
+		parameters, _, err := unstructured.NestedSlice(u.Object, "spec", "source", "helm", "parameters")
+		if err != nil {
+			return "", err
+		}
+		for _, p := range parameters {
+			if m, ok := p.(map[string]interface{}); ok && m["name"] == resourcePath(ref) {
+				return fmt.Sprint(m["value"]), nil
+			}
+		}
+		return "", fmt.Errorf("%s: %w", resourcePath(ref), ErrResourceValueNotFound)
+	}
+
+	return "", fmt.Errorf("%s: %w", ref.Kind, ErrResourceKindNotSupported)
+}
+
+// SetValue sets the value selected by the reference and updates the resource.
+// The namespace is used if the reference does not specify one.
+// A reference to another namespace returns `ErrResourceNamespaceNotAllowed` unless the namespace is allowed.
+// It returns true if the resource has been updated.
+//
+// For a HelmRelease, the value is set at the path in spec.values.
+// For an Application, the Helm parameter named as the path is set in spec.source.helm.parameters.
+func (r *ResourceObj) SetValue(ctx context.Context, namespace string, ref v1alpha1.ResourceFieldSelector, value string) (changed bool, err error) {
+	client, err := r.client(namespace, ref)
+	if err != nil {
+		return false, err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		u, err := client.Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		switch ref.Kind {
+		case KindHelmRelease:
+			changed, err = setHelmReleaseValue(u, ref, value)
+		case KindApplication:
+			changed, err = setApplicationParameter(u, ref, value)
+		}
+		if err != nil || !changed {
+			return err
+		}
+
+		_, err = client.Update(ctx, u, metav1.UpdateOptions{})
+		return err
+	})
+
+	return changed, err
+}
+
+func (r *ResourceObj) get(ctx context.Context, namespace string, ref v1alpha1.ResourceFieldSelector) (*unstructured.Unstructured, error) {
+	client, err := r.client(namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Get(ctx, ref.Name, metav1.GetOptions{})
+}
+
+// client returns the dynamic client of the resource kind in the namespace.
+func (r *ResourceObj) client(namespace string, ref v1alpha1.ResourceFieldSelector) (dynamic.ResourceInterface, error) {
+	gvr, err := ResourceGroupVersionResource(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ResourceNamespaceAllowed(namespace, ref.Namespace) {
+		return nil, fmt.Errorf("%s: %w", ref.Namespace, ErrResourceNamespaceNotAllowed)
+	}
+
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+
+	return r.DynamicResource(gvr).Namespace(namespace), nil
+}
+
+// ResourceGroupVersionResource returns the GroupVersionResource of the referenced resource.
+func ResourceGroupVersionResource(ref v1alpha1.ResourceFieldSelector) (schema.GroupVersionResource, error) {
+	var (
+		apiVersion = ref.APIVersion
+		resource   string
+	)
+
+	switch ref.Kind {
+	case KindHelmRelease:
+		resource = "helmreleases"
+		if apiVersion == "" {
+			apiVersion = DefaultHelmReleaseAPIVersion
+		}
+	case KindApplication:
+		resource = "applications"
+		if apiVersion == "" {
+			apiVersion = DefaultApplicationAPIVersion
+		}
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("%s: %w", ref.Kind, ErrResourceKindNotSupported)
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+
+	return gv.WithResource(resource), nil
+}
+
+// setHelmReleaseValue sets the value at the path in spec.values.
+// The missing intermediate maps are created.
+func setHelmReleaseValue(u *unstructured.Unstructured, ref v1alpha1.ResourceFieldSelector, value string) (bool, error) {
+	fields := helmReleaseFields(ref)
+
+	actual, found, err := unstructured.NestedFieldNoCopy(u.Object, fields...)
+	if err != nil {
+		return false, err
+	}
+	if found && actual == value {
+		return false, nil
+	}
+
+	return true, unstructured.SetNestedField(u.Object, value, fields...)
+}
+
+// setApplicationParameter sets the Helm parameter in spec.source.helm.parameters.
+// The parameter is added if it does not exist.
+func setApplicationParameter(u *unstructured.Unstructured, ref v1alpha1.ResourceFieldSelector, value string) (bool, error) {
+	if _, found, _ := unstructured.NestedMap(u.Object, "spec", "source"); !found {
+		return false, fmt.Errorf("spec.source is required, multi-source applications are not supported: %w", ErrResourceValueNotFound)
+	}
+
+	parameters, _, err := unstructured.NestedSlice(u.Object, "spec", "source", "helm", "parameters")
+	if err != nil {
+		return false, err
+	}
+
+	name := resourcePath(ref)
+	found := false
+	for i, p := range parameters {
+		m, ok := p.(map[string]interface{})
+		if !ok || m["name"] != name {
+			continue
+		}
+		if m["value"] == value {
+			return false, nil
+		}
+		m["value"] = value
+		parameters[i] = m
+		found = true
+		break
+	}
+
+	if !found {
+		parameters = append(parameters, map[string]interface{}{
+			"name":  name,
+			"value": value,
+		})
+	}
+
+	return true, unstructured.SetNestedSlice(u.Object, parameters, "spec", "source", "helm", "parameters")
+}
+
+// helmReleaseFields returns the fields of the value in the HelmRelease.
+func helmReleaseFields(ref v1alpha1.ResourceFieldSelector) []string {
+	return append([]string{"spec", "values"}, strings.Split(resourcePath(ref), ".")...)
+}
+
+func resourcePath(ref v1alpha1.ResourceFieldSelector) string {
+	if ref.Path == "" {
+		return DefaultResourcePath
+	}
+
+	return ref.Path
+}
@@ -63,5 +63,23 @@ func (c *Client) GetValueOrValueFrom(ctx context.Context, namespace string, v v1
 		return alert, nil
 	}
 
-	return "", fmt.Errorf("ValueFrom is specified but neither SecretKeyRef nor ConfigMapKeyRef nor AlertConfigRef is set")
+	if v.ValueFrom.GitConfigRef != nil {
+		gitConfig, err := c.GitConfig().Get(ctx, namespace, v.ValueFrom.GitConfigRef.Name)
+		if err != nil {
+			return "", fmt.Errorf("error getting git config %s: %w", v.ValueFrom.GitConfigRef.Name, err)
+		}
+
+		return gitConfig, nil
+	}
+
+	if v.ValueFrom.ResourceRef != nil {
+		value, err := c.Resource().GetValue(ctx, namespace, *v.ValueFrom.ResourceRef)
+		if err != nil {
+			return "", fmt.Errorf("error getting value of %s %s: %w", v.ValueFrom.ResourceRef.Kind, v.ValueFrom.ResourceRef.Name, err)
+		}
+
+		return value, nil
+	}
+
+	return "", fmt.Errorf("ValueFrom is specified but neither SecretKeyRef nor ConfigMapKeyRef nor AlertConfigRef nor GitConfigRef nor ResourceRef is set")
 }
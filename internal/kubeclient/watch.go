@@ -1,12 +0,0 @@
-package kubeclient
-
-import (
-	"k8s.io/apimachinery/pkg/watch"
-)
-
-type (
-	WatchInterface[T any] struct {
-		Type  watch.EventType
-		Value T
-	}
-)
@@ -9,6 +9,8 @@ type (
 		ExecutedTotal      prometheus.Counter `help:"The total number of action performed."`
 		ExecutedErrorTotal prometheus.Counter `help:"The total number of action performed with error."`
 		ExecutedDuration   Histogram          `help:"The duration in seconds of action performed."`
+
+		ResultTotal *prometheus.CounterVec `labels:"namespace,image,rule,action,result" help:"The total number of actions by result (Succeeded, Failed or Skipped)."`
 	}
 )
 
@@ -9,6 +9,8 @@ type (
 		TriggeredTotal     prometheus.Counter `help:"The total number of events triggered."`
 		TriggerdErrorTotal prometheus.Counter `help:"The total number of events triggered with error."`
 		TriggeredDuration  Histogram          `help:"The duration in seconds of events triggered."`
+
+		RefreshTotal *prometheus.CounterVec `labels:"namespace,image,result" help:"The total number of refreshes of the images by result (Succeeded or Failed)."`
 	}
 )
 
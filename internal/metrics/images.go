@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	images struct {
+		VersionsBehind                        *prometheus.GaugeVec `labels:"namespace,image" help:"The number of versions available in the registry newer than the tag of the image."`
+		NewerTagAvailableTimestampSeconds     *prometheus.GaugeVec `labels:"namespace,image" help:"The timestamp in seconds when a version newer than the tag of the image has been found. Not set if the image is up to date."`
+		LastSuccessfulRefreshTimestampSeconds *prometheus.GaugeVec `labels:"namespace,image" help:"The timestamp in seconds of the last refresh of the image without error."`
+	}
+)
+
+var imagesMetrics images
+
+// Images returns a new images.
+// This is the metrics for the lag of the images, for the SLO dashboards and alerts.
+func Images() images {
+	if imagesMetrics.VersionsBehind == nil {
+		imagesMetrics = initMetrics(images{})
+	}
+
+	return imagesMetrics
+}
+
+// ObserveImage sets the lag of the image.
+// newerSince and lastSuccess are nil if the image is up to date or has never been refreshed without error.
+// The lag is not observed if the image label is disabled.
+func ObserveImage(namespace, image string, versionsBehind int, newerSince, lastSuccess *time.Time) {
+	if Label(LabelImage, image) == "" {
+		return
+	}
+
+	namespace = Label(LabelNamespace, namespace)
+	Images().VersionsBehind.WithLabelValues(namespace, image).Set(float64(versionsBehind))
+
+	if newerSince != nil {
+		Images().NewerTagAvailableTimestampSeconds.WithLabelValues(namespace, image).Set(float64(newerSince.Unix()))
+	} else {
+		Images().NewerTagAvailableTimestampSeconds.DeleteLabelValues(namespace, image)
+	}
+
+	if lastSuccess != nil {
+		Images().LastSuccessfulRefreshTimestampSeconds.WithLabelValues(namespace, image).Set(float64(lastSuccess.Unix()))
+	}
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+// Labels of the metrics of the images.
+// The values of the labels disabled with the flag are empty, so the series are aggregated.
+const (
+	LabelNamespace = "namespace"
+	LabelImage     = "image"
+	LabelRule      = "rule"
+	LabelAction    = "action"
+	LabelResult    = "result"
+)
+
+// labelsFlag is the list of the labels enabled.
+type labelsFlag []string
+
+var enabledLabels labelsFlag
+
+func init() {
+	if err := enabledLabels.Set(models.MetricsDefaultLabels); err != nil {
+		panic(err)
+	}
+	flag.Var(&enabledLabels, models.MetricsLabelsFlagName, "Comma separated labels of the metrics of the images (namespace, image, rule and action). The labels not listed are aggregated to limit the cardinality.")
+}
+
+func (l *labelsFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *labelsFlag) Set(value string) error {
+	labels := labelsFlag{}
+	for _, label := range strings.Split(value, ",") {
+		label = strings.TrimSpace(label)
+		switch label {
+		case "":
+			continue
+		case LabelNamespace, LabelImage, LabelRule, LabelAction:
+			labels = append(labels, label)
+		default:
+			return fmt.Errorf("unknown label %q", label)
+		}
+	}
+	*l = labels
+
+	return nil
+}
+
+// SetLabels sets the labels enabled, e.g. "namespace,image".
+func SetLabels(value string) error {
+	return enabledLabels.Set(value)
+}
+
+// Label returns the value of the label, or an empty value if the label is disabled.
+func Label(name, value string) string {
+	if !slices.Contains(enabledLabels, name) {
+		return ""
+	}
+
+	return value
+}
+
+// Forget deletes the series of the image, e.g. when the image is deleted or owned by another replica.
+func Forget(namespace, image string) {
+	// The series of the images are aggregated if the image label is disabled
+	if Label(LabelImage, image) == "" {
+		return
+	}
+
+	labels := prometheus.Labels{LabelNamespace: Label(LabelNamespace, namespace), LabelImage: image}
+	Events().RefreshTotal.DeletePartialMatch(labels)
+	Rules().ResultTotal.DeletePartialMatch(labels)
+	Actions().ResultTotal.DeletePartialMatch(labels)
+	Images().VersionsBehind.DeletePartialMatch(labels)
+	Images().NewerTagAvailableTimestampSeconds.DeletePartialMatch(labels)
+	Images().LastSuccessfulRefreshTimestampSeconds.DeletePartialMatch(labels)
+}
+
+// Results of the refreshes of the images.
+const (
+	ResultSucceeded = "Succeeded"
+	ResultFailed    = "Failed"
+)
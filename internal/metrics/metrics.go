@@ -79,6 +79,7 @@ func InitAll() {
 	Rules()
 	Registry()
 	Mutator()
+	Images()
 }
 
 // GetHelp returns the help text of the metric
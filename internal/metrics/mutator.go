@@ -12,6 +12,10 @@ type (
 		PatchTotal        prometheus.Counter `help:"The total number of patch action performed."`
 		PatchErrorTotal   prometheus.Counter `help:"The total number of patch action performed with error."`
 		PatchDuration     Histogram          `help:"The duration in seconds of patch in admission controller."`
+
+		CacheSynced             prometheus.Gauge `help:"1 if the image cache of the admission controller is synced, 0 otherwise."`
+		CacheStaleness          Histogram        `help:"The delay in seconds between the last write of an image and its reception by the cache of the admission controller."`
+		CacheLastEventTimestamp prometheus.Gauge `help:"The timestamp in seconds of the last image event received by the cache of the admission controller."`
 	}
 )
 
@@ -9,6 +9,8 @@ type (
 		EvaluatedTotal      prometheus.Counter `help:"The total number of rules evaluated."`
 		EvaluatedErrorTotal prometheus.Counter `help:"The total number of rules evaluated with error."`
 		EvaluatedDuration   Histogram          `help:"The duration in seconds of rules evaluated."`
+
+		ResultTotal *prometheus.CounterVec `labels:"namespace,image,rule,result" help:"The total number of rules evaluated by result (Matched, NotMatched or Error)."`
 	}
 )
 
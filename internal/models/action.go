@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 type (
 	ActionName string
 )
@@ -8,3 +10,13 @@ type (
 func (n ActionName) String() string {
 	return string(n)
 }
+
+// IsAlert returns true if the action sends an alert.
+func (n ActionName) IsAlert() bool {
+	return strings.HasPrefix(string(n), "alert-")
+}
+
+var (
+	// Used to allow the patch-resource action to patch the resources of other namespaces than the namespace of the Image
+	PatchResourceNamespacesFlagName = "patch-resource-namespaces"
+)
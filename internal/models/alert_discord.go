@@ -10,4 +10,24 @@ type (
 	AlertEmail struct {
 		v1alpha1.AlertConfig
 	}
+
+	AlertSlack struct {
+		v1alpha1.AlertConfig
+	}
+
+	AlertTeams struct {
+		v1alpha1.AlertConfig
+	}
+
+	AlertMattermost struct {
+		v1alpha1.AlertConfig
+	}
+
+	AlertTelegram struct {
+		v1alpha1.AlertConfig
+	}
+
+	AlertWebhook struct {
+		v1alpha1.AlertConfig
+	}
 )
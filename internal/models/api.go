@@ -0,0 +1,20 @@
+package models
+
+var (
+	// Used to enable the REST API of the kimup controller
+	APIFlagName = "api"
+
+	APIPortFlagName       = APIFlagName + "-port"
+	APIDefaultPort  int32 = 9083
+
+	// Used to serve the web dashboard on the REST API server
+	APIDashboardFlagName = APIFlagName + "-dashboard"
+
+	// Used to serve the REST API with TLS
+	APITLSCertFileFlagName = APIFlagName + "-tls-cert-file"
+	APITLSKeyFileFlagName  = APIFlagName + "-tls-key-file"
+	APITLSDefaultDir       = "/etc/kimup/api-tls"
+
+	// Used to serve the REST API over plain HTTP
+	APIInsecureFlagName = APIFlagName + "-insecure"
+)
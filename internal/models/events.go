@@ -0,0 +1,21 @@
+package models
+
+var (
+	// Used to enable the endpoint receiving the push notifications of the registries
+	EventsHTTPFlagName = "events-http"
+
+	EventsHTTPPortFlagName       = EventsHTTPFlagName + "-port"
+	EventsHTTPDefaultPort  int32 = 9082
+
+	EventsHTTPPathFlagName = EventsHTTPFlagName + "-path"
+	EventsHTTPDefaultPath  = "/events"
+
+	// Used to accept the push notifications without token
+	EventsHTTPInsecureFlagName = EventsHTTPFlagName + "-insecure"
+
+	// Used to subscribe to the push notifications carried by NATS
+	EventsNATSURLFlagName = "events-nats-url"
+
+	EventsNATSSubjectFlagName = "events-nats-subject"
+	EventsNATSDefaultSubject  = "registry.events"
+)
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+
+type (
+	GitCommit struct {
+		v1alpha1.GitConfig
+	}
+
+	GitPullRequest struct {
+		v1alpha1.GitConfig
+	}
+)
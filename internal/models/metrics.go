@@ -14,3 +14,9 @@ var (
 	MetricsPathFlagName = MetricsFlagName + "-path"
 	MetricsDefaultPath  = "/metrics"
 )
+
+var (
+	// Used to limit the cardinality of the metrics of the images
+	MetricsLabelsFlagName = MetricsFlagName + "-labels"
+	MetricsDefaultLabels  = "namespace,image,rule,action"
+)
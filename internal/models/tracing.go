@@ -0,0 +1,11 @@
+package models
+
+var (
+	// Used to export the traces with OpenTelemetry
+	TracingExporterFlagName = "tracing-exporter"
+
+	TracingFileFlagName = "tracing-file"
+
+	TracingSampleRatioFlagName         = "tracing-sample-ratio"
+	TracingDefaultSampleRatio  float64 = 1
+)
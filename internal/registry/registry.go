@@ -6,6 +6,9 @@ import (
 
 	"github.com/containers/image/v5/types"
 	dRegistry "github.com/crazy-max/diun/v4/pkg/registry"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/tracing"
 )
 
 var (
@@ -28,7 +31,10 @@ type (
 	}
 )
 
-func New(ctx context.Context, repo string, settings Settings) (*Repository, error) {
+func New(ctx context.Context, repo string, settings Settings) (_ *Repository, err error) {
+	_, span := tracing.Start(ctx, "registry.New", attribute.String("kimup.registry.repository", repo))
+	defer func() { tracing.End(span, err) }()
+
 	if repo == "" {
 		return nil, ErrRepoIsEmpty
 	}
@@ -62,17 +68,41 @@ func New(ctx context.Context, repo string, settings Settings) (*Repository, erro
 	return rr, nil
 }
 
-func (r *Repository) Tags() ([]string, error) {
+func (r *Repository) Tags() (_ []string, err error) {
+	_, span := tracing.Start(r.ctx, "registry.Tags", attribute.String("kimup.registry.repository", r.repo))
+	defer func() { tracing.End(span, err) }()
+
 	tags, err := r.r.Tags(dRegistry.TagsOptions{
 		Image: r.dR,
 	})
 	if err != nil {
 		return nil, err
 	}
+	span.SetAttributes(attribute.Int("kimup.registry.tags", len(tags.List)))
 
 	return tags.List, nil
 }
 
+// HasTag returns nil if the manifest of the tag can be fetched from the repository.
+// It permits to verify a tag pushed to the registry before it is listed by the registry.
+func (r *Repository) HasTag(tag string) (err error) {
+	_, span := tracing.Start(r.ctx, "registry.HasTag",
+		attribute.String("kimup.registry.repository", r.repo),
+		attribute.String("kimup.registry.tag", tag),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	image, err := dRegistry.ParseImage(dRegistry.ParseImageOptions{
+		Name: r.repo + ":" + tag,
+	})
+	if err != nil {
+		return ErrInvalidRepo
+	}
+
+	_, _, err = r.r.Manifest(image, dRegistry.Manifest{})
+	return err
+}
+
 // GetRepo returns the repository name
 func (r *Repository) GetRepo() string {
 	return r.repo
@@ -0,0 +1,144 @@
+package restapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+)
+
+type userKey struct{}
+
+// accessKey is the key of a SubjectAccessReview in the cache of the reviews.
+type accessKey struct {
+	user                  string
+	verb, namespace, name string
+}
+
+// tokenKey returns the key of a token in the cache of the reviews, the tokens are not kept in memory.
+func tokenKey(token string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(token))
+}
+
+// userIdentity returns the identity of the user, used in the key of the access reviews.
+func userIdentity(user authenticationv1.UserInfo) string {
+	identity, _ := json.Marshal(user)
+	return string(identity)
+}
+
+// authenticate is the middleware authenticating the bearer token of the requests with a TokenReview.
+// The user of the token is stored in the context of the request.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		status, err := s.reviewToken(r.Context(), token)
+		if err != nil {
+			log.WithError(err).Error("Error reviewing the token")
+			writeError(w, http.StatusInternalServerError, "error reviewing the token")
+			return
+		}
+
+		if !status.Authenticated {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, status.User)))
+	})
+}
+
+// reviewToken reviews the token with a TokenReview. The results are cached for the TTL of the reviews.
+func (s *Server) reviewToken(ctx context.Context, token string) (authenticationv1.TokenReviewStatus, error) {
+	key := tokenKey(token)
+	if status, ok := s.reviews.Get(key); ok {
+		return status.(authenticationv1.TokenReviewStatus), nil
+	}
+
+	review, err := s.k.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return authenticationv1.TokenReviewStatus{}, err
+	}
+
+	s.cacheReview(key, review.Status)
+	return review.Status, nil
+}
+
+// cacheReview caches the result of a review for the TTL of the reviews.
+func (s *Server) cacheReview(key, result any) {
+	if s.reviewTTL > 0 {
+		s.reviews.Add(key, result, s.reviewTTL)
+	}
+}
+
+// authorize returns true if the user of the request can execute the verb on the Images of the namespace.
+// An empty namespace means all the namespaces. The response is written if the user is not allowed.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, verb, namespace, name string) bool {
+	allowed, err := s.allowed(r, verb, namespace, name)
+	if err != nil {
+		log.WithError(err).Error("Error reviewing the access")
+		writeError(w, http.StatusInternalServerError, "error reviewing the access")
+		return false
+	}
+
+	if !allowed {
+		user, _ := r.Context().Value(userKey{}).(authenticationv1.UserInfo)
+		writeError(w, http.StatusForbidden, "user "+user.Username+" cannot "+verb+" images")
+		return false
+	}
+
+	return true
+}
+
+// allowed reviews the access of the user of the request to the Images with a SubjectAccessReview.
+// The results are cached for the TTL of the reviews.
+func (s *Server) allowed(r *http.Request, verb, namespace, name string) (bool, error) {
+	user, _ := r.Context().Value(userKey{}).(authenticationv1.UserInfo)
+
+	key := accessKey{user: userIdentity(user), verb: verb, namespace: namespace, name: name}
+	if allowed, ok := s.reviews.Get(key); ok {
+		return allowed.(bool), nil
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	review, err := s.k.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:     v1alpha1.GroupVersion.Group,
+				Version:   v1alpha1.GroupVersion.Version,
+				Resource:  "images",
+				Verb:      verb,
+				Namespace: namespace,
+				Name:      name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	s.cacheReview(key, review.Status.Allowed)
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,370 @@
+// Package restapi is the REST API of the kimup controller, used by the portals and the CI
+// to read the status of the Images and to drive them without kubectl access.
+//
+// The requests are authenticated with a Kubernetes bearer token (TokenReview) and authorized
+// with the RBAC of the Images (SubjectAccessReview).
+package restapi
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/crontab"
+)
+
+var (
+	Port        int
+	TLSCertFile string
+	TLSKeyFile  string
+)
+
+func init() {
+	flag.Bool(models.APIFlagName, false, "Enable the REST API server.")
+	flag.IntVar(&Port, models.APIPortFlagName, int(models.APIDefaultPort), "REST API server port.")
+	flag.StringVar(&TLSCertFile, models.APITLSCertFileFlagName, "", "Certificate of the REST API server (PEM).")
+	flag.StringVar(&TLSKeyFile, models.APITLSKeyFileFlagName, "", "Private key of the REST API server (PEM).")
+	flag.Bool(models.APIInsecureFlagName, false, "Serve the REST API over plain HTTP. The bearer tokens of the requests are sent in clear text.")
+}
+
+const (
+	// defaultReviewTTL is the time the results of the token and access reviews are cached.
+	// It is short to follow the revocations of the tokens and the changes of the RBAC.
+	defaultReviewTTL = 10 * time.Second
+
+	// reviewCacheSize is the maximum number of reviews in the cache.
+	reviewCacheSize = 1024
+)
+
+type (
+	// Server serves the REST API.
+	Server struct {
+		c client.Client
+		k kubernetes.Interface
+
+		// next returns the next scheduled refresh of the image.
+		next func(namespace, name string) (time.Time, bool)
+
+		// reviews caches the results of the TokenReviews and SubjectAccessReviews,
+		// the dashboard sends several requests per page.
+		reviews   *cache.LRUExpireCache
+		reviewTTL time.Duration
+	}
+
+	// Image is the state of an Image.
+	Image struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+		Image     string `json:"image"`
+		Registry  string `json:"registry"`
+
+		// Tag is the current tag of the image.
+		Tag string `json:"tag"`
+
+		// LatestTag is the latest version available in the registry.
+		LatestTag string `json:"latestTag,omitempty"`
+
+		// Pending are the tags found by the rules of the last sync and not applied.
+		Pending []PendingTag `json:"pending,omitempty"`
+
+		Result   v1alpha1.ImageStatusLastSync `json:"result,omitempty"`
+		LastSync string                       `json:"lastSync,omitempty"`
+		NextSync *time.Time                   `json:"nextSync,omitempty"`
+
+		// Error is the message of the Ready condition when the last sync failed.
+		Error string `json:"error,omitempty"`
+
+		// Notifications are the alerts sent for the rules of the image.
+		Notifications []v1alpha1.ImageStatusNotification `json:"notifications,omitempty"`
+	}
+
+	// PendingTag is a tag found by a rule and not applied.
+	PendingTag struct {
+		Rule string `json:"rule"`
+		Tag  string `json:"tag"`
+	}
+
+	// ScheduledRefresh is the next scheduled refresh of an Image.
+	ScheduledRefresh struct {
+		Namespace string    `json:"namespace"`
+		Name      string    `json:"name"`
+		Next      time.Time `json:"next"`
+	}
+
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+// New returns the server reading the Images with c and reviewing the tokens and the accesses with k.
+func New(c client.Client, k kubernetes.Interface) *Server {
+	return &Server{
+		c:         c,
+		k:         k,
+		next:      crontab.NextImageRunTime,
+		reviews:   cache.NewLRUExpireCache(reviewCacheSize),
+		reviewTTL: defaultReviewTTL,
+	}
+}
+
+// WithReviewTTL replaces the time the results of the token and access reviews are cached.
+// The results are not cached if ttl is zero.
+func (s *Server) WithReviewTTL(ttl time.Duration) *Server {
+	s.reviewTTL = ttl
+	return s
+}
+
+// WithSchedule replaces the function returning the next scheduled refresh of an image.
+func (s *Server) WithSchedule(next func(namespace, name string) (time.Time, bool)) *Server {
+	s.next = next
+	return s
+}
+
+// Routes mounts the endpoints of the API on the router.
+func (s *Server) Routes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(s.authenticate)
+
+		r.Get("/images", s.listImages)
+		r.Get("/images/{namespace}/{name}", s.getImage)
+		r.Get("/images/{namespace}/{name}/history", s.getHistory)
+		r.Post("/images/{namespace}/{name}/refresh", s.refreshImage)
+		r.Post("/images/{namespace}/{name}/approve", s.approveImage)
+		r.Get("/schedule", s.getSchedule)
+	})
+}
+
+// listImages returns the Images of the namespace of the query, or of all the namespaces.
+func (s *Server) listImages(w http.ResponseWriter, r *http.Request) {
+	images, ok := s.list(w, r)
+	if !ok {
+		return
+	}
+
+	response := make([]Image, 0, len(images))
+	for _, image := range images {
+		response = append(response, s.newImage(image))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getImage(w http.ResponseWriter, r *http.Request) {
+	image, ok := s.get(w, r, "get")
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.newImage(image))
+}
+
+// getHistory returns the last tags applied to the Image, the most recent first.
+func (s *Server) getHistory(w http.ResponseWriter, r *http.Request) {
+	image, ok := s.get(w, r, "get")
+	if !ok {
+		return
+	}
+
+	history := image.Status.History
+	if history == nil {
+		history = []v1alpha1.ImageStatusHistory{}
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// refreshImage sets the refresh action annotation on the Image.
+func (s *Server) refreshImage(w http.ResponseWriter, r *http.Request) {
+	image, ok := s.get(w, r, "patch")
+	if !ok {
+		return
+	}
+
+	patch := client.MergeFrom(image.DeepCopy())
+	if image.Annotations == nil {
+		image.Annotations = map[string]string{}
+	}
+	image.Annotations[string(annotations.KeyAction)] = string(annotations.ActionRefresh)
+
+	if err := s.c.Patch(r.Context(), &image, patch); err != nil {
+		log.WithError(err).Error("Error refreshing the image")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, s.newImage(image))
+}
+
+// approveImage approves the pending request of the Image.
+// The request-approval action does not create requests yet, so there is nothing to approve.
+func (s *Server) approveImage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.get(w, r, "patch"); !ok {
+		return
+	}
+
+	writeError(w, http.StatusNotImplemented, "the request-approval action is not supported yet")
+}
+
+// getSchedule returns the next scheduled refreshes of the Images, the earliest first.
+func (s *Server) getSchedule(w http.ResponseWriter, r *http.Request) {
+	images, ok := s.list(w, r)
+	if !ok {
+		return
+	}
+
+	schedule := []ScheduledRefresh{}
+	for _, image := range images {
+		if next, ok := s.next(image.Namespace, image.Name); ok {
+			schedule = append(schedule, ScheduledRefresh{Namespace: image.Namespace, Name: image.Name, Next: next})
+		}
+	}
+	slices.SortFunc(schedule, func(a, b ScheduledRefresh) int {
+		return a.Next.Compare(b.Next)
+	})
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// list returns the Images of the namespace of the query if the user can list them.
+// Without namespace, the user without access to all the namespaces gets the Images of the namespaces they can list,
+// so the users with the RBAC of some namespaces only can use the API and the dashboard without selecting a namespace.
+func (s *Server) list(w http.ResponseWriter, r *http.Request) ([]v1alpha1.Image, bool) {
+	namespace := r.URL.Query().Get("namespace")
+	if namespace != "" && !s.authorize(w, r, "list", namespace, "") {
+		return nil, false
+	}
+
+	var images v1alpha1.ImageList
+	if err := s.c.List(r.Context(), &images, client.InNamespace(namespace)); err != nil {
+		log.WithError(err).Error("Error listing the images")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if namespace != "" {
+		return images.Items, true
+	}
+
+	allowed, err := s.allowed(r, "list", "", "")
+	if err != nil {
+		log.WithError(err).Error("Error reviewing the access")
+		writeError(w, http.StatusInternalServerError, "error reviewing the access")
+		return nil, false
+	}
+	if allowed {
+		return images.Items, true
+	}
+
+	// The access is reviewed once per namespace of the Images
+	namespaces := map[string]bool{}
+	filtered := []v1alpha1.Image{}
+	for _, image := range images.Items {
+		allowed, reviewed := namespaces[image.Namespace]
+		if !reviewed {
+			if allowed, err = s.allowed(r, "list", image.Namespace, ""); err != nil {
+				log.WithError(err).Error("Error reviewing the access")
+				writeError(w, http.StatusInternalServerError, "error reviewing the access")
+				return nil, false
+			}
+			namespaces[image.Namespace] = allowed
+		}
+		if allowed {
+			filtered = append(filtered, image)
+		}
+	}
+
+	return filtered, true
+}
+
+// get returns the Image of the path if the user can execute the verb on it.
+func (s *Server) get(w http.ResponseWriter, r *http.Request, verb string) (v1alpha1.Image, bool) {
+	key := types.NamespacedName{
+		Namespace: chi.URLParam(r, "namespace"),
+		Name:      chi.URLParam(r, "name"),
+	}
+
+	if !s.authorize(w, r, verb, key.Namespace, key.Name) {
+		return v1alpha1.Image{}, false
+	}
+
+	var image v1alpha1.Image
+	if err := s.c.Get(r.Context(), key, &image); err != nil {
+		if apierrors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, "image "+key.String()+" not found")
+			return v1alpha1.Image{}, false
+		}
+		log.WithError(err).Error("Error getting the image")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return v1alpha1.Image{}, false
+	}
+
+	return image, true
+}
+
+func (s *Server) newImage(image v1alpha1.Image) Image {
+	i := Image{
+		Namespace: image.Namespace,
+		Name:      image.Name,
+		Image:     image.Spec.Image,
+		Registry:  registry(image.Spec.Image),
+		Tag:       image.Status.Tag,
+		LatestTag: image.Status.LatestTag,
+		Result:    image.Status.Result,
+		LastSync:  image.Status.Time,
+
+		Notifications: image.Status.Notifications,
+	}
+
+	if ready := image.GetCondition(v1alpha1.ImageConditionReady); ready != nil && ready.Status == metav1.ConditionFalse {
+		i.Error = ready.Message
+	}
+
+	for _, rule := range image.Status.Rules {
+		if rule.Result == v1alpha1.ImageRuleResultMatched && rule.NewTag != "" && rule.NewTag != image.Status.Tag {
+			i.Pending = append(i.Pending, PendingTag{Rule: rule.Name, Tag: rule.NewTag})
+		}
+	}
+
+	if next, ok := s.next(image.Namespace, image.Name); ok {
+		i.NextSync = &next
+	}
+
+	return i
+}
+
+// registry returns the registry of the image (e.g. docker.io for nginx).
+func registry(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+
+	return reference.Domain(named)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Error writing the response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -2,5 +2,13 @@ package rules
 
 import "errors"
 
-// ErrRuleNotFound is returned when a rule is not found
-var ErrRuleNotFound = errors.New("rule not found")
+var (
+	// ErrRuleNotFound is returned when a rule is not found
+	ErrRuleNotFound = errors.New("rule not found")
+
+	// ErrInvalidBaseTag is returned when the base tag can not be evaluated by the rule
+	ErrInvalidBaseTag = errors.New("invalid base tag")
+
+	// ErrInvalidValue is returned when the value of the rule is not valid
+	ErrInvalidValue = errors.New("invalid rule value")
+)
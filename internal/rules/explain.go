@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/shipengqi/vc"
+)
+
+// Explainer is implemented by the rules able to explain why a tag is rejected.
+// The rule must be initialized with Init before calling Explain.
+type Explainer interface {
+	// Explain returns why the tag is rejected by the rule,
+	// or an empty string if the tag satisfies the rule.
+	Explain(tag string) string
+}
+
+var (
+	_ Explainer = &semverMajor{}
+	_ Explainer = &semverMinor{}
+	_ Explainer = &semverPatch{}
+	_ Explainer = &calverMajor{}
+	_ Explainer = &calverMinor{}
+	_ Explainer = &calverPatch{}
+	_ Explainer = &calverPrerelease{}
+	_ Explainer = &regex{}
+	_ Explainer = &always{}
+)
+
+// ! semver rules
+
+func (s *semverMajor) Explain(tag string) string {
+	return explainSemver(s.actualTag, tag, func(x *vc.Semver) string {
+		return fmt.Sprintf(">=%s", x.IncMajor())
+	})
+}
+
+func (s *semverMinor) Explain(tag string) string {
+	return explainSemver(s.actualTag, tag, func(x *vc.Semver) string {
+		return fmt.Sprintf(">=%s <%s", x.IncMinor(), x.IncMajor())
+	})
+}
+
+func (s *semverPatch) Explain(tag string) string {
+	return explainSemver(s.actualTag, tag, func(x *vc.Semver) string {
+		return fmt.Sprintf(">=%s <%s", x.IncPatch(), x.IncMinor())
+	})
+}
+
+// explainSemver checks the tag against the constraint built from the actual tag.
+func explainSemver(actualTag, tag string, constraint func(x *vc.Semver) string) string {
+	x, err := vc.NewSemverStr(actualTag)
+	if err != nil {
+		return fmt.Sprintf("the current tag %s is not a semantic version", actualTag)
+	}
+
+	if _, err := vc.NewSemverStr(tag); err != nil {
+		return "not a semantic version"
+	}
+
+	c := constraint(x)
+	v, err := vc.NewConstraint(c, funcParseSemVer)
+	if err != nil {
+		return err.Error()
+	}
+
+	if ok, _ := v.CheckString(tag); !ok {
+		return fmt.Sprintf("does not satisfy %s", c)
+	}
+
+	return ""
+}
+
+// ! calver rules
+
+func (c *calverMajor) Explain(tag string) string {
+	return explainCalver(c.actualTag, tag, false, func(x *vc.CalVer) string {
+		return fmt.Sprintf(">=%s", x.IncMajor())
+	})
+}
+
+func (c *calverMinor) Explain(tag string) string {
+	return explainCalver(c.actualTag, tag, false, func(x *vc.CalVer) string {
+		return fmt.Sprintf(">=%s <%s", x.IncMinor(), x.IncMajor())
+	})
+}
+
+func (c *calverPatch) Explain(tag string) string {
+	return explainCalver(c.actualTag, tag, false, func(x *vc.CalVer) string {
+		return fmt.Sprintf(">=%s <%s", x.IncPatch(), x.IncMinor())
+	})
+}
+
+func (c *calverPrerelease) Explain(tag string) string {
+	return explainCalver(c.actualTag, tag, true, func(x *vc.CalVer) string {
+		return fmt.Sprintf(">%s-%s", x.Version(), x.Prerelease())
+	})
+}
+
+// explainCalver checks the prerelease of the tag and the tag against the constraint built from the actual tag.
+func explainCalver(actualTag, tag string, prerelease bool, constraint func(x *vc.CalVer) string) string {
+	x, err := vc.NewCalVerStr(actualTag)
+	if err != nil {
+		return fmt.Sprintf("the current tag %s is not a calendar version", actualTag)
+	}
+
+	cv, err := vc.NewCalVerStr(tag)
+	if err != nil {
+		return "not a calendar version"
+	}
+
+	switch {
+	case prerelease && cv.Prerelease() == "":
+		return "not a prerelease"
+	case !prerelease && cv.Prerelease() != "":
+		return "prerelease"
+	}
+
+	c := constraint(x)
+	v, err := vc.NewConstraint(c, funcParseCalver)
+	if err != nil {
+		return err.Error()
+	}
+
+	if !v.Check(cv) {
+		return fmt.Sprintf("does not satisfy %s", c)
+	}
+
+	return ""
+}
+
+// ! regex rule
+
+func (r *regex) Explain(tag string) string {
+	if tag == r.actualTag {
+		return "current tag"
+	}
+
+	re, err := regexp.Compile(r.value)
+	if err != nil || r.value == "" {
+		return "invalid regular expression"
+	}
+
+	if !re.MatchString(tag) {
+		return fmt.Sprintf("does not match %s", r.value)
+	}
+
+	return ""
+}
+
+// ! always rule
+
+func (a *always) Explain(tag string) string {
+	if tag == a.actualTag {
+		return "current tag"
+	}
+
+	return ""
+}
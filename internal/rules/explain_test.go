@@ -0,0 +1,44 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      rules.Name
+		actualTag string
+		value     string
+		tag       string
+		want      string
+	}{
+		{name: "semver satisfied", rule: rules.SemverMinor, actualTag: "v1.0.0", tag: "v1.1.0", want: ""},
+		{name: "semver constraint", rule: rules.SemverMinor, actualTag: "v1.0.0", tag: "v2.0.0", want: "does not satisfy >=1.1.0 <2.0.0"},
+		{name: "semver invalid tag", rule: rules.SemverPatch, actualTag: "v1.0.0", tag: "latest", want: "not a semantic version"},
+		{name: "semver invalid actual tag", rule: rules.SemverMajor, actualTag: "latest", tag: "v1.0.0", want: "the current tag latest is not a semantic version"},
+		{name: "calver prerelease", rule: rules.CalverMinor, actualTag: "2024.1.0", tag: "2024.2.0-dev.1", want: "prerelease"},
+		{name: "calver not prerelease", rule: rules.CalverPrerelease, actualTag: "2024.1.0-dev.1", tag: "2024.2.0", want: "not a prerelease"},
+		{name: "calver satisfied", rule: rules.CalverMinor, actualTag: "2024.1.0", tag: "2024.2.0", want: ""},
+		{name: "regex not matched", rule: rules.Regex, actualTag: "v1", value: "^v2", tag: "v3", want: "does not match ^v2"},
+		{name: "regex current tag", rule: rules.Regex, actualTag: "v2", value: "^v2", tag: "v2", want: "current tag"},
+		{name: "always", rule: rules.Always, actualTag: "v1", tag: "v2", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := rules.GetRule(tt.rule)
+			require.NoError(t, err)
+			r.Init(tt.actualTag, nil, tt.value)
+
+			explainer, ok := r.(rules.Explainer)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, explainer.Explain(tt.tag))
+		})
+	}
+}
@@ -115,3 +115,15 @@ func (r *rule) GetNewTag() string {
 func (r Name) String() string {
 	return string(r)
 }
+
+// Evaluate evaluates the rule against the available tags without executing any action.
+// It returns true and the new tag if the rule matches.
+func Evaluate(name Name, actualTag string, tagsAvailable []string, value string) (matchWithRule bool, newTag string, err error) {
+	r, err := GetRule(name)
+	if err != nil {
+		return false, "", err
+	}
+
+	r.Init(actualTag, tagsAvailable, value)
+	return r.Evaluate()
+}
@@ -0,0 +1,23 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+)
+
+func TestEvaluate(t *testing.T) {
+	match, newTag, err := rules.Evaluate(rules.SemverMinor, "v1.0.0", []string{"v1.0.1", "v1.1.0", "v2.0.0"}, "")
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.Equal(t, "v1.1.0", newTag)
+
+	match, _, err = rules.Evaluate(rules.SemverMinor, "v1.1.0", []string{"v1.0.1", "v1.1.0"}, "")
+	assert.NoError(t, err)
+	assert.False(t, match)
+
+	_, _, err = rules.Evaluate("unknown", "v1.0.0", nil, "")
+	assert.ErrorIs(t, err, rules.ErrRuleNotFound)
+}
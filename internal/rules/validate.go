@@ -0,0 +1,45 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/shipengqi/vc"
+)
+
+// Validate checks that the rule can be evaluated from the base tag of the image with the value.
+// The semver and calver rules require a base tag in the format of the rule and the regex rule
+// requires a valid regular expression.
+//
+// Parameters:
+//   - name: The name of the rule.
+//   - baseTag: The base tag of the image.
+//   - value: The value of the rule.
+//
+// Returns:
+//   - error: `ErrRuleNotFound` if the rule does not exist, `ErrInvalidBaseTag` or `ErrInvalidValue` otherwise.
+func Validate(name Name, baseTag, value string) error {
+	if _, err := GetRule(name); err != nil {
+		return err
+	}
+
+	switch name {
+	case SemverMajor, SemverMinor, SemverPatch:
+		if _, err := vc.NewSemverStr(baseTag); err != nil {
+			return fmt.Errorf("%w: %q is not a semantic version required by the rule %s", ErrInvalidBaseTag, baseTag, name)
+		}
+	case CalverMajor, CalverMinor, CalverPatch, CalverPrerelease:
+		if _, err := vc.NewCalVerStr(baseTag); err != nil {
+			return fmt.Errorf("%w: %q is not a calendar version required by the rule %s", ErrInvalidBaseTag, baseTag, name)
+		}
+	case Regex:
+		if value == "" {
+			return fmt.Errorf("%w: the rule %s requires a regular expression", ErrInvalidValue, name)
+		}
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidValue, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        rules.Name
+		baseTag     string
+		value       string
+		expectedErr error
+	}{
+		{
+			name:    "Semver with a semver base tag",
+			rule:    rules.SemverMinor,
+			baseTag: "v1.0.0",
+		},
+		{
+			name:        "Semver with latest",
+			rule:        rules.SemverPatch,
+			baseTag:     "latest",
+			expectedErr: rules.ErrInvalidBaseTag,
+		},
+		{
+			name:    "Calver with a calver base tag",
+			rule:    rules.CalverMajor,
+			baseTag: "2024.10.1",
+		},
+		{
+			name:        "Calver with a semver base tag",
+			rule:        rules.CalverMinor,
+			baseTag:     "v1.0.0-beta",
+			expectedErr: rules.ErrInvalidBaseTag,
+		},
+		{
+			name:    "Valid regex",
+			rule:    rules.Regex,
+			baseTag: "latest",
+			value:   `^v1\.0\.\d+$`,
+		},
+		{
+			name:        "Invalid regex",
+			rule:        rules.Regex,
+			value:       `^(v1\.0\.\d+$`,
+			expectedErr: rules.ErrInvalidValue,
+		},
+		{
+			name:        "Empty regex",
+			rule:        rules.Regex,
+			expectedErr: rules.ErrInvalidValue,
+		},
+		{
+			name:    "Always",
+			rule:    rules.Always,
+			baseTag: "latest",
+		},
+		{
+			name:        "Unknown rule",
+			rule:        "unknown",
+			expectedErr: rules.ErrRuleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rules.Validate(tt.rule, tt.baseTag, tt.value)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
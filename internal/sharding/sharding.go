@@ -0,0 +1,283 @@
+// Package sharding distributes the Images across the replicas of the kimup controller.
+//
+// Each replica renews a Lease labelled with the shard group. The replicas with a valid
+// Lease are the members of the group and each key (namespace/name) is owned by one member
+// chosen by rendezvous hashing, so only a part of the keys moves when a member joins or leaves.
+package sharding
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/utils"
+)
+
+type (
+	// Sharder maintains the membership of the replica in the shard group.
+	Sharder struct {
+		client    kubernetes.Interface
+		namespace string
+		identity  string
+		group     string
+
+		leaseDuration time.Duration
+		renewInterval time.Duration
+
+		mu        sync.RWMutex
+		members   []string
+		listeners []func(members []string)
+		// renewed is the time of the last successful renewal of the Lease of the replica
+		renewed time.Time
+	}
+
+	// Option configures the Sharder.
+	Option func(*Sharder)
+)
+
+const (
+	// LabelShardGroup is the label of the Leases of the members of a shard group.
+	LabelShardGroup = "kimup.cloudavenue.io/shard-group"
+
+	DefaultGroup         = "kimup-controller"
+	DefaultLeaseDuration = 30 * time.Second
+	DefaultRenewInterval = 10 * time.Second
+
+	// EnvPodName and EnvPodNamespace are the environment variables used to identify the replica.
+	EnvPodName      = "POD_NAME"
+	EnvPodNamespace = "POD_NAMESPACE"
+)
+
+// New returns a Sharder for the replica identified by identity.
+// The Leases are stored in the namespace.
+func New(client kubernetes.Interface, namespace, identity string, opts ...Option) *Sharder {
+	s := &Sharder{
+		client:        client,
+		namespace:     namespace,
+		identity:      identity,
+		group:         DefaultGroup,
+		leaseDuration: DefaultLeaseDuration,
+		renewInterval: DefaultRenewInterval,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithGroup sets the name of the shard group.
+func WithGroup(group string) Option {
+	return func(s *Sharder) {
+		s.group = group
+	}
+}
+
+// WithLeaseDuration sets the duration after which a member that has not renewed its Lease is removed.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(s *Sharder) {
+		s.leaseDuration = d
+	}
+}
+
+// WithRenewInterval sets the interval of the renewal of the Lease.
+func WithRenewInterval(d time.Duration) Option {
+	return func(s *Sharder) {
+		s.renewInterval = d
+	}
+}
+
+// OnChange registers a function called with the new members when the membership changes.
+func (s *Sharder) OnChange(f func(members []string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, f)
+}
+
+// Identity returns the identity of the replica.
+func (s *Sharder) Identity() string {
+	return s.identity
+}
+
+// Members returns the members of the shard group.
+func (s *Sharder) Members() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.members)
+}
+
+// IsOwner returns true if the replica owns the key.
+// The replica owns all the keys until the membership is known or if the sharding is disabled (nil Sharder).
+// It owns no key once its Lease has expired without a successful renewal, the other members own its keys.
+func (s *Sharder) IsOwner(key string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.members) == 0 {
+		return true
+	}
+
+	if time.Since(s.renewed) > s.leaseDuration {
+		return false
+	}
+
+	return Owner(s.members, key) == s.identity
+}
+
+// Start renews the Lease of the replica and refreshes the members until the context is done.
+// The first membership is known when Start returns. The Lease is deleted when the context is done.
+func (s *Sharder) Start(ctx context.Context) error {
+	if err := s.sync(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.renewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Leave the group to move the keys to the other members without waiting the expiration
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := s.client.CoordinationV1().Leases(s.namespace).Delete(ctx, s.leaseName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+					log.WithError(err).Error("Error deleting the shard lease")
+				}
+				return
+			case <-ticker.C:
+				if err := s.sync(ctx); err != nil {
+					log.WithError(err).Error("Error synchronizing the shard members")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// sync renews the Lease of the replica and refreshes the members.
+func (s *Sharder) sync(ctx context.Context) error {
+	renewed := time.Now()
+	if err := s.renew(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.renewed = renewed
+	s.mu.Unlock()
+
+	leases, err := s.client.CoordinationV1().Leases(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: LabelShardGroup + "=" + s.group,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	members := []string{}
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expire := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if expire.After(now) {
+			members = append(members, *lease.Spec.HolderIdentity)
+		}
+	}
+	slices.Sort(members)
+
+	s.mu.Lock()
+	if slices.Equal(s.members, members) {
+		s.mu.Unlock()
+		return nil
+	}
+	s.members = members
+	listeners := slices.Clone(s.listeners)
+	s.mu.Unlock()
+
+	log.WithFields(logrus.Fields{
+		"identity": s.identity,
+		"members":  members,
+	}).Info("Shard members changed")
+
+	for _, f := range listeners {
+		f(slices.Clone(members))
+	}
+
+	return nil
+}
+
+// renew creates or renews the Lease of the replica.
+func (s *Sharder) renew(ctx context.Context) error {
+	leases := s.client.CoordinationV1().Leases(s.namespace)
+	now := metav1.NewMicroTime(time.Now())
+
+	lease, err := leases.Get(ctx, s.leaseName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.leaseName(),
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					LabelShardGroup: s.group,
+				},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       utils.ToPTR(s.identity),
+				LeaseDurationSeconds: utils.ToPTR(int32(s.leaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+func (s *Sharder) leaseName() string {
+	return s.group + "-" + s.identity
+}
+
+// Owner returns the member owning the key with the rendezvous hashing.
+// It returns an empty string if there is no member.
+func Owner(members []string, key string) string {
+	var (
+		owner string
+		best  uint64
+	)
+
+	for _, member := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(member))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(key))
+		if score := h.Sum64(); owner == "" || score > best {
+			owner, best = member, score
+		}
+	}
+
+	return owner
+}
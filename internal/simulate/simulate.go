@@ -0,0 +1,161 @@
+// Package simulate evaluates the rules of an Image against a list of tags without executing the actions.
+// The rules are evaluated as the scheduler of kimup does, and the rejected tags are explained.
+package simulate
+
+import (
+	"slices"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+)
+
+type (
+	// Result is the result of the simulation of an Image.
+	Result struct {
+		// Image is the image of the Image.
+		Image string `json:"image"`
+
+		// Tag is the current tag used by the first rule.
+		Tag string `json:"tag"`
+
+		// Tags is the number of available tags.
+		Tags int `json:"tags"`
+
+		// NewTag is the tag applied by the apply actions, empty if the tag is unchanged.
+		NewTag string `json:"newTag,omitempty"`
+
+		// Rules are the results of the rules in the order of the Image.
+		Rules []RuleResult `json:"rules"`
+	}
+
+	// RuleResult is the result of a rule.
+	RuleResult struct {
+		// Name is the name of the rule.
+		Name string `json:"name"`
+
+		// Type is the type of the rule.
+		Type rules.Name `json:"type"`
+
+		// Tag is the current tag used to evaluate the rule.
+		Tag string `json:"tag"`
+
+		// Result is the result of the evaluation.
+		Result v1alpha1.ImageRuleResult `json:"result"`
+
+		// NewTag is the tag chosen by the rule when it matched.
+		NewTag string `json:"newTag,omitempty"`
+
+		// Error is the error of the evaluation.
+		Error string `json:"error,omitempty"`
+
+		// Actions are the types of the actions executed by the scheduler when the rule matched.
+		Actions []string `json:"actions,omitempty"`
+
+		// Rejected are the tags not chosen by the rule with the reason.
+		Rejected []Rejection `json:"rejected,omitempty"`
+	}
+
+	// Rejection is a tag not chosen by a rule.
+	Rejection struct {
+		Tag    string `json:"tag"`
+		Reason string `json:"reason"`
+	}
+)
+
+// Simulate evaluates the rules of the image against the tags.
+// The current tag is the tag of the status of the image, or the base tag if the status is empty.
+// As in the scheduler, a matching rule with an apply action changes the current tag of the following rules.
+func Simulate(image v1alpha1.Image, tags []string) Result {
+	tag := image.Status.Tag
+	if tag == "" {
+		tag = image.Spec.BaseTag
+	}
+
+	// The rules share and sort the same list of tags, as in the scheduler
+	tags = slices.Clone(tags)
+
+	result := Result{
+		Image: image.Spec.Image,
+		Tag:   tag,
+		Tags:  len(tags),
+		Rules: []RuleResult{},
+	}
+
+	for _, rule := range image.Spec.Rules {
+		ruleResult := RuleResult{
+			Name:   rule.Name,
+			Type:   rule.Type,
+			Tag:    tag,
+			Result: v1alpha1.ImageRuleResultNotMatched,
+		}
+
+		r, err := rules.GetRule(rule.Type)
+		if err != nil {
+			ruleResult.Result = v1alpha1.ImageRuleResultError
+			ruleResult.Error = err.Error()
+			result.Rules = append(result.Rules, ruleResult)
+			continue
+		}
+
+		r.Init(tag, tags, rule.Value)
+		match, newTag, err := r.Evaluate()
+		switch {
+		case err != nil:
+			ruleResult.Result = v1alpha1.ImageRuleResultError
+			ruleResult.Error = err.Error()
+		case match:
+			ruleResult.Result = v1alpha1.ImageRuleResultMatched
+			ruleResult.NewTag = newTag
+			for _, action := range rule.Actions {
+				ruleResult.Actions = append(ruleResult.Actions, action.Type)
+				if action.Type == string(actions.Apply) && newTag != "" {
+					tag = newTag
+					result.NewTag = newTag
+				}
+			}
+		}
+
+		if err == nil {
+			ruleResult.Rejected = rejections(r, tags, newTag)
+		}
+
+		result.Rules = append(result.Rules, ruleResult)
+	}
+
+	return result
+}
+
+// rejections returns the tags not chosen by the rule with the reason.
+func rejections(r rules.RuleInterface, tags []string, chosen string) []Rejection {
+	explainer, ok := r.(rules.Explainer)
+	if !ok {
+		return nil
+	}
+
+	rejected := []Rejection{}
+	for _, t := range tags {
+		if t == chosen {
+			continue
+		}
+
+		reason := explainer.Explain(t)
+		if reason == "" {
+			if chosen == "" {
+				reason = "satisfies the rule"
+			} else {
+				reason = "satisfies the rule, " + chosen + " is preferred"
+			}
+		}
+		rejected = append(rejected, Rejection{Tag: t, Reason: reason})
+	}
+
+	return rejected
+}
+
+// HasErrors returns true if a rule can not be evaluated.
+func (r Result) HasErrors() bool {
+	return slices.ContainsFunc(r.Rules, func(rule RuleResult) bool {
+		return rule.Result == v1alpha1.ImageRuleResultError
+	})
+}
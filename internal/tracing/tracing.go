@@ -0,0 +1,126 @@
+// Package tracing exports the OpenTelemetry spans of kimup.
+//
+// The spans follow a refresh from its trigger to the registry, the rules and the actions.
+// The trace context is carried in the data of the events of gookit/event (see triggers.TraceContext).
+// The spans are exported with OTLP over HTTP (configured with the OTEL_EXPORTER_OTLP_* environment variables),
+// to the standard output or to a file. The tracing is disabled if no exporter is set.
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	instrumentationName = "github.com/orange-cloudavenue/kube-image-updater"
+)
+
+var (
+	Exporter    string
+	File        string
+	SampleRatio float64
+)
+
+func init() {
+	flag.StringVar(&Exporter, models.TracingExporterFlagName, "", "Exporter of the traces: otlp, stdout or file. The tracing is disabled if empty.")
+	flag.StringVar(&File, models.TracingFileFlagName, "", "File where the traces are written by the file exporter.")
+	flag.Float64Var(&SampleRatio, models.TracingSampleRatioFlagName, models.TracingDefaultSampleRatio, "Ratio of the traces sampled, between 0 and 1.")
+
+	// The trace context is propagated even if the traces are not exported
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Init registers the tracer provider exporting the spans of the service with the exporter of the flags.
+// The returned function flushes and stops the exporter.
+func Init(ctx context.Context, service string) (shutdown func(context.Context) error, err error) {
+	if Exporter == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	switch Exporter {
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = NewWriterExporter(os.Stdout)
+	case ExporterFile:
+		if File == "" {
+			return nil, fmt.Errorf("the flag --%s is required by the file exporter", models.TracingFileFlagName)
+		}
+		var f *os.File
+		if f, err = os.OpenFile(File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600); err != nil {
+			return nil, err
+		}
+		exporter, err = NewWriterExporter(f)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(service),
+		semconv.ServiceVersion(models.Version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// NewWriterExporter returns an exporter writing the spans as JSON to the writer.
+func NewWriterExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// Start starts a span of kimup. The span is a child of the span of the context if any.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records the error on the span, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Image returns the attributes of an Image.
+func Image(namespace, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("kimup.image.namespace", namespace),
+		attribute.String("kimup.image.name", name),
+	}
+}
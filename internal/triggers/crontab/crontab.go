@@ -2,6 +2,9 @@ package crontab
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/reugn/go-quartz/job"
 	"github.com/reugn/go-quartz/quartz"
@@ -19,31 +22,164 @@ func New(ctx context.Context) {
 
 type CronTrigger struct{}
 
-func AddCronTab(namespace, name, crontab string) error {
+// AddCronTab registers the job of the crontab trigger at the index of the image.
+// The H fields of the expression are expanded with the key of the image.
+func AddCronTab(namespace, name string, index int, crontab string) error {
+	cronTrigger, err := NewTrigger(crontab, BuildKey(namespace, name))
+	if err != nil {
+		return err
+	}
+
+	return AddTrigger(namespace, name, index, cronTrigger)
+}
+
+// AddTrigger registers the job refreshing the image with the trigger at the index of the image.
+func AddTrigger(namespace, name string, index int, trigger quartz.Trigger) error {
 	log.WithFields(logrus.Fields{
-		"crontab":   crontab,
+		"schedule":  trigger.Description(),
 		"namespace": namespace,
 		"name":      name,
+		"trigger":   index,
 	}).Info("Registering crontab")
 
-	cronTrigger, _ := quartz.NewCronTrigger(crontab)
 	functionJob := job.NewFunctionJob(func(_ context.Context) (string, error) {
 		log.WithFields(logrus.Fields{
 			"namespace": namespace,
 			"name":      name,
+			"trigger":   index,
 		}).Info("Crontab trigger refresh")
 
-		_, err := triggers.Trigger(triggers.RefreshImage, namespace, name)
+		_, err := triggers.TriggerSchedule(triggers.RefreshImage, namespace, name, index)
 		return "", err
 	})
 
 	return sched.ScheduleJob(
 		quartz.NewJobDetail(
 			functionJob,
-			quartz.NewJobKey(BuildKey(namespace, name)),
+			quartz.NewJobKey(BuildTriggerKey(namespace, name, index)),
+		), trigger)
+}
+
+// SyncCronTabs converges the jobs of the image with the crontab expressions of its triggers.
+// It returns true if a job has been added, replaced or removed.
+func SyncCronTabs(namespace, name string, crontabs []string) (bool, error) {
+	schedules := make([]quartz.Trigger, 0, len(crontabs))
+	for _, crontab := range crontabs {
+		cronTrigger, err := NewTrigger(crontab, BuildKey(namespace, name))
+		if err != nil {
+			return false, err
+		}
+		schedules = append(schedules, cronTrigger)
+	}
+
+	return SyncTriggers(namespace, name, schedules)
+}
+
+// SyncTriggers converges the jobs of the image with the schedules of its triggers.
+// A job is replaced if its schedule has changed and the jobs of the removed triggers are removed.
+// It returns true if a job has been added, replaced or removed.
+func SyncTriggers(namespace, name string, schedules []quartz.Trigger) (bool, error) {
+	changed := false
+
+	for index, schedule := range schedules {
+		key := BuildTriggerKey(namespace, name, index)
+
+		if job, err := sched.GetScheduledJob(quartz.NewJobKey(key)); err == nil {
+			if job.Trigger().Description() == schedule.Description() {
+				continue
+			}
+
+			if err := RemoveJob(key); err != nil {
+				return changed, err
+			}
+		}
+
+		if err := AddTrigger(namespace, name, index, schedule); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	keys, err := imageJobKeys(namespace, name)
+	if err != nil {
+		return changed, err
+	}
+	for _, key := range keys {
+		if index, ok := triggerIndex(namespace, name, key); ok && index < len(schedules) {
+			continue
+		}
+		if err := RemoveJob(key); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// RemoveImageJobs removes the jobs of all the scheduled triggers of the image.
+func RemoveImageJobs(namespace, name string) error {
+	keys, err := imageJobKeys(namespace, name)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := RemoveJob(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddDigestCronTab registers the job sending the digest of the alert configuration.
+func AddDigestCronTab(namespace, name, crontab string) error {
+	log.WithFields(logrus.Fields{
+		"crontab":     crontab,
+		"namespace":   namespace,
+		"alertConfig": name,
+	}).Info("Registering digest crontab")
+
+	cronTrigger, err := NewTrigger(crontab, BuildDigestKey(namespace, name))
+	if err != nil {
+		return err
+	}
+
+	functionJob := job.NewFunctionJob(func(_ context.Context) (string, error) {
+		log.WithFields(logrus.Fields{
+			"namespace":   namespace,
+			"alertConfig": name,
+		}).Info("Crontab trigger digest")
+
+		_, err := triggers.TriggerDigest(namespace, name)
+		return "", err
+	})
+
+	return sched.ScheduleJob(
+		quartz.NewJobDetail(
+			functionJob,
+			quartz.NewJobKey(BuildDigestKey(namespace, name)),
 		), cronTrigger)
 }
 
+// Validate returns an error if the crontab expression is not valid.
+// The expression has 6 fields, the first one is the seconds. The fields can be hashed with H.
+func Validate(crontab string) error {
+	_, err := NewTrigger(crontab, "")
+	return err
+}
+
+// NewTrigger returns the trigger of the crontab expression with the H fields expanded with the key.
+func NewTrigger(crontab, key string) (*quartz.CronTrigger, error) {
+	expr, err := Expand(crontab, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return quartz.NewCronTrigger(expr)
+}
+
 func RemoveJob(name string) error {
 	jobs, err := sched.GetJobKeys()
 	if err != nil {
@@ -58,8 +194,48 @@ func RemoveJob(name string) error {
 	return nil
 }
 
+// BuildKey returns the key of the image used to expand the H fields.
+// The namespaces and the names can not contain a slash, so the keys do not collide.
 func BuildKey(namespace, name string) string {
-	return namespace + "-" + name
+	return namespace + "/" + name
+}
+
+// BuildTriggerKey returns the key of the job of the crontab trigger at the index of the image.
+func BuildTriggerKey(namespace, name string, index int) string {
+	return "image/" + BuildKey(namespace, name) + "/" + strconv.Itoa(index)
+}
+
+// BuildDigestKey returns the key of the digest job of the alert configuration.
+func BuildDigestKey(namespace, name string) string {
+	return "digest/" + BuildKey(namespace, name)
+}
+
+// triggerIndex returns the index of the trigger of the job key of the image.
+func triggerIndex(namespace, name, key string) (int, bool) {
+	suffix, ok := strings.CutPrefix(key, "image/"+BuildKey(namespace, name)+"/")
+	if !ok {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(suffix)
+	return index, err == nil
+}
+
+// imageJobKeys returns the keys of the jobs of the crontab triggers of the image.
+func imageJobKeys(namespace, name string) ([]string, error) {
+	jobs, err := sched.GetJobKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, job := range jobs {
+		if _, ok := triggerIndex(namespace, name, job.Name()); ok {
+			keys = append(keys, job.Name())
+		}
+	}
+
+	return keys, nil
 }
 
 func IsExistingJob(name string) (bool, error) {
@@ -75,3 +251,60 @@ func IsExistingJob(name string) (bool, error) {
 
 	return false, nil
 }
+
+// NextRunTime returns the next run time of the job.
+// It returns false if the job is not scheduled.
+func NextRunTime(name string) (time.Time, bool) {
+	job, err := sched.GetScheduledJob(quartz.NewJobKey(name))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, job.NextRunTime()), true
+}
+
+// NextImageRunTime returns the earliest next run time of the crontab triggers of the image.
+// It returns false if no trigger is scheduled.
+func NextImageRunTime(namespace, name string) (time.Time, bool) {
+	keys, err := imageJobKeys(namespace, name)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var (
+		next  time.Time
+		found bool
+	)
+	for _, key := range keys {
+		if t, ok := NextRunTime(key); ok && (!found || t.Before(next)) {
+			next, found = t, true
+		}
+	}
+
+	return next, found
+}
+
+// MissedRuns returns the run times of the crontab expression after from and before to.
+// The H fields are expanded with the key. At most limit run times are returned.
+func MissedRuns(crontab, key string, from, to time.Time, limit int) ([]time.Time, error) {
+	cronTrigger, err := NewTrigger(crontab, key)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []time.Time{}
+	prev := from.UnixNano()
+	for len(runs) < limit {
+		next, err := cronTrigger.NextFireTime(prev)
+		if err != nil {
+			return nil, err
+		}
+		if next >= to.UnixNano() {
+			break
+		}
+		runs = append(runs, time.Unix(0, next))
+		prev = next
+	}
+
+	return runs, nil
+}
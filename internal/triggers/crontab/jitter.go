@@ -0,0 +1,85 @@
+package crontab
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hashRune is the value of a field replaced by a value computed from the key of the job.
+// It spreads the jobs sharing the same expression across the interval (Jenkins style).
+const hashRune = "H"
+
+// fieldRanges are the ranges of the fields of the crontab expression used by the hash.
+// The day of month is limited to 28 to run every month.
+var fieldRanges = [][2]int{
+	{0, 59}, // seconds
+	{0, 59}, // minutes
+	{0, 23}, // hours
+	{1, 28}, // day of month
+	{1, 12}, // month
+	{1, 7},  // day of week
+}
+
+// hashField matches H, H(a-b), H/n and H(a-b)/n.
+var hashField = regexp.MustCompile(`^H(?:\((\d+)-(\d+)\))?(?:/(\d+))?$`)
+
+// Expand replaces the H fields of the crontab expression by values computed from the key.
+// The same key always gives the same expression.
+//
+//   - H is a value of the range of the field.
+//   - H(a-b) is a value between a and b.
+//   - H/n runs every n units from a value lower than n.
+//   - H(a-b)/n runs every n units between a and b from a value lower than a+n.
+func Expand(crontab, key string) (string, error) {
+	if !strings.Contains(crontab, hashRune) {
+		return crontab, nil
+	}
+
+	fields := strings.Fields(crontab)
+	for i, field := range fields {
+		if !strings.HasPrefix(field, hashRune) {
+			continue
+		}
+		if i >= len(fieldRanges) {
+			return "", fmt.Errorf("the field %d does not support %s", i+1, hashRune)
+		}
+
+		m := hashField.FindStringSubmatch(field)
+		if m == nil {
+			return "", fmt.Errorf("invalid hashed field %s", field)
+		}
+
+		lo, hi := fieldRanges[i][0], fieldRanges[i][1]
+		if m[1] != "" {
+			lo, _ = strconv.Atoi(m[1])
+			hi, _ = strconv.Atoi(m[2])
+			if lo > hi || lo < fieldRanges[i][0] || hi > fieldRanges[i][1] {
+				return "", fmt.Errorf("invalid range of the hashed field %s", field)
+			}
+		}
+
+		h := hash(key, i)
+		if m[3] == "" {
+			fields[i] = strconv.Itoa(lo + h%(hi-lo+1))
+			continue
+		}
+
+		step, _ := strconv.Atoi(m[3])
+		if step == 0 || step > hi-lo+1 {
+			return "", fmt.Errorf("invalid step of the hashed field %s", field)
+		}
+		fields[i] = fmt.Sprintf("%d-%d/%d", lo+h%step, hi, step)
+	}
+
+	return strings.Join(fields, " "), nil
+}
+
+// hash returns a value computed from the key and the index of the field.
+func hash(key string, field int) int {
+	sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(field)))
+	return int(binary.BigEndian.Uint32(sum[:4]) & 0x7fffffff)
+}
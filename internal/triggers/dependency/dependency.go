@@ -0,0 +1,81 @@
+// Package dependency refreshes the images following another image of their namespace
+// when the tag of the followed image changes.
+package dependency
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Graph contains the dependencies between the images and the last tag seen for each image.
+// The keys of the images are namespace/name and the dependencies are in the same namespace.
+type Graph struct {
+	mu sync.Mutex
+
+	// dependencies contains the images followed by each image.
+	dependencies map[string][]string
+
+	// tags contains the last tag seen for each image.
+	tags map[string]string
+}
+
+// New returns an empty Graph.
+func New() *Graph {
+	return &Graph{
+		dependencies: map[string][]string{},
+		tags:         map[string]string{},
+	}
+}
+
+// Set sets the images followed by the image.
+func (g *Graph) Set(namespace, name string, dependencies []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(dependencies) == 0 {
+		delete(g.dependencies, key(namespace, name))
+		return
+	}
+
+	g.dependencies[key(namespace, name)] = slices.Clone(dependencies)
+}
+
+// Remove removes the image from the graph.
+func (g *Graph) Remove(namespace, name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.dependencies, key(namespace, name))
+	delete(g.tags, key(namespace, name))
+}
+
+// Observe records the tag of the image and returns the names of the images following it
+// if the tag has changed since the last observation. The first observation of an image
+// only records its tag.
+func (g *Graph) Observe(namespace, name, tag string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	k := key(namespace, name)
+	previous, seen := g.tags[k]
+	g.tags[k] = tag
+	if !seen || previous == tag || tag == "" {
+		return nil
+	}
+
+	dependents := []string{}
+	for dependent, dependencies := range g.dependencies {
+		ns, n, _ := strings.Cut(dependent, "/")
+		if ns == namespace && slices.Contains(dependencies, name) {
+			dependents = append(dependents, n)
+		}
+	}
+	slices.Sort(dependents)
+
+	return dependents
+}
+
+func key(namespace, name string) string {
+	return namespace + "/" + name
+}
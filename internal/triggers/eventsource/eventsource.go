@@ -0,0 +1,271 @@
+package eventsource
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/tracing"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+const (
+	// maxPayloadSize is the maximum size of a notification.
+	maxPayloadSize = 1 << 20
+
+	// EnvHTTPToken is the environment variable of the token expected by the HTTP endpoint.
+	EnvHTTPToken = "KIMUP_EVENTS_HTTP_TOKEN"
+)
+
+var (
+	HTTPPort    int
+	HTTPPath    string
+	NATSURL     string
+	NATSSubject string
+)
+
+func init() {
+	flag.Bool(models.EventsHTTPFlagName, false, "Enable the endpoint receiving the push notifications of the registries (CloudEvents over HTTP).")
+	flag.IntVar(&HTTPPort, models.EventsHTTPPortFlagName, int(models.EventsHTTPDefaultPort), "Port of the endpoint receiving the push notifications.")
+	flag.StringVar(&HTTPPath, models.EventsHTTPPathFlagName, models.EventsHTTPDefaultPath, "Path of the endpoint receiving the push notifications.")
+	flag.Bool(models.EventsHTTPInsecureFlagName, false, "Accept the push notifications without token. Anyone reaching the endpoint can trigger the refreshes of the Images.")
+	flag.StringVar(&NATSURL, models.EventsNATSURLFlagName, "", "URL of the NATS server carrying the push notifications of the registries. Disabled if empty.")
+	flag.StringVar(&NATSSubject, models.EventsNATSSubjectFlagName, models.EventsNATSDefaultSubject, "NATS subject of the push notifications.")
+}
+
+type (
+	// ImageLister lists the Images of all the namespaces.
+	ImageLister func(ctx context.Context) ([]v1alpha1.Image, error)
+
+	// Dispatcher refreshes the Images of the pushed repositories.
+	Dispatcher struct {
+		list    ImageLister
+		trigger func(ctx context.Context, namespace, name, tag string)
+
+		// shard and c route the refreshes received over HTTP to the replica owning the Image
+		shard *sharding.Sharder
+		c     client.Writer
+
+		// token is the token expected in the Authorization header of the HTTP requests
+		token string
+	}
+)
+
+// NewDispatcher returns a Dispatcher firing triggers.RefreshImage for the Images listed by list.
+func NewDispatcher(list ImageLister) *Dispatcher {
+	return &Dispatcher{
+		list: list,
+		trigger: func(ctx context.Context, namespace, name, tag string) {
+			_, _ = triggers.TriggerWithTag(ctx, triggers.RefreshImage, namespace, name, tag)
+		},
+	}
+}
+
+// WithTrigger replaces the function called for each Image of a pushed repository.
+func (d *Dispatcher) WithTrigger(trigger func(ctx context.Context, namespace, name, tag string)) *Dispatcher {
+	d.trigger = trigger
+	return d
+}
+
+// WithSharding routes the refreshes of the notifications received over HTTP to the replica owning the Image.
+// A request is received by a single replica, so the refresh of an Image owned by another replica
+// is requested with the refresh action annotation, as the REST API does, and the pushed tag
+// and the trace context are forwarded in the refresh-hint annotation.
+// The NATS messages are received by all the replicas, each replica refreshes the Images it owns.
+func (d *Dispatcher) WithSharding(shard *sharding.Sharder, c client.Writer) *Dispatcher {
+	d.shard = shard
+	d.c = c
+	return d
+}
+
+// WithToken sets the token expected in the Authorization header of the HTTP requests ("Bearer <token>").
+// The HTTP requests are not authenticated if the token is empty.
+func (d *Dispatcher) WithToken(token string) *Dispatcher {
+	d.token = token
+	return d
+}
+
+// Dispatch refreshes the Images of the repository of the push with the pushed tag as hint.
+// It returns the number of refreshed Images.
+func (d *Dispatcher) Dispatch(ctx context.Context, push Push) (int, error) {
+	return d.dispatch(ctx, push, false)
+}
+
+// dispatch refreshes the Images of the repository of the push.
+// If route is true, the refreshes of the Images owned by another replica are requested with the refresh action annotation.
+func (d *Dispatcher) dispatch(ctx context.Context, push Push, route bool) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "eventsource.Dispatch",
+		attribute.String("kimup.push.repository", push.Repository),
+		attribute.String("kimup.push.tag", push.Tag),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	images, err := d.list(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, image := range images {
+		repository, err := NormalizeRepository(image.Spec.Image)
+		if err != nil || repository != push.Repository {
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			"namespace":  image.Namespace,
+			"name":       image.Name,
+			"repository": push.Repository,
+			"tag":        push.Tag,
+		}).Info("Registry push refresh")
+
+		if route && d.c != nil && !d.shard.IsOwner(types.NamespacedName{Namespace: image.Namespace, Name: image.Name}.String()) {
+			if err := d.requestRefresh(ctx, image, push.Tag); err != nil {
+				return refreshed, err
+			}
+		} else {
+			d.trigger(ctx, image.Namespace, image.Name, push.Tag)
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+// requestRefresh sets the refresh action annotation on the Image, the refresh is executed by the replica owning the Image.
+// The pushed tag and the trace context are set in the refresh-hint annotation.
+func (d *Dispatcher) requestRefresh(ctx context.Context, image v1alpha1.Image, tag string) error {
+	hint := annotations.RefreshHintValue{Tag: tag, TraceContext: propagation.MapCarrier{}}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(hint.TraceContext))
+	value, err := json.Marshal(hint)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				string(annotations.KeyAction):      string(annotations.ActionRefresh),
+				string(annotations.KeyRefreshHint): string(value),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return d.c.Patch(ctx, &image, client.RawPatch(types.MergePatchType, patch))
+}
+
+// DispatchPayload parses the payload and dispatches its pushes.
+func (d *Dispatcher) DispatchPayload(ctx context.Context, payload []byte) error {
+	return d.dispatchPayload(ctx, payload, false)
+}
+
+func (d *Dispatcher) dispatchPayload(ctx context.Context, payload []byte, route bool) error {
+	pushes, err := ParsePushes(payload)
+	if err != nil {
+		return err
+	}
+
+	for _, push := range pushes {
+		if _, err := d.dispatch(ctx, push, route); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Handler returns the HTTP handler receiving the notifications.
+// The CloudEvents are accepted in the binary, structured and batched modes,
+// the other payloads are parsed as the data of a CloudEvent.
+// The requests without the token set by WithToken are rejected.
+func (d *Dispatcher) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if d.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+d.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		payloads := [][]byte{body}
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/cloudevents-batch+json" {
+			var batch []json.RawMessage
+			if err := json.Unmarshal(body, &batch); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			payloads = payloads[:0]
+			for _, e := range batch {
+				payloads = append(payloads, e)
+			}
+		}
+
+		// The trace context of the notification is in the traceparent header (CloudEvents distributed tracing)
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		// In the binary mode, the attributes are in the ce- headers and the body is the data
+		for _, payload := range payloads {
+			if err := d.dispatchPayload(ctx, payload, true); err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, ErrUnknownPayload) {
+					status = http.StatusBadRequest
+				}
+				log.WithError(err).WithField("ce-type", r.Header.Get("Ce-Type")).Error("Error dispatching the push notification")
+				http.Error(w, err.Error(), status)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// Subscriber subscribes to a subject of a message broker.
+// The handler is called with the payload of each message until the context is done.
+type Subscriber interface {
+	Subscribe(ctx context.Context, subject string, handler func(payload []byte)) error
+}
+
+// Subscribe dispatches the notifications received on the subject of the broker.
+func (d *Dispatcher) Subscribe(ctx context.Context, broker Subscriber, subject string) error {
+	return broker.Subscribe(ctx, subject, func(payload []byte) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		if err := d.DispatchPayload(ctx, payload); err != nil {
+			log.WithError(err).WithField("subject", subject).Error("Error dispatching the push notification")
+		}
+	})
+}
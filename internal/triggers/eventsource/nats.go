@@ -0,0 +1,43 @@
+package eventsource
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+)
+
+var _ Subscriber = &NATS{}
+
+// NATS subscribes to the push notifications carried by a NATS server.
+type NATS struct {
+	conn *nats.Conn
+}
+
+// NewNATS connects to the NATS server. The connection is re-established after the errors.
+func NewNATS(url string, opts ...nats.Option) (*NATS, error) {
+	conn, err := nats.Connect(url, append([]nats.Option{nats.Name("kimup"), nats.MaxReconnects(-1)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATS{conn: conn}, nil
+}
+
+// Subscribe calls the handler with the payload of each message of the subject.
+// The subscription and the connection are closed when the context is done.
+func (n *NATS) Subscribe(ctx context.Context, subject string, handler func(payload []byte)) error {
+	sub, err := n.conn.Subscribe(subject, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = sub.Unsubscribe()
+		n.conn.Close()
+	}()
+
+	return nil
+}
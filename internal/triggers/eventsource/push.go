@@ -0,0 +1,163 @@
+// Package eventsource receives the push notifications of the registries and refreshes
+// the Images of the pushed repositories without waiting for their next scheduled sync.
+//
+// The notifications are received as CloudEvents over HTTP or from a NATS subject.
+// The payloads of the Docker distribution registry, Harbor and a simple
+// {"repository": "...", "tag": "..."} document are supported.
+package eventsource
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/distribution/reference"
+)
+
+// Push is a tag pushed to a repository.
+type Push struct {
+	// Repository is the normalized name of the repository (e.g. docker.io/library/nginx).
+	Repository string
+
+	// Tag is the pushed tag. It is empty if the notification does not contain the tag.
+	Tag string
+}
+
+// ErrUnknownPayload is returned when the payload does not contain a supported push notification.
+var ErrUnknownPayload = errors.New("unknown push notification payload")
+
+type (
+	// cloudEvent is a CloudEvent in the structured mode.
+	cloudEvent struct {
+		SpecVersion string          `json:"specversion"`
+		Type        string          `json:"type"`
+		Data        json.RawMessage `json:"data"`
+		DataBase64  []byte          `json:"data_base64"`
+	}
+
+	// distributionEnvelope is the notification of the Docker distribution registry.
+	distributionEnvelope struct {
+		Events []struct {
+			Action string `json:"action"`
+			Target struct {
+				Repository string `json:"repository"`
+				Tag        string `json:"tag"`
+			} `json:"target"`
+			Request struct {
+				Host string `json:"host"`
+			} `json:"request"`
+		} `json:"events"`
+	}
+
+	// harborEventData is the data of the Harbor webhooks and CloudEvents.
+	harborEventData struct {
+		Resources []struct {
+			Tag         string `json:"tag"`
+			ResourceURL string `json:"resource_url"`
+		} `json:"resources"`
+	}
+
+	// simplePush is a push notification with the repository and the tag.
+	simplePush struct {
+		Repository string `json:"repository"`
+		Tag        string `json:"tag"`
+	}
+)
+
+// ParsePushes returns the pushes of the payload.
+// The payload can be a CloudEvent in the structured mode or the data of a CloudEvent.
+func ParsePushes(payload []byte) ([]Push, error) {
+	var ce cloudEvent
+	if err := json.Unmarshal(payload, &ce); err == nil && ce.SpecVersion != "" {
+		switch {
+		case len(ce.DataBase64) > 0:
+			return parseData(ce.DataBase64)
+		case len(ce.Data) > 0:
+			return parseData(ce.Data)
+		default:
+			return nil, ErrUnknownPayload
+		}
+	}
+
+	return parseData(payload)
+}
+
+// parseData returns the pushes of the data of a notification.
+func parseData(data []byte) ([]Push, error) {
+	// The data of a CloudEvent can be a JSON string
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		data = []byte(s)
+	}
+
+	var envelope distributionEnvelope
+	if err := json.Unmarshal(data, &envelope); err == nil && len(envelope.Events) > 0 {
+		pushes := []Push{}
+		for _, e := range envelope.Events {
+			if e.Action != "push" || e.Target.Repository == "" {
+				continue
+			}
+			repository := e.Target.Repository
+			if e.Request.Host != "" {
+				repository = e.Request.Host + "/" + repository
+			}
+			if p, ok := newPush(repository, e.Target.Tag); ok {
+				pushes = append(pushes, p)
+			}
+		}
+		return pushes, nil
+	}
+
+	// Harbor sends the event data in event_data, or as data of its CloudEvents
+	var harbor struct {
+		EventData *harborEventData `json:"event_data"`
+		harborEventData
+	}
+	if err := json.Unmarshal(data, &harbor); err == nil {
+		resources := harbor.Resources
+		if harbor.EventData != nil {
+			resources = harbor.EventData.Resources
+		}
+		if len(resources) > 0 {
+			pushes := []Push{}
+			for _, r := range resources {
+				if p, ok := newPush(r.ResourceURL, r.Tag); ok {
+					pushes = append(pushes, p)
+				}
+			}
+			return pushes, nil
+		}
+	}
+
+	var simple simplePush
+	if err := json.Unmarshal(data, &simple); err == nil && simple.Repository != "" {
+		p, ok := newPush(simple.Repository, simple.Tag)
+		if !ok {
+			return nil, ErrUnknownPayload
+		}
+		return []Push{p}, nil
+	}
+
+	return nil, ErrUnknownPayload
+}
+
+// newPush returns the push with the normalized repository.
+// It returns false if the repository is not a valid reference.
+func newPush(repository, tag string) (Push, bool) {
+	name, err := NormalizeRepository(repository)
+	if err != nil {
+		return Push{}, false
+	}
+
+	return Push{Repository: name, Tag: tag}, true
+}
+
+// NormalizeRepository returns the normalized name of the repository without tag and digest
+// (e.g. nginx:1.27 is docker.io/library/nginx).
+func NormalizeRepository(repository string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(repository)
+	if err != nil {
+		return "", err
+	}
+
+	return named.Name(), nil
+}
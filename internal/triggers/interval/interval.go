@@ -0,0 +1,55 @@
+// Package interval schedules the refreshes of the images at a fixed interval.
+package interval
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// MinInterval is the minimum interval between two refreshes.
+const MinInterval = time.Minute
+
+// Trigger fires every interval.
+// The fire times are shifted by a phase computed from the key, so the images
+// sharing the same interval are spread across it.
+type Trigger struct {
+	every time.Duration
+	phase time.Duration
+}
+
+// New returns the trigger firing every interval with the phase of the key.
+func New(every time.Duration, key string) (*Trigger, error) {
+	if err := Validate(every); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	phase := time.Duration(binary.BigEndian.Uint64(sum[:8]) % uint64(every))
+
+	return &Trigger{
+		every: every,
+		phase: phase,
+	}, nil
+}
+
+// Validate returns an error if the interval is lower than MinInterval.
+func Validate(every time.Duration) error {
+	if every < MinInterval {
+		return fmt.Errorf("the interval %s is lower than %s", every, MinInterval)
+	}
+
+	return nil
+}
+
+// NextFireTime returns the first fire time after prev in nanoseconds.
+func (t *Trigger) NextFireTime(prev int64) (int64, error) {
+	n := (prev-int64(t.phase))/int64(t.every) + 1
+	return n*int64(t.every) + int64(t.phase), nil
+}
+
+// Description returns the description of the trigger.
+func (t *Trigger) Description() string {
+	return fmt.Sprintf("IntervalTrigger::%s::%s", t.every, t.phase)
+}
@@ -0,0 +1,29 @@
+// Package podcreate refreshes the images when a Pod using them is created.
+package podcreate
+
+import (
+	"time"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+// MinInterval is the minimum time between the last sync of an image and a refresh
+// triggered by the creation of a Pod. It prevents a refresh for each Pod of a rollout.
+const MinInterval = time.Minute
+
+// ShouldRefresh returns true if the creation of a Pod using the image must refresh it.
+// The image must define an on-pod-create trigger, have no pending action and
+// not have been synchronized for MinInterval.
+func ShouldRefresh(image v1alpha1.Image, now time.Time) bool {
+	if !image.HasTrigger(triggers.OnPodCreate) {
+		return false
+	}
+
+	if image.GetAnnotations()[string(annotations.KeyAction)] != "" {
+		return false
+	}
+
+	return now.Sub(image.LastSyncTime()) >= MinInterval
+}
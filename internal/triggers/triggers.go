@@ -1,8 +1,12 @@
 package triggers
 
 import (
+	"context"
+
 	"github.com/gookit/event"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/orange-cloudavenue/kube-image-updater/internal/log"
 )
@@ -15,9 +19,13 @@ type (
 const (
 	RefreshImage  EventName = "refresh.image"
 	RefreshStatus EventName = "refresh.status"
+	SendDigest    EventName = "send.digest"
 
-	Crontab Name = "crontab"
-	Webhook Name = "webhook"
+	Crontab     Name = "crontab"
+	Webhook     Name = "webhook"
+	Interval    Name = "interval"
+	OnPodCreate Name = "on-pod-create"
+	Dependency  Name = "dependency"
 )
 
 func (e EventName) String() string {
@@ -34,3 +42,68 @@ func Trigger(e EventName, namespace, imageName string) (event.Event, error) {
 	event.Async(e.String(), event.M{"namespace": namespace, "image": imageName})
 	return nil, nil
 }
+
+// TriggerSchedule fires the event of the scheduled trigger at the index of the image.
+// The index permits to record the time of the run of the trigger.
+func TriggerSchedule(e EventName, namespace, imageName string, index int) (event.Event, error) {
+	log.
+		WithFields(logrus.Fields{
+			"namespace": namespace,
+			"image":     imageName,
+			"trigger":   index,
+		}).Infof("Triggering event %s", e.String())
+
+	event.Async(e.String(), event.M{"namespace": namespace, "image": imageName, "trigger": index})
+	return nil, nil
+}
+
+// TriggerDigest fires the event sending the digest of the AlertConfig.
+func TriggerDigest(namespace, alertConfigName string) (event.Event, error) {
+	log.
+		WithFields(logrus.Fields{
+			"namespace":   namespace,
+			"alertConfig": alertConfigName,
+		}).Infof("Triggering event %s", SendDigest.String())
+
+	event.Async(SendDigest.String(), event.M{"namespace": namespace, "alertConfig": alertConfigName})
+	return nil, nil
+}
+
+// TriggerWithTag fires the event with a tag hint, e.g. the tag pushed to the registry,
+// and the trace context of ctx.
+func TriggerWithTag(ctx context.Context, e EventName, namespace, imageName, tag string) (event.Event, error) {
+	log.
+		WithFields(logrus.Fields{
+			"namespace": namespace,
+			"image":     imageName,
+			"tag":       tag,
+		}).Infof("Triggering event %s", e.String())
+
+	data := event.M{"namespace": namespace, "image": imageName, "tag": tag}
+	injectTraceContext(ctx, data)
+
+	event.Async(e.String(), data)
+	return nil, nil
+}
+
+// traceContextKey is the key of the trace context in the data of the events.
+const traceContextKey = "traceContext"
+
+// injectTraceContext adds the trace context of ctx to the data of the event.
+func injectTraceContext(ctx context.Context, data event.M) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		data[traceContextKey] = carrier
+	}
+}
+
+// TraceContext returns ctx with the trace context of the event, if any.
+func TraceContext(ctx context.Context, e event.Event) context.Context {
+	carrier, ok := e.Data()[traceContextKey].(propagation.MapCarrier)
+	if !ok {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
@@ -0,0 +1,80 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+)
+
+func TestAlertMattermost_Execute(t *testing.T) {
+	sent := recordAlerts(t)
+
+	tests := []struct {
+		name           string
+		mattermostSpec *v1alpha1.AlertMattermostSpec
+		expectedError  bool
+		expectedURL    string
+		expectedBody   map[string]any
+	}{
+		{
+			name: "Webhook only",
+			mattermostSpec: &v1alpha1.AlertMattermostSpec{
+				WebhookURL: v1alpha1.ValueOrValueFrom{Value: "https://mattermost.example.com/hooks/abcdef"},
+			},
+			expectedURL:  "https://mattermost.example.com/hooks/abcdef",
+			expectedBody: map[string]any{},
+		},
+		{
+			name: "All the optional fields",
+			mattermostSpec: &v1alpha1.AlertMattermostSpec{
+				WebhookURL: v1alpha1.ValueOrValueFrom{Value: "https://mattermost.example.com/team/hooks/abcdef"},
+				Channel:    "town-square",
+				Username:   "kimup bot",
+				Icon:       "https://example.com/icon.png?size=32&x=y",
+			},
+			expectedURL: "https://mattermost.example.com/hooks/abcdef",
+			expectedBody: map[string]any{
+				"channel":  "town-square",
+				"username": "kimup bot",
+				"icon_url": "https://example.com/icon.png?size=32&x=y",
+			},
+		},
+		{
+			name: "Invalid webhook URL",
+			mattermostSpec: &v1alpha1.AlertMattermostSpec{
+				WebhookURL: v1alpha1.ValueOrValueFrom{Value: "https://mattermost.example.com/abcdef"},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "Empty configuration",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var values []v1alpha1.ValueOrValueFrom
+			if tt.mattermostSpec != nil {
+				values = append(values, tt.mattermostSpec.WebhookURL)
+			}
+
+			err := executeAlert(t, actions.AlertMattermost, v1alpha1.AlertConfigSpec{Mattermost: tt.mattermostSpec}, values...)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			alert := <-sent
+			assert.Equal(t, tt.expectedURL, alert.url)
+			for k, v := range tt.expectedBody {
+				assert.Equal(t, v, alert.body[k], k)
+			}
+		})
+	}
+}
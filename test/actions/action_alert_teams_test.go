@@ -0,0 +1,77 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+)
+
+func TestAlertTeams_Execute(t *testing.T) {
+	sent := recordAlerts(t)
+
+	const webhookURL = "https://example.webhook.office.com/webhookb2/11111111-4444-4444-8444-cccccccccccc@22222222-4444-4444-8444-cccccccccccc/IncomingWebhook/33333333012222222222333333333344/44444444-4444-4444-8444-cccccccccccc"
+
+	tests := []struct {
+		name          string
+		teamsSpec     *v1alpha1.AlertTeamsSpec
+		expectedError bool
+		expectedBody  map[string]any
+	}{
+		{
+			name: "Webhook only",
+			teamsSpec: &v1alpha1.AlertTeamsSpec{
+				WebhookURL: v1alpha1.ValueOrValueFrom{Value: webhookURL},
+			},
+			expectedBody: map[string]any{},
+		},
+		{
+			name: "All the optional fields",
+			teamsSpec: &v1alpha1.AlertTeamsSpec{
+				WebhookURL: v1alpha1.ValueOrValueFrom{Value: webhookURL},
+				Title:      "New tag & more",
+				Color:      "#ff0000",
+			},
+			expectedBody: map[string]any{
+				"title":      "New tag & more",
+				"themeColor": "#ff0000",
+			},
+		},
+		{
+			name: "Invalid webhook URL",
+			teamsSpec: &v1alpha1.AlertTeamsSpec{
+				WebhookURL: v1alpha1.ValueOrValueFrom{Value: "https://example.webhook.office.com/webhookb2/invalid"},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "Empty configuration",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var values []v1alpha1.ValueOrValueFrom
+			if tt.teamsSpec != nil {
+				values = append(values, tt.teamsSpec.WebhookURL)
+			}
+
+			err := executeAlert(t, actions.AlertTeams, v1alpha1.AlertConfigSpec{Teams: tt.teamsSpec}, values...)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			alert := <-sent
+			assert.Equal(t, webhookURL, alert.url)
+			for k, v := range tt.expectedBody {
+				assert.Equal(t, v, alert.body[k], k)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+)
+
+func TestAlertTelegram_Execute(t *testing.T) {
+	sent := recordAlerts(t)
+
+	const expectedURL = "https://api.telegram.org/bot123456:ABC-DEF_ghi/sendMessage"
+
+	tests := []struct {
+		name          string
+		telegramSpec  *v1alpha1.AlertTelegramSpec
+		expectedError bool
+		expectedBody  []map[string]any
+	}{
+		{
+			name: "Single chat",
+			telegramSpec: &v1alpha1.AlertTelegramSpec{
+				Token: v1alpha1.ValueOrValueFrom{Value: "123456:ABC-DEF_ghi"},
+				Chats: []string{"@kimup"},
+			},
+			expectedBody: []map[string]any{
+				{"chat_id": "@kimup"},
+			},
+		},
+		{
+			name: "All the optional fields",
+			telegramSpec: &v1alpha1.AlertTelegramSpec{
+				Token:               v1alpha1.ValueOrValueFrom{Value: "123456:ABC-DEF_ghi"},
+				Chats:               []string{"@kimup", "-100123"},
+				ParseMode:           "HTML",
+				DisableNotification: true,
+			},
+			expectedBody: []map[string]any{
+				{"chat_id": "@kimup", "parse_mode": "HTML", "disable_notification": true},
+				{"chat_id": "-100123", "parse_mode": "HTML", "disable_notification": true},
+			},
+		},
+		{
+			name: "Invalid token",
+			telegramSpec: &v1alpha1.AlertTelegramSpec{
+				Token: v1alpha1.ValueOrValueFrom{Value: "123456"},
+				Chats: []string{"@kimup"},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "Empty configuration",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var values []v1alpha1.ValueOrValueFrom
+			if tt.telegramSpec != nil {
+				values = append(values, tt.telegramSpec.Token)
+			}
+
+			err := executeAlert(t, actions.AlertTelegram, v1alpha1.AlertConfigSpec{Telegram: tt.telegramSpec}, values...)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, expected := range tt.expectedBody {
+				alert := <-sent
+				assert.Equal(t, expectedURL, alert.url)
+				for k, v := range expected {
+					assert.Equal(t, v, alert.body[k], k)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package actions_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+func TestAlertWebhook_Execute(t *testing.T) {
+	namespace := "default"
+	ctx := context.TODO()
+
+	type received struct {
+		method string
+		query  url.Values
+		header http.Header
+		body   []byte
+	}
+
+	ch := make(chan received, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{method: r.Method, query: r.URL.Query(), header: r.Header, body: body}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	image := v1alpha1.Image{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Image",
+			APIVersion: fmt.Sprintf("%s/%s", v1alpha1.GroupVersion.Group, v1alpha1.GroupVersion.Version),
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.ImageSpec{
+			Image:   "ghcr.io/orange-cloudavenue/kimup-controller",
+			BaseTag: "v0.0.1",
+			Rules: []v1alpha1.ImageRule{
+				{
+					Name: "Always update",
+					Type: rules.Always,
+					Actions: []v1alpha1.ImageAction{
+						{
+							Type: actions.AlertWebhook.String(),
+							Data: v1alpha1.ValueOrValueFrom{
+								ValueFrom: &v1alpha1.ValueFromSource{
+									AlertConfigRef: &corev1.LocalObjectReference{
+										Name: "demo",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		webhookSpec   *v1alpha1.AlertWebhookSpec
+		expectedError bool
+		expectedBody  map[string]string
+		expectedQuery url.Values
+	}{
+		{
+			name: "Default template",
+			webhookSpec: &v1alpha1.AlertWebhookSpec{
+				URL: v1alpha1.ValueOrValueFrom{Value: ts.URL + "/hook"},
+				Headers: []v1alpha1.AlertWebhookHeader{
+					{
+						Name:  "X-Api-Key",
+						Value: v1alpha1.ValueOrValueFrom{Value: "secret"},
+					},
+				},
+			},
+			expectedError: false,
+			expectedBody: map[string]string{
+				"namespace": namespace,
+				"name":      "demo",
+				"image":     "ghcr.io/orange-cloudavenue/kimup-controller",
+				"baseTag":   "v0.0.1",
+				"actualTag": "1.0.0",
+				"newTag":    "1.1.0+build",
+			},
+		},
+		{
+			name: "Custom template",
+			webhookSpec: &v1alpha1.AlertWebhookSpec{
+				URL:          v1alpha1.ValueOrValueFrom{Value: ts.URL + "/hook"},
+				Method:       http.MethodPut,
+				TemplateBody: `{"text": "{{ .ImageName }}:{{ .NewTag }}"}`,
+			},
+			expectedError: false,
+			expectedBody: map[string]string{
+				"text": "ghcr.io/orange-cloudavenue/kimup-controller:1.1.0+build",
+			},
+		},
+		{
+			name: "Query of the endpoint",
+			webhookSpec: &v1alpha1.AlertWebhookSpec{
+				URL:          v1alpha1.ValueOrValueFrom{Value: ts.URL + "/hook?token=abc&method=x&title=y%26z"},
+				TemplateBody: `{"text": "{{ .NewTag }}"}`,
+			},
+			expectedError: false,
+			expectedBody: map[string]string{
+				"text": "1.1.0+build",
+			},
+			expectedQuery: url.Values{
+				"token":  []string{"abc"},
+				"method": []string{"x"},
+				"title":  []string{"y&z"},
+			},
+		},
+		{
+			name: "Reserved query parameter",
+			webhookSpec: &v1alpha1.AlertWebhookSpec{
+				URL: v1alpha1.ValueOrValueFrom{Value: ts.URL + "/hook?@authorization=abc"},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "Empty configuration",
+			webhookSpec:   nil,
+			expectedError: true,
+		},
+		{
+			name: "Invalid url scheme",
+			webhookSpec: &v1alpha1.AlertWebhookSpec{
+				URL: v1alpha1.ValueOrValueFrom{Value: "ftp://example.com"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertConfig := v1alpha1.AlertConfig{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "demo",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.AlertConfigSpec{
+					Webhook: tt.webhookSpec,
+				},
+			}
+
+			fakeClient := fakekubeclient.NewFakeKubeClient()
+
+			a, err := actions.GetAction(actions.AlertWebhook)
+			assert.NoError(t, err)
+
+			a.Init(fakeClient, models.Tags{
+				Actual: "1.0.0",
+				New:    "1.1.0+build",
+			}, &image, image.Spec.Rules[0].Actions[0].Data)
+
+			fakeClient.On("GetValueOrValueFrom", ctx, image.GetNamespace(), image.Spec.Rules[0].Actions[0].Data).Return(alertConfig, nil)
+			if tt.webhookSpec != nil {
+				fakeClient.On("GetValueOrValueFrom", ctx, namespace, tt.webhookSpec.URL).Return(tt.webhookSpec.URL.Value, nil)
+				for _, h := range tt.webhookSpec.Headers {
+					fakeClient.On("GetValueOrValueFrom", ctx, namespace, h.Value).Return(h.Value.Value, nil)
+				}
+			}
+
+			err = a.Execute(ctx)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			r := <-ch
+			body := map[string]string{}
+			assert.NoError(t, json.Unmarshal(r.body, &body))
+			assert.Equal(t, tt.expectedBody, body)
+			assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+
+			if tt.webhookSpec.Method != "" {
+				assert.Equal(t, tt.webhookSpec.Method, r.method)
+			} else {
+				assert.Equal(t, http.MethodPost, r.method)
+			}
+
+			for _, h := range tt.webhookSpec.Headers {
+				assert.Equal(t, h.Value.Value, r.header.Get(h.Name))
+			}
+
+			if tt.expectedQuery != nil {
+				assert.Equal(t, tt.expectedQuery, r.query)
+			}
+		})
+	}
+}
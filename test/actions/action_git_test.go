@@ -0,0 +1,228 @@
+package actions_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+const kustomization = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - deployment.yaml
+images:
+  - name: ghcr.io/orange-cloudavenue/kimup-controller
+    newTag: v0.0.1
+`
+
+// newBareRepository creates a bare repository containing the kustomization on the main branch.
+func newBareRepository(t *testing.T) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "orange-cloudavenue", "gitops.git")
+	_, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+		Bare:        true,
+	})
+	require.NoError(t, err)
+
+	fs := memfs.New()
+	repo, err := git.InitWithOptions(memory.NewStorage(), fs, git.InitOptions{DefaultBranch: plumbing.Main})
+	require.NoError(t, err)
+
+	f, err := fs.Create("kustomization.yaml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(kustomization))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add("kustomization.yaml")
+	require.NoError(t, err)
+	_, err = w.Commit("init", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{dir}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin"}))
+
+	return dir
+}
+
+// readFile returns the content of the file on the branch of the bare repository.
+func readFile(t *testing.T, dir, branch, path string) string {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	require.NoError(t, err)
+
+	commit, err := repo.CommitObject(ref.Hash())
+	require.NoError(t, err)
+
+	file, err := commit.File(path)
+	require.NoError(t, err)
+
+	content, err := file.Contents()
+	require.NoError(t, err)
+
+	return content
+}
+
+func TestGitActions_Execute(t *testing.T) {
+	namespace := "default"
+	ctx := context.TODO()
+
+	data := v1alpha1.ValueOrValueFrom{
+		ValueFrom: &v1alpha1.ValueFromSource{
+			GitConfigRef: &corev1.LocalObjectReference{
+				Name: "demo",
+			},
+		},
+	}
+
+	image := v1alpha1.Image{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.ImageSpec{
+			Image:   "ghcr.io/orange-cloudavenue/kimup-controller",
+			BaseTag: "v0.0.1",
+		},
+	}
+
+	tags := models.Tags{
+		Actual: "v0.0.1",
+		New:    "v0.0.2",
+	}
+
+	t.Run("git-commit", func(t *testing.T) {
+		dir := newBareRepository(t)
+
+		gitConfig := v1alpha1.GitConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: namespace},
+			Spec: v1alpha1.GitConfigSpec{
+				URL:    dir,
+				Branch: "main",
+				File: v1alpha1.GitFileSpec{
+					Path:   "kustomization.yaml",
+					Format: "kustomize",
+				},
+			},
+		}
+
+		fakeClient := fakekubeclient.NewFakeKubeClient()
+		fakeClient.On("GetValueOrValueFrom", ctx, namespace, data).Return(gitConfig, nil)
+
+		a, err := actions.GetAction(actions.GitCommit)
+		require.NoError(t, err)
+		a.Init(fakeClient, tags, &image, data)
+
+		require.NoError(t, a.Execute(ctx))
+		content := readFile(t, dir, "main", "kustomization.yaml")
+		assert.Contains(t, content, "newTag: v0.0.2")
+		assert.Contains(t, content, "- deployment.yaml")
+
+		// Nothing to commit when the tag is already up to date
+		require.NoError(t, a.Execute(ctx))
+	})
+
+	t.Run("git-pull-request", func(t *testing.T) {
+		dir := newBareRepository(t)
+
+		type received struct {
+			path  string
+			auth  string
+			value map[string]string
+		}
+		ch := make(chan received, 1)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			value := map[string]string{}
+			_ = json.Unmarshal(body, &value)
+			ch <- received{path: r.URL.Path, auth: r.Header.Get("Authorization"), value: value}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"html_url": "https://example.com/pull/1"}`))
+		}))
+		defer ts.Close()
+
+		gitConfig := v1alpha1.GitConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: namespace},
+			Spec: v1alpha1.GitConfigSpec{
+				URL: dir,
+				File: v1alpha1.GitFileSpec{
+					Path: "kustomization.yaml",
+				},
+				PullRequest: &v1alpha1.GitPullRequestSpec{
+					Provider: "github",
+					APIURL:   ts.URL,
+					Token:    v1alpha1.ValueOrValueFrom{Value: "token"},
+					Title:    "Update {{ .Name }} to {{ .NewTag }}",
+				},
+			},
+		}
+
+		fakeClient := fakekubeclient.NewFakeKubeClient()
+		fakeClient.On("GetValueOrValueFrom", ctx, namespace, data).Return(gitConfig, nil)
+		fakeClient.On("GetValueOrValueFrom", ctx, namespace, gitConfig.Spec.PullRequest.Token).Return("token", nil)
+
+		a, err := actions.GetAction(actions.GitPullRequest)
+		require.NoError(t, err)
+		a.Init(fakeClient, tags, &image, data)
+
+		require.NoError(t, a.Execute(ctx))
+
+		r := <-ch
+		assert.True(t, strings.HasSuffix(r.path, "/orange-cloudavenue/gitops/pulls"), r.path)
+		assert.Equal(t, "Bearer token", r.auth)
+		assert.Equal(t, "kimup/demo-v0.0.2", r.value["head"])
+		assert.Equal(t, "main", r.value["base"])
+		assert.Equal(t, "Update demo to v0.0.2", r.value["title"])
+
+		// The base branch is not modified
+		assert.Contains(t, readFile(t, dir, "main", "kustomization.yaml"), "newTag: v0.0.1")
+		assert.Contains(t, readFile(t, dir, "kimup/demo-v0.0.2", "kustomization.yaml"), "newTag: v0.0.2")
+	})
+
+	t.Run("missing pull request configuration", func(t *testing.T) {
+		fakeClient := fakekubeclient.NewFakeKubeClient()
+		fakeClient.On("GetValueOrValueFrom", ctx, namespace, data).Return(v1alpha1.GitConfig{
+			Spec: v1alpha1.GitConfigSpec{
+				URL:  "https://example.com/org/repo.git",
+				File: v1alpha1.GitFileSpec{Path: "kustomization.yaml"},
+			},
+		}, nil)
+
+		a, err := actions.GetAction(actions.GitPullRequest)
+		require.NoError(t, err)
+		a.Init(fakeClient, tags, &image, data)
+
+		assert.ErrorIs(t, a.Execute(ctx), actions.ErrInvalidGitConfig)
+	})
+}
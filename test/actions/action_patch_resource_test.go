@@ -0,0 +1,166 @@
+package actions_test
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+func TestPatchResource_Execute(t *testing.T) {
+	namespace := "default"
+	ctx := context.TODO()
+
+	// Allow the Images to patch the Applications of the argocd namespace
+	require.NoError(t, flag.Set(models.PatchResourceNamespacesFlagName, "argocd"))
+	t.Cleanup(func() { _ = flag.Set(models.PatchResourceNamespacesFlagName, "") })
+
+	image := v1alpha1.Image{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.ImageSpec{
+			Image:   "ghcr.io/orange-cloudavenue/kimup-controller",
+			BaseTag: "v0.0.1",
+		},
+	}
+
+	tags := models.Tags{
+		Actual: "v0.0.1",
+		New:    "v0.0.2",
+	}
+
+	helmRelease := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": kubeclient.DefaultHelmReleaseAPIVersion,
+			"kind":       kubeclient.KindHelmRelease,
+			"metadata":   map[string]interface{}{"name": "demo", "namespace": namespace},
+			"spec": map[string]interface{}{
+				"values": map[string]interface{}{
+					"replicaCount": int64(2),
+					"image":        map[string]interface{}{"repository": "ghcr.io/orange-cloudavenue/kimup-controller", "tag": "v0.0.1"},
+				},
+			},
+		}}
+	}
+
+	application := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": kubeclient.DefaultApplicationAPIVersion,
+			"kind":       kubeclient.KindApplication,
+			"metadata":   map[string]interface{}{"name": "demo", "namespace": "argocd"},
+			"spec": map[string]interface{}{
+				"source": map[string]interface{}{
+					"repoURL": "https://charts.example.com",
+					"helm": map[string]interface{}{
+						"parameters": []interface{}{
+							map[string]interface{}{"name": "replicaCount", "value": "2"},
+							map[string]interface{}{"name": "image.tag", "value": "v0.0.1"},
+						},
+					},
+				},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		ref     *v1alpha1.ResourceFieldSelector
+		objects []runtime.Object
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "HelmRelease",
+			ref:     &v1alpha1.ResourceFieldSelector{Kind: kubeclient.KindHelmRelease, Name: "demo"},
+			objects: []runtime.Object{helmRelease()},
+			want:    "v0.0.2",
+		},
+		{
+			name:    "HelmRelease with a new path",
+			ref:     &v1alpha1.ResourceFieldSelector{Kind: kubeclient.KindHelmRelease, Name: "demo", Path: "sidecar.image.tag"},
+			objects: []runtime.Object{helmRelease()},
+			want:    "v0.0.2",
+		},
+		{
+			name:    "Application",
+			ref:     &v1alpha1.ResourceFieldSelector{Kind: kubeclient.KindApplication, Name: "demo", Namespace: "argocd"},
+			objects: []runtime.Object{application()},
+			want:    "v0.0.2",
+		},
+		{
+			name:    "Application with a new parameter",
+			ref:     &v1alpha1.ResourceFieldSelector{Kind: kubeclient.KindApplication, Name: "demo", Namespace: "argocd", Path: "sidecar.image.tag"},
+			objects: []runtime.Object{application()},
+			want:    "v0.0.2",
+		},
+		{
+			name:    "Namespace not allowed",
+			ref:     &v1alpha1.ResourceFieldSelector{Kind: kubeclient.KindHelmRelease, Name: "demo", Namespace: "flux-system"},
+			objects: []runtime.Object{helmRelease()},
+			wantErr: kubeclient.ErrResourceNamespaceNotAllowed,
+		},
+		{
+			name:    "Resource not found",
+			ref:     &v1alpha1.ResourceFieldSelector{Kind: kubeclient.KindHelmRelease, Name: "unknown"},
+			objects: []runtime.Object{helmRelease()},
+			wantErr: assert.AnError,
+		},
+		{
+			name:    "Kind not supported",
+			ref:     &v1alpha1.ResourceFieldSelector{Kind: "Deployment", Name: "demo"},
+			objects: []runtime.Object{helmRelease()},
+			wantErr: kubeclient.ErrResourceKindNotSupported,
+		},
+		{
+			name:    "Missing resource reference",
+			objects: []runtime.Object{helmRelease()},
+			wantErr: actions.ErrInvalidResourceRef,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fakekubeclient.NewFakeKubeClient().WithDynamicObjects(tt.objects...)
+
+			data := v1alpha1.ValueOrValueFrom{}
+			if tt.ref != nil {
+				data.ValueFrom = &v1alpha1.ValueFromSource{ResourceRef: tt.ref}
+			}
+
+			a, err := actions.GetAction(actions.PatchResource)
+			require.NoError(t, err)
+			a.Init(fakeClient, tags, &image, data)
+
+			err = a.Execute(ctx)
+			switch {
+			case tt.wantErr == assert.AnError:
+				require.Error(t, err)
+				return
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			value, err := fakeClient.Resource().GetValue(ctx, namespace, *tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+
+			// Nothing to patch when the tag is already up to date
+			require.NoError(t, a.Execute(ctx))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+// sentAlert is a request sent by shoutrrr to a chat service.
+type sentAlert struct {
+	url  string
+	body map[string]any
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// recordAlerts replaces the default HTTP transport, used by shoutrrr, with a transport recording the requests.
+func recordAlerts(t *testing.T) <-chan sentAlert {
+	t.Helper()
+
+	ch := make(chan sentAlert, 10)
+	previous := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		alert := sentAlert{url: r.URL.String()}
+		if r.Body != nil {
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &alert.body)
+		}
+		ch <- alert
+
+		// The Telegram API answers with a JSON document, the webhooks with an empty body
+		body := ""
+		if r.URL.Host == "api.telegram.org" {
+			body = `{"ok":true,"result":{"message_id":1}}`
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Request:    r,
+		}, nil
+	})
+	t.Cleanup(func() { http.DefaultTransport = previous })
+
+	return ch
+}
+
+// executeAlert executes the alert action with the AlertConfig spec.
+// The values are returned by the fake client as strings.
+func executeAlert(t *testing.T, name models.ActionName, spec v1alpha1.AlertConfigSpec, values ...v1alpha1.ValueOrValueFrom) error {
+	t.Helper()
+
+	ctx := context.TODO()
+	namespace := "default"
+	image := v1alpha1.Image{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Image",
+			APIVersion: fmt.Sprintf("%s/%s", v1alpha1.GroupVersion.Group, v1alpha1.GroupVersion.Version),
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.ImageSpec{
+			Image:   "ghcr.io/orange-cloudavenue/kimup-controller",
+			BaseTag: "v0.0.1",
+			Rules: []v1alpha1.ImageRule{
+				{
+					Name: "Always update",
+					Type: rules.Always,
+					Actions: []v1alpha1.ImageAction{
+						{
+							Type: name.String(),
+							Data: v1alpha1.ValueOrValueFrom{
+								ValueFrom: &v1alpha1.ValueFromSource{
+									AlertConfigRef: &corev1.LocalObjectReference{
+										Name: "demo",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	alertConfig := v1alpha1.AlertConfig{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}
+
+	fakeClient := fakekubeclient.NewFakeKubeClient()
+	fakeClient.On("GetValueOrValueFrom", ctx, namespace, image.Spec.Rules[0].Actions[0].Data).Return(alertConfig, nil)
+	for _, v := range values {
+		fakeClient.On("GetValueOrValueFrom", ctx, namespace, v).Return(v.Value, nil)
+	}
+
+	a, err := actions.GetAction(name)
+	require.NoError(t, err)
+
+	a.Init(fakeClient, models.Tags{
+		Actual: "1.0.0",
+		New:    "1.1.0",
+	}, &image, image.Spec.Rules[0].Actions[0].Data)
+
+	return a.Execute(ctx)
+}
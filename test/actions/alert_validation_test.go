@@ -0,0 +1,195 @@
+package actions_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/actions"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+func TestValidateAlertConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          v1alpha1.AlertConfigSpec
+		expectedError bool
+	}{
+		{
+			name: "Valid slack configuration",
+			spec: v1alpha1.AlertConfigSpec{
+				Slack: &v1alpha1.AlertSlackSpec{
+					WebhookURL: v1alpha1.ValueOrValueFrom{Value: "https://hooks.slack.com/services/T0/B0/X"},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "No alert configured",
+			spec:          v1alpha1.AlertConfigSpec{},
+			expectedError: true,
+		},
+		{
+			name: "Email without recipient",
+			spec: v1alpha1.AlertConfigSpec{
+				Email: &v1alpha1.AlertEmailSpec{
+					Host:        v1alpha1.ValueOrValueFrom{Value: "smtp.example.com"},
+					FromAddress: "kimup@example.com",
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := actions.ValidateAlertConfig(v1alpha1.AlertConfig{Spec: tt.spec})
+			if tt.expectedError {
+				assert.ErrorIs(t, err, actions.ErrInvalidAlertConfig)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateAlertTemplates(t *testing.T) {
+	tests := []struct {
+		name          string
+		templateBody  string
+		expectedError bool
+	}{
+		{
+			name:          "Default template",
+			templateBody:  "",
+			expectedError: false,
+		},
+		{
+			name:          "Valid template",
+			templateBody:  "New tag {{ .NewTag }} for {{ .ImageName }}",
+			expectedError: false,
+		},
+		{
+			name:          "Parse error",
+			templateBody:  "New tag {{ .NewTag ",
+			expectedError: true,
+		},
+		{
+			name:          "Unknown field",
+			templateBody:  "New tag {{ .Unknown }}",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := actions.ValidateAlertTemplates(v1alpha1.AlertConfig{
+				Spec: v1alpha1.AlertConfigSpec{
+					Discord: &v1alpha1.AlertDiscordSpec{
+						WebhookURL:   v1alpha1.ValueOrValueFrom{Value: "https://discord.com/api/webhooks/id/token"},
+						TemplateBody: tt.templateBody,
+					},
+				},
+			})
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestResolveAlertCredentials(t *testing.T) {
+	ctx := context.TODO()
+
+	secretRef := v1alpha1.ValueOrValueFrom{
+		ValueFrom: &v1alpha1.ValueFromSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "telegram"},
+				Key:                  "token",
+			},
+		},
+	}
+
+	alertConfig := v1alpha1.AlertConfig{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.AlertConfigSpec{
+			Telegram: &v1alpha1.AlertTelegramSpec{
+				Token: secretRef,
+				Chats: []string{"@kimup"},
+			},
+		},
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		fakeClient := fakekubeclient.NewFakeKubeClient()
+		fakeClient.On("GetValueOrValueFrom", ctx, "default", secretRef).Return("123:abc", nil)
+
+		assert.NoError(t, actions.ResolveAlertCredentials(ctx, fakeClient, alertConfig))
+	})
+
+	t.Run("Secret not found", func(t *testing.T) {
+		fakeClient := fakekubeclient.NewFakeKubeClient()
+		fakeClient.On("GetValueOrValueFrom", ctx, "default", secretRef).Return("", fmt.Errorf("secret telegram not found"))
+
+		assert.Error(t, actions.ResolveAlertCredentials(ctx, fakeClient, alertConfig))
+	})
+}
+
+func TestSendTestAlert(t *testing.T) {
+	ctx := context.TODO()
+
+	ch := make(chan []byte, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	// The test alert is sent immediately even if the updates are sent in a digest
+	alertConfig := v1alpha1.AlertConfig{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.AlertConfigSpec{
+			Webhook: &v1alpha1.AlertWebhookSpec{
+				URL: v1alpha1.ValueOrValueFrom{Value: ts.URL + "/hook"},
+			},
+			Digest: &v1alpha1.AlertDigestSpec{
+				Schedule: "0 0 9 * * *",
+			},
+		},
+	}
+
+	fakeClient := fakekubeclient.NewFakeKubeClient()
+	fakeClient.On("GetValueOrValueFrom", ctx, "default", v1alpha1.ValueOrValueFrom{
+		ValueFrom: &v1alpha1.ValueFromSource{
+			AlertConfigRef: &corev1.LocalObjectReference{Name: "demo"},
+		},
+	}).Return(alertConfig, nil)
+	fakeClient.On("GetValueOrValueFrom", ctx, "default", alertConfig.Spec.Webhook.URL).Return(alertConfig.Spec.Webhook.URL.Value, nil)
+
+	require.NoError(t, actions.SendTestAlert(ctx, fakeClient, alertConfig))
+
+	select {
+	case body := <-ch:
+		assert.Contains(t, string(body), "kimup-test")
+	default:
+		t.Fatal("the test alert was not sent")
+	}
+}
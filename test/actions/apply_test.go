@@ -52,6 +52,7 @@ func TestApply_Execute(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedTag, image.Status.Tag)
+				assert.Equal(t, tt.expectedTag, image.Status.History[0].Tag)
 			}
 		})
 	}
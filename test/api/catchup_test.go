@@ -0,0 +1,72 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+func TestCatchUpPolicy_CatchUpRuns(t *testing.T) {
+	tests := []struct {
+		policy v1alpha1.CatchUpPolicy
+		missed int
+		want   int
+	}{
+		{policy: "", missed: 3, want: 1},
+		{policy: v1alpha1.CatchUpRunOnce, missed: 0, want: 0},
+		{policy: v1alpha1.CatchUpRunOnce, missed: 3, want: 1},
+		{policy: v1alpha1.CatchUpSkip, missed: 3, want: 0},
+		{policy: v1alpha1.CatchUpRunAll, missed: 3, want: 3},
+		{policy: v1alpha1.CatchUpRunAll, missed: 42, want: v1alpha1.MaxCatchUpRuns},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.policy.CatchUpRuns(tt.missed), "%s %d", tt.policy, tt.missed)
+	}
+}
+
+func TestImage_LastSyncTime(t *testing.T) {
+	created := time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+	image := v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+
+	assert.Equal(t, created, image.LastSyncTime())
+
+	image.SetStatusTime("2024-10-02T12:00:00Z")
+	assert.Equal(t, time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC), image.LastSyncTime())
+}
+
+func TestImage_LastCrontabRunTime(t *testing.T) {
+	image := v1alpha1.Image{
+		Spec: v1alpha1.ImageSpec{
+			Triggers: []v1alpha1.ImageTrigger{
+				{Type: triggers.Crontab, Value: "0 0 * * * *"},
+				{Type: triggers.Crontab, Value: "0 0 0 * * *"},
+			},
+		},
+	}
+	image.SetStatusTime("2024-10-02T12:00:00Z")
+
+	// The time of the last sync is used until the crontab has run
+	assert.Equal(t, time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC), image.LastCrontabRunTime("0 0 0 * * *"))
+
+	// The other refreshes of the image do not change the last run of the crontab
+	image.SetCrontabRun("0 0 0 * * *", time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC))
+	image.SetStatusTime("2024-10-03T12:00:00Z")
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), image.LastCrontabRunTime("0 0 0 * * *"))
+
+	image.SetCrontabRun("0 0 0 * * *", time.Date(2024, 10, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 10, 4, 0, 0, 0, 0, time.UTC), image.LastCrontabRunTime("0 0 0 * * *"))
+	assert.Len(t, image.Status.CrontabRuns, 1)
+
+	// The runs of the removed crontabs are dropped
+	image.Spec.Triggers = image.Spec.Triggers[:1]
+	image.SetCrontabRun("0 0 * * * *", time.Date(2024, 10, 4, 1, 0, 0, 0, time.UTC))
+	assert.Equal(t, []v1alpha1.ImageStatusCrontabRun{
+		{Crontab: "0 0 * * * *", Time: metav1.NewTime(time.Date(2024, 10, 4, 1, 0, 0, 0, time.UTC))},
+	}, image.Status.CrontabRuns)
+}
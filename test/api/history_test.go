@@ -0,0 +1,29 @@
+package api_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+)
+
+func TestImage_AddHistory(t *testing.T) {
+	image := v1alpha1.Image{}
+
+	image.AddHistory("v1.0.0")
+	image.AddHistory("v1.1.0")
+	assert.Len(t, image.Status.History, 2)
+	assert.Equal(t, "v1.1.0", image.Status.History[0].Tag)
+
+	// The same tag is not recorded twice in a row
+	image.AddHistory("v1.1.0")
+	assert.Len(t, image.Status.History, 2)
+
+	for i := 0; i < 2*v1alpha1.MaxHistory; i++ {
+		image.AddHistory(fmt.Sprintf("v2.0.%d", i))
+	}
+	assert.Len(t, image.Status.History, v1alpha1.MaxHistory)
+	assert.Equal(t, fmt.Sprintf("v2.0.%d", 2*v1alpha1.MaxHistory-1), image.Status.History[0].Tag)
+}
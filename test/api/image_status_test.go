@@ -0,0 +1,127 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+)
+
+func TestLatestTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want string
+	}{
+		{name: "semver", tags: []string{"v1.0.0", "v1.10.0", "v1.2.0", "latest"}, want: "v1.10.0"},
+		{name: "prerelease ignored", tags: []string{"v1.0.0", "v2.0.0-rc.1"}, want: "v1.0.0"},
+		{name: "calver", tags: []string{"2024.01.01", "2024.10.01", "2024.02.01"}, want: "2024.10.01"},
+		{name: "no version", tags: []string{"latest", "main"}, want: ""},
+		{name: "no tag", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, v1alpha1.LatestTag(tt.tags))
+		})
+	}
+}
+
+func TestVersionsBehind(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		tags []string
+		want int
+	}{
+		{name: "semver", tag: "v1.2.0", tags: []string{"v1.0.0", "v1.2.0", "v1.10.0", "v2.0.0", "latest"}, want: 2},
+		{name: "prerelease ignored", tag: "v1.0.0", tags: []string{"v1.0.0", "v2.0.0-rc.1"}, want: 0},
+		{name: "calver", tag: "2024.02.01", tags: []string{"2024.01.01", "2024.10.01", "2024.02.01"}, want: 1},
+		{name: "up to date", tag: "v1.10.0", tags: []string{"v1.0.0", "v1.10.0"}, want: 0},
+		{name: "no version", tag: "latest", tags: []string{"v1.0.0"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, v1alpha1.VersionsBehind(tt.tag, tt.tags))
+		})
+	}
+}
+
+func TestImage_SetStatusVersionsBehind(t *testing.T) {
+	image := v1alpha1.Image{Spec: v1alpha1.ImageSpec{BaseTag: "v1.0.0"}}
+
+	// The base tag is used until a tag is applied
+	assert.Equal(t, 0, image.SetStatusVersionsBehind([]string{"v1.0.0"}))
+	assert.Nil(t, image.Status.NewerTagSince)
+
+	assert.Equal(t, 1, image.SetStatusVersionsBehind([]string{"v1.0.0", "v1.1.0"}))
+	require.NotNil(t, image.Status.NewerTagSince)
+	since := *image.Status.NewerTagSince
+
+	// The time of the first newer tag is kept
+	assert.Equal(t, 2, image.SetStatusVersionsBehind([]string{"v1.0.0", "v1.1.0", "v1.2.0"}))
+	assert.Equal(t, since, *image.Status.NewerTagSince)
+
+	image.Status.Tag = "v1.2.0"
+	assert.Equal(t, 0, image.SetStatusVersionsBehind([]string{"v1.0.0", "v1.1.0", "v1.2.0"}))
+	assert.Nil(t, image.Status.NewerTagSince)
+}
+
+func TestImage_SetReadyCondition(t *testing.T) {
+	image := v1alpha1.Image{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+
+	// No sync yet
+	image.SetReadyCondition()
+	require.NotNil(t, image.GetCondition(v1alpha1.ImageConditionReady))
+	assert.Equal(t, metav1.ConditionUnknown, image.GetCondition(v1alpha1.ImageConditionReady).Status)
+
+	image.SetCondition(v1alpha1.ImageConditionTagsFetched, metav1.ConditionTrue, v1alpha1.ImageReasonSucceeded, "")
+	image.SetCondition(v1alpha1.ImageConditionRulesEvaluated, metav1.ConditionFalse, string(v1alpha1.ImageStatusLastSyncErrorGetRule), "rule not found")
+	image.SetCondition(v1alpha1.ImageConditionActionsSucceeded, metav1.ConditionFalse, string(v1alpha1.ImageStatusLastSyncErrorAction), "action failed")
+	image.SetReadyCondition()
+
+	ready := image.GetCondition(v1alpha1.ImageConditionReady)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, string(v1alpha1.ImageStatusLastSyncErrorGetRule), ready.Reason)
+	assert.Equal(t, "rule not found", ready.Message)
+	assert.Equal(t, int64(3), ready.ObservedGeneration)
+	assert.False(t, image.IsReady())
+
+	image.SetCondition(v1alpha1.ImageConditionRulesEvaluated, metav1.ConditionTrue, v1alpha1.ImageReasonSucceeded, "")
+	image.SetCondition(v1alpha1.ImageConditionActionsSucceeded, metav1.ConditionTrue, v1alpha1.ImageReasonSucceeded, "")
+	image.SetReadyCondition()
+	assert.True(t, image.IsReady())
+}
+
+func TestImage_SetRuleStatus(t *testing.T) {
+	image := v1alpha1.Image{}
+
+	image.SetRuleStatus(v1alpha1.ImageStatusRule{Name: "patch", Result: v1alpha1.ImageRuleResultNotMatched})
+	image.SetRuleStatus(v1alpha1.ImageStatusRule{Name: "minor", Result: v1alpha1.ImageRuleResultError})
+	image.SetRuleStatus(v1alpha1.ImageStatusRule{Name: "patch", Result: v1alpha1.ImageRuleResultMatched, NewTag: "v1.0.1"})
+
+	require.Len(t, image.Status.Rules, 2)
+	assert.Equal(t, v1alpha1.ImageRuleResultMatched, image.Status.Rules[0].Result)
+	assert.Equal(t, "v1.0.1", image.Status.Rules[0].NewTag)
+	assert.Equal(t, v1alpha1.ImageRuleResultError, image.Status.Rules[1].Result)
+}
+
+func TestImage_GetRuleStatus(t *testing.T) {
+	image := v1alpha1.Image{}
+	assert.Nil(t, image.GetRuleStatus("patch"))
+
+	rule := v1alpha1.ImageStatusRule{Name: "patch", Result: v1alpha1.ImageRuleResultMatched, NewTag: "v1.0.1"}
+	rule.AddActionStatus(v1alpha1.ImageStatusAction{Type: "apply", Result: v1alpha1.ImageActionResultSucceeded})
+	rule.AddActionStatus(v1alpha1.ImageStatusAction{Type: "alert-email", Result: v1alpha1.ImageActionResultFailed, Message: "dial tcp: connection refused"})
+	image.SetRuleStatus(rule)
+
+	got := image.GetRuleStatus("patch")
+	require.NotNil(t, got)
+	require.Len(t, got.Actions, 2)
+	assert.Equal(t, v1alpha1.ImageActionResultFailed, got.Actions[1].Result)
+	assert.Equal(t, "dial tcp: connection refused", got.Actions[1].Message)
+}
@@ -0,0 +1,45 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+)
+
+func TestImage_ShouldMutate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy v1alpha1.MutationPolicy
+		status string
+		tag    string
+		want   bool
+	}{
+		{name: "default policy", tag: "v9.9.9", status: "v1.0.1", want: true},
+		{name: "always", policy: v1alpha1.MutationPolicyAlways, tag: "v9.9.9", status: "v1.0.1", want: true},
+		{name: "no tag", policy: v1alpha1.MutationPolicyOnlyIfBaseTag, tag: "", status: "v1.0.1", want: true},
+		{name: "onlyIfOlder with an older semver tag", policy: v1alpha1.MutationPolicyOnlyIfOlder, tag: "v1.0.0", status: "v1.0.1", want: true},
+		{name: "onlyIfOlder with the same tag", policy: v1alpha1.MutationPolicyOnlyIfOlder, tag: "v1.0.1", status: "v1.0.1", want: false},
+		{name: "onlyIfOlder with a newer semver tag", policy: v1alpha1.MutationPolicyOnlyIfOlder, tag: "v1.1.0", status: "v1.0.1", want: false},
+		{name: "onlyIfOlder with an older calver tag", policy: v1alpha1.MutationPolicyOnlyIfOlder, tag: "2024.1.1", status: "2024.2.1", want: true},
+		{name: "onlyIfOlder without status tag", policy: v1alpha1.MutationPolicyOnlyIfOlder, tag: "v0.9.0", want: true},
+		{name: "onlyIfOlder with a tag not comparable", policy: v1alpha1.MutationPolicyOnlyIfOlder, tag: "latest", status: "v1.0.1", want: false},
+		{name: "onlyIfBaseTag with the base tag", policy: v1alpha1.MutationPolicyOnlyIfBaseTag, tag: "v1.0.0", status: "v1.0.1", want: true},
+		{name: "onlyIfBaseTag with another tag", policy: v1alpha1.MutationPolicyOnlyIfBaseTag, tag: "v0.9.0", status: "v1.0.1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := v1alpha1.Image{
+				Spec: v1alpha1.ImageSpec{
+					BaseTag:        "v1.0.0",
+					MutationPolicy: tt.policy,
+				},
+				Status: v1alpha1.ImageStatus{Tag: tt.status},
+			}
+
+			assert.Equal(t, tt.want, image.ShouldMutate(tt.tag))
+		})
+	}
+}
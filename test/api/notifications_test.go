@@ -0,0 +1,64 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+)
+
+func TestImage_IsAlreadyNotified(t *testing.T) {
+	action := v1alpha1.ImageAction{
+		Type: "alert-slack",
+		Data: v1alpha1.ValueOrValueFrom{
+			ValueFrom: &v1alpha1.ValueFromSource{
+				AlertConfigRef: &corev1.LocalObjectReference{Name: "demo"},
+			},
+		},
+	}
+	otherAction := v1alpha1.ImageAction{
+		Type: "alert-slack",
+		Data: v1alpha1.ValueOrValueFrom{
+			ValueFrom: &v1alpha1.ValueFromSource{
+				AlertConfigRef: &corev1.LocalObjectReference{Name: "other"},
+			},
+		},
+	}
+
+	image := v1alpha1.Image{}
+	assert.False(t, image.IsAlreadyNotified("rule", action, "v1.0.1"))
+
+	image.SetNotified("rule", action, "v1.0.1")
+	assert.True(t, image.IsAlreadyNotified("rule", action, "v1.0.1"))
+	assert.False(t, image.IsAlreadyNotified("rule", action, "v1.0.2"))
+	assert.False(t, image.IsAlreadyNotified("other-rule", action, "v1.0.1"))
+	assert.False(t, image.IsAlreadyNotified("rule", otherAction, "v1.0.1"))
+
+	// A new tag replaces the previous notification
+	image.SetNotified("rule", action, "v1.0.2")
+	assert.Len(t, image.Status.Notifications, 1)
+	assert.True(t, image.IsAlreadyNotified("rule", action, "v1.0.2"))
+}
+
+func TestAlertConfig_Pending(t *testing.T) {
+	aC := v1alpha1.AlertConfig{}
+
+	aC.AddPending(v1alpha1.AlertDigestEntry{Action: "alert-slack", Name: "demo", NewTag: "v1.0.1"})
+	aC.AddPending(v1alpha1.AlertDigestEntry{Action: "alert-slack", Name: "other", NewTag: "v2.0.1"})
+	assert.Len(t, aC.Status.Pending, 2)
+
+	// A new tag for the same image replaces the pending update
+	aC.AddPending(v1alpha1.AlertDigestEntry{Action: "alert-slack", Name: "demo", NewTag: "v1.0.2"})
+	assert.Len(t, aC.Status.Pending, 2)
+	assert.Equal(t, "v1.0.2", aC.Status.Pending[0].NewTag)
+
+	// An update queued again since the digest has been built is kept
+	aC.RemovePending([]v1alpha1.AlertDigestEntry{
+		{Action: "alert-slack", Name: "demo", NewTag: "v1.0.1"},
+		{Action: "alert-slack", Name: "other", NewTag: "v2.0.1"},
+	})
+	assert.Len(t, aC.Status.Pending, 1)
+	assert.Equal(t, "demo", aC.Status.Pending[0].Name)
+}
@@ -0,0 +1,59 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/controller"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+func TestAlertConfigValidator_ValidateUpdate(t *testing.T) {
+	ctx := context.TODO()
+	validator := &controller.AlertConfigValidator{KubeAPIClient: fakekubeclient.NewFakeKubeClient()}
+
+	// The AlertConfig has been created before the validation of the alerts
+	legacy := &v1alpha1.AlertConfig{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "default",
+			Annotations: map[string]string{"kimup.cloudavenue.io/test-send": "true"},
+			Finalizers:  []string{"kimup.cloudavenue.io/finalizer"},
+		},
+	}
+
+	t.Run("Metadata only", func(t *testing.T) {
+		updated := legacy.DeepCopy()
+		updated.Annotations = nil
+		updated.Finalizers = nil
+
+		warnings, err := validator.ValidateUpdate(ctx, legacy, updated)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("Invalid spec", func(t *testing.T) {
+		updated := legacy.DeepCopy()
+		updated.Spec.Slack = &v1alpha1.AlertSlackSpec{}
+
+		_, err := validator.ValidateUpdate(ctx, legacy, updated)
+		require.Error(t, err)
+		assert.True(t, apierrors.IsInvalid(err))
+	})
+
+	t.Run("Valid spec", func(t *testing.T) {
+		updated := legacy.DeepCopy()
+		updated.Spec.Slack = &v1alpha1.AlertSlackSpec{
+			WebhookURL: v1alpha1.ValueOrValueFrom{Value: "https://hooks.slack.com/services/T000/B000/XXXX"},
+		}
+
+		_, err := validator.ValidateUpdate(ctx, legacy, updated)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,154 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/controller"
+)
+
+func TestClusterImageReconciler_Reconcile(t *testing.T) {
+	ctx := context.TODO()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	spec := v1alpha1.ImageSpec{
+		Image:   "ghcr.io/orange-cloudavenue/demo",
+		BaseTag: "v0.0.1",
+		Triggers: []v1alpha1.ImageTrigger{
+			{Type: "crontab", Value: "0 0 * * * *"},
+		},
+		Rules: []v1alpha1.ImageRule{
+			{Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{Type: "apply"}}},
+		},
+	}
+
+	clusterImage := &v1alpha1.ClusterImage{
+		ObjectMeta: v1.ObjectMeta{Name: "demo", Generation: 1, UID: "demo"},
+		Spec: v1alpha1.ClusterImageSpec{
+			NamespaceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			ImageSpec:         spec,
+		},
+	}
+
+	// The namespace-level Image overrides the ClusterImage
+	override := &v1alpha1.Image{
+		ObjectMeta: v1.ObjectMeta{Name: "custom", Namespace: "web-override"},
+		Spec:       v1alpha1.ImageSpec{Image: spec.Image, BaseTag: "v1.0.0"},
+	}
+
+	controllerRef := []v1.OwnerReference{{
+		APIVersion: v1alpha1.GroupVersion.String(),
+		Kind:       "ClusterImage",
+		Name:       "demo",
+		UID:        "demo",
+		Controller: ptr.To(true),
+	}}
+
+	// Image created in a namespace no longer selected
+	stale := &v1alpha1.Image{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            "demo",
+			Namespace:       "other",
+			Labels:          map[string]string{v1alpha1.LabelClusterImage: "demo"},
+			OwnerReferences: controllerRef,
+		},
+		Spec: spec,
+	}
+
+	// Image of a user labelled as created from the ClusterImage
+	labelled := &v1alpha1.Image{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "tenant",
+			Labels:    map[string]string{v1alpha1.LabelClusterImage: "demo"},
+		},
+		Spec: v1alpha1.ImageSpec{Image: spec.Image, BaseTag: "v1.0.0"},
+	}
+
+	k := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.ClusterImage{}).
+		WithObjects(
+			clusterImage,
+			namespace("web-1", map[string]string{"team": "web"}),
+			namespace("web-2", map[string]string{"team": "web"}),
+			namespace("web-override", map[string]string{"team": "web"}),
+			namespace("other", nil),
+			namespace("tenant", nil),
+			override,
+			stale,
+			labelled,
+		).
+		Build()
+
+	r := &controller.ClusterImageReconciler{
+		Client:   k,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "demo"}})
+	require.NoError(t, err)
+
+	for _, ns := range []string{"web-1", "web-2"} {
+		var image v1alpha1.Image
+		require.NoError(t, k.Get(ctx, types.NamespacedName{Namespace: ns, Name: "demo"}, &image))
+		assert.Equal(t, spec, image.Spec)
+		assert.True(t, image.IsManagedByClusterImage())
+		require.Len(t, image.OwnerReferences, 1)
+		assert.Equal(t, "ClusterImage", image.OwnerReferences[0].Kind)
+	}
+
+	err = k.Get(ctx, types.NamespacedName{Namespace: "web-override", Name: "demo"}, &v1alpha1.Image{})
+	assert.True(t, apierrors.IsNotFound(err))
+	err = k.Get(ctx, client.ObjectKeyFromObject(stale), &v1alpha1.Image{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// The Image of the user is neither managed nor removed
+	var tenant v1alpha1.Image
+	require.NoError(t, k.Get(ctx, client.ObjectKeyFromObject(labelled), &tenant))
+	assert.False(t, tenant.IsManagedByClusterImage())
+	assert.Equal(t, "v1.0.0", tenant.Spec.BaseTag)
+
+	var got v1alpha1.ClusterImage
+	require.NoError(t, k.Get(ctx, types.NamespacedName{Name: "demo"}, &got))
+	assert.Equal(t, []string{"web-1", "web-2"}, got.Status.Namespaces)
+	assert.Equal(t, []string{"web-override"}, got.Status.Overridden)
+	assert.Equal(t, int64(1), got.Status.ObservedGeneration)
+
+	// The spec and the label of the managed Images are restored
+	var image v1alpha1.Image
+	require.NoError(t, k.Get(ctx, types.NamespacedName{Namespace: "web-1", Name: "demo"}, &image))
+	image.Spec.BaseTag = "v9.9.9"
+	delete(image.Labels, v1alpha1.LabelClusterImage)
+	require.NoError(t, k.Update(ctx, &image))
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "demo"}})
+	require.NoError(t, err)
+	require.NoError(t, k.Get(ctx, types.NamespacedName{Namespace: "web-1", Name: "demo"}, &image))
+	assert.Equal(t, "v0.0.1", image.Spec.BaseTag)
+	assert.Equal(t, "demo", image.Labels[v1alpha1.LabelClusterImage])
+}
@@ -0,0 +1,96 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/controller"
+)
+
+func TestClusterImageValidator_ValidateCreate(t *testing.T) {
+	ctx := context.TODO()
+	validator := &controller.ClusterImageValidator{}
+
+	newClusterImage := func(selector *v1.LabelSelector, rules ...v1alpha1.ImageRule) *v1alpha1.ClusterImage {
+		return &v1alpha1.ClusterImage{
+			ObjectMeta: v1.ObjectMeta{Name: "demo"},
+			Spec: v1alpha1.ClusterImageSpec{
+				NamespaceSelector: selector,
+				ImageSpec: v1alpha1.ImageSpec{
+					Image:    "ghcr.io/orange-cloudavenue/demo",
+					BaseTag:  "v1.0.0",
+					Triggers: []v1alpha1.ImageTrigger{{Type: "crontab", Value: "0 */5 * * * *"}},
+					Rules:    rules,
+				},
+			},
+		}
+	}
+
+	validRule := v1alpha1.ImageRule{Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{Type: "apply"}}}
+
+	tests := []struct {
+		name         string
+		clusterImage *v1alpha1.ClusterImage
+		wantErr      []string
+	}{
+		{
+			name:         "Valid cluster image",
+			clusterImage: newClusterImage(&v1.LabelSelector{MatchLabels: map[string]string{"team": "demo"}}, validRule),
+		},
+		{
+			name: "Invalid namespace selector",
+			clusterImage: newClusterImage(&v1.LabelSelector{MatchExpressions: []v1.LabelSelectorRequirement{
+				{Key: "team", Operator: "Contains", Values: []string{"demo"}},
+			}}, validRule),
+			wantErr: []string{"spec.namespaceSelector"},
+		},
+		{
+			name: "Invalid regex",
+			clusterImage: newClusterImage(nil, v1alpha1.ImageRule{
+				Name: "regex", Type: "regex", Value: "^(v1", Actions: []v1alpha1.ImageAction{{Type: "apply"}},
+			}),
+			wantErr: []string{"spec.rules[0]", "invalid rule value"},
+		},
+		{
+			name: "Alert action without alertConfigRef",
+			clusterImage: newClusterImage(nil, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{Type: "alert-slack"}},
+			}),
+			wantErr: []string{"spec.rules[0].actions[0]", "alertConfigRef"},
+		},
+		{
+			name: "AlertConfig not checked",
+			clusterImage: newClusterImage(nil, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{
+					Type: "alert-slack",
+					Data: v1alpha1.ValueOrValueFrom{ValueFrom: &v1alpha1.ValueFromSource{AlertConfigRef: &corev1.LocalObjectReference{Name: "slack"}}},
+				}},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := validator.ValidateCreate(ctx, tt.clusterImage)
+			assert.Empty(t, warnings)
+
+			if len(tt.wantErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			for _, msg := range tt.wantErr {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
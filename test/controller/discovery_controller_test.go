@@ -0,0 +1,191 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/controller"
+)
+
+func TestDiscoveryReconciler_Reconcile(t *testing.T) {
+	ctx := context.TODO()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	const template = `
+triggers:
+  - type: crontab
+    value: "0 0 * * * *"
+rules:
+  - name: notify
+    type: semver-minor
+    actions:
+      - type: alert-discord
+        data:
+          valueFrom:
+            alertConfigRef:
+              name: discord
+`
+
+	objects := func(ns string, nsAnnotations map[string]string) []client.Object {
+		return []client.Object{
+			&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: ns, Annotations: nsAnnotations}},
+			&corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: ns},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "init", Image: "registry.example.com/migrate:v1.0.0"}},
+					Containers: []corev1.Container{
+						{Name: "sidecar", Image: "busybox"},
+						{Name: "nginx", Image: "docker.io/library/nginx:1.27.0"},
+						{Name: "managed", Image: "ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+						{Name: "digest", Image: "registry.example.com/app@sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+					},
+				},
+			},
+			&corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "web-2", Namespace: ns},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "docker.io/library/nginx:1.27.0"}},
+				},
+			},
+			&v1alpha1.Image{
+				ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: ns},
+				Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/demo"},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: "kimup-template", Namespace: ns},
+				Data:       map[string]string{controller.DiscoveryTemplateKey: template},
+			},
+		}
+	}
+
+	reconcileWithRecorder := func(t *testing.T, ns string, nsAnnotations map[string]string, funcs interceptor.Funcs) (client.Client, *record.FakeRecorder) {
+		t.Helper()
+
+		k := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects(ns, nsAnnotations)...).WithInterceptorFuncs(funcs).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &controller.DiscoveryReconciler{
+			Client:   k,
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: ns}})
+		require.NoError(t, err)
+		return k, recorder
+	}
+
+	reconcile := func(t *testing.T, ns string, nsAnnotations map[string]string) client.Client {
+		t.Helper()
+
+		k, _ := reconcileWithRecorder(t, ns, nsAnnotations, interceptor.Funcs{})
+		return k
+	}
+
+	t.Run("report", func(t *testing.T) {
+		k := reconcile(t, "report", map[string]string{
+			string(annotations.KeyEnabled):   "true",
+			string(annotations.KeyDiscovery): "report",
+		})
+
+		var ns corev1.Namespace
+		require.NoError(t, k.Get(ctx, types.NamespacedName{Name: "report"}, &ns))
+		an := annotations.New(ctx, &ns)
+		assert.Equal(t, []annotations.DiscoveredImage{
+			{Image: "busybox", BaseTag: "latest", Pods: []string{"web"}},
+			{Image: "docker.io/library/nginx", BaseTag: "1.27.0", Pods: []string{"web", "web-2"}},
+		}, an.Discovered().Get())
+
+		var images v1alpha1.ImageList
+		require.NoError(t, k.List(ctx, &images, client.InNamespace("report")))
+		assert.Len(t, images.Items, 1)
+	})
+
+	t.Run("create with the default template", func(t *testing.T) {
+		k := reconcile(t, "create", map[string]string{
+			string(annotations.KeyEnabled):   "true",
+			string(annotations.KeyDiscovery): "create",
+		})
+
+		var image v1alpha1.Image
+		require.NoError(t, k.Get(ctx, types.NamespacedName{Namespace: "create", Name: "nginx"}, &image))
+		assert.Equal(t, "docker.io/library/nginx", image.Spec.Image)
+		assert.Equal(t, "1.27.0", image.Spec.BaseTag)
+		assert.Equal(t, controller.DefaultDiscoveryTemplate.Rules, image.Spec.Rules)
+		assert.Equal(t, controller.DiscoveryManagedBy, image.Labels[controller.KubernetesManagedByLabelKey])
+
+		require.NoError(t, k.Get(ctx, types.NamespacedName{Namespace: "create", Name: "busybox"}, &image))
+		assert.Equal(t, "latest", image.Spec.BaseTag)
+	})
+
+	t.Run("create skips the rejected images", func(t *testing.T) {
+		// The validating webhook rejects the semver rules for the latest tag
+		k, recorder := reconcileWithRecorder(t, "rejected", map[string]string{
+			string(annotations.KeyEnabled):   "true",
+			string(annotations.KeyDiscovery): "create",
+		}, interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if image, ok := obj.(*v1alpha1.Image); ok && image.Spec.BaseTag == "latest" {
+					return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("Image").GroupKind(), image.Name, field.ErrorList{
+						field.Invalid(field.NewPath("spec", "baseTag"), image.Spec.BaseTag, "not a semver tag"),
+					})
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		})
+
+		var image v1alpha1.Image
+		require.NoError(t, k.Get(ctx, types.NamespacedName{Namespace: "rejected", Name: "nginx"}, &image))
+		assert.True(t, apierrors.IsNotFound(k.Get(ctx, types.NamespacedName{Namespace: "rejected", Name: "busybox"}, &image)))
+
+		events := []string{}
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		assert.Contains(t, events[0], "Warning ImageDiscovery Image busybox:latest not created")
+	})
+
+	t.Run("create with the template of the namespace", func(t *testing.T) {
+		k := reconcile(t, "template", map[string]string{
+			string(annotations.KeyEnabled):           "true",
+			string(annotations.KeyDiscovery):         "create",
+			string(annotations.KeyDiscoveryTemplate): "kimup-template",
+		})
+
+		var image v1alpha1.Image
+		require.NoError(t, k.Get(ctx, types.NamespacedName{Namespace: "template", Name: "nginx"}, &image))
+		require.Len(t, image.Spec.Rules, 1)
+		assert.Equal(t, "notify", image.Spec.Rules[0].Name)
+		assert.Equal(t, "0 0 * * * *", image.Spec.Triggers[0].Value)
+	})
+
+	t.Run("namespace not enabled", func(t *testing.T) {
+		k := reconcile(t, "disabled", map[string]string{
+			string(annotations.KeyDiscovery): "create",
+		})
+
+		var images v1alpha1.ImageList
+		require.NoError(t, k.List(ctx, &images, client.InNamespace("disabled")))
+		assert.Len(t, images.Items, 1)
+	})
+}
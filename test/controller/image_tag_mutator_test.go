@@ -0,0 +1,330 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/controller"
+)
+
+func TestImageTagMutator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	images := []*v1alpha1.Image{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: "default"},
+			Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/demo", BaseTag: "v0.0.1"},
+			Status:     v1alpha1.ImageStatus{Tag: "v0.0.2"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: "other"},
+			Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/demo", BaseTag: "v1.0.0"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "sidecar", Namespace: "default"},
+			Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/sidecar", BaseTag: "v0.0.1"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: "only-if-older"},
+			Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/demo", BaseTag: "v0.0.1", MutationPolicy: v1alpha1.MutationPolicyOnlyIfOlder},
+			Status:     v1alpha1.ImageStatus{Tag: "v0.0.2"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: "only-if-base-tag"},
+			Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/demo", BaseTag: "v0.0.1", MutationPolicy: v1alpha1.MutationPolicyOnlyIfBaseTag},
+			Status:     v1alpha1.ImageStatus{Tag: "v0.0.2"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "a-cluster",
+				Namespace: "override",
+				Labels:    map[string]string{v1alpha1.LabelClusterImage: "a-cluster"},
+				OwnerReferences: []v1.OwnerReference{{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       "ClusterImage",
+					Name:       "a-cluster",
+					UID:        "a-cluster",
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/demo", BaseTag: "v0.0.1"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: "override"},
+			Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/orange-cloudavenue/demo", BaseTag: "v2.0.0"},
+		},
+	}
+
+	builder := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&v1alpha1.Image{}, controller.ImageSpecImageIndex, controller.IndexImageSpecImage)
+	for _, image := range images {
+		builder = builder.WithObjects(image)
+	}
+
+	mutator := &controller.ImageTagMutator{
+		Client: builder.Build(),
+		Scheme: scheme,
+	}
+	handler := mutator.SetupHandler()
+
+	tests := []struct {
+		name      string
+		namespace string
+		pin       string
+		images    []string
+		want      []string
+		mutated   []string
+	}{
+		{
+			name:      "Image with a status tag",
+			namespace: "default",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.2"},
+			mutated:   []string{"a"},
+		},
+		{
+			name:      "Image already up to date",
+			namespace: "default",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:v0.0.2"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.2"},
+		},
+		{
+			name:      "Image of another namespace",
+			namespace: "other",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v1.0.0"},
+			mutated:   []string{"a"},
+		},
+		{
+			name:      "Several containers",
+			namespace: "default",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:latest", "ghcr.io/orange-cloudavenue/sidecar:v0.0.0", "nginx:latest"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.2", "ghcr.io/orange-cloudavenue/sidecar:v0.0.1", "nginx:latest"},
+			mutated:   []string{"a", "b"},
+		},
+		{
+			name:      "Pinned container",
+			namespace: "default",
+			pin:       "b",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:latest", "ghcr.io/orange-cloudavenue/sidecar:v0.0.0"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.2", "ghcr.io/orange-cloudavenue/sidecar:v0.0.0"},
+			mutated:   []string{"a"},
+		},
+		{
+			name:      "Pinned pod",
+			namespace: "default",
+			pin:       "*",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:latest", "ghcr.io/orange-cloudavenue/sidecar:v0.0.0"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:latest", "ghcr.io/orange-cloudavenue/sidecar:v0.0.0"},
+		},
+		{
+			name:      "onlyIfOlder with an older tag",
+			namespace: "only-if-older",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.2"},
+			mutated:   []string{"a"},
+		},
+		{
+			name:      "onlyIfOlder with a newer tag",
+			namespace: "only-if-older",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:v0.1.0"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.1.0"},
+		},
+		{
+			name:      "onlyIfBaseTag with the base tag",
+			namespace: "only-if-base-tag",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.2"},
+			mutated:   []string{"a"},
+		},
+		{
+			name:      "onlyIfBaseTag with another tag",
+			namespace: "only-if-base-tag",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:v0.0.0"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.0"},
+		},
+		{
+			name:      "Namespace Image overrides the ClusterImage",
+			namespace: "override",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v2.0.0"},
+			mutated:   []string{"a"},
+		},
+		{
+			name:      "No image in the namespace",
+			namespace: "empty",
+			images:    []string{"ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+			want:      []string{"ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{
+				TypeMeta:   v1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: tt.namespace},
+			}
+			if tt.pin != "" {
+				pod.Annotations = map[string]string{string(annotations.KeyPin): tt.pin}
+			}
+			for i, image := range tt.images {
+				pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: string(rune('a' + i)), Image: image})
+			}
+
+			raw, err := json.Marshal(pod)
+			require.NoError(t, err)
+
+			resp := handler.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Namespace: tt.namespace,
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			require.True(t, resp.Allowed)
+
+			// Apply the patches to the images of the containers
+			got := append([]string{}, tt.images...)
+			var mutationsAnnotation string
+			for _, p := range resp.Patches {
+				var i int
+				if _, err := fmt.Sscanf(p.Path, "/spec/containers/%d/image", &i); err == nil {
+					got[i] = p.Value.(string)
+				}
+				switch p.Path {
+				case "/metadata/annotations":
+					mutationsAnnotation = p.Value.(map[string]interface{})[string(annotations.KeyMutations)].(string)
+				case "/metadata/annotations/kimup.cloudavenue.io~1mutations":
+					mutationsAnnotation = p.Value.(string)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+
+			if len(tt.mutated) == 0 {
+				assert.Empty(t, mutationsAnnotation)
+				assert.Empty(t, resp.Warnings)
+				assert.Empty(t, resp.AuditAnnotations)
+				return
+			}
+
+			mutations := []annotations.ContainerMutation{}
+			require.NoError(t, json.Unmarshal([]byte(mutationsAnnotation), &mutations))
+			require.Len(t, mutations, len(tt.mutated))
+			for i, m := range mutations {
+				assert.Equal(t, tt.mutated[i], m.Container)
+				assert.Equal(t, tt.images[i], m.OriginalImage)
+				assert.Equal(t, tt.want[i], m.Image)
+				assert.NotEmpty(t, m.ImageRef)
+				assert.NotEmpty(t, m.Time)
+			}
+
+			assert.Len(t, resp.Warnings, len(tt.mutated))
+			assert.JSONEq(t, mutationsAnnotation, resp.AuditAnnotations["mutations"])
+		})
+	}
+}
+
+func TestImageTagMutator_HandleOnPodCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	newImage := func(name, lastSync string) *v1alpha1.Image {
+		return &v1alpha1.Image{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.ImageSpec{
+				Image:    "ghcr.io/orange-cloudavenue/" + name,
+				BaseTag:  "v0.0.1",
+				Triggers: []v1alpha1.ImageTrigger{{Type: "on-pod-create"}},
+			},
+			Status: v1alpha1.ImageStatus{Time: lastSync},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		dryRun      bool
+		lastSync    string
+		wantRefresh bool
+	}{
+		{name: "Pod created", operation: admissionv1.Create, wantRefresh: true},
+		{name: "Pod updated", operation: admissionv1.Update},
+		{name: "Dry run", operation: admissionv1.Create, dryRun: true},
+		{name: "Image synchronized recently", operation: admissionv1.Create, lastSync: time.Now().Format(time.RFC3339)},
+		{name: "Image synchronized a while ago", operation: admissionv1.Create, lastSync: time.Now().Add(-time.Hour).Format(time.RFC3339), wantRefresh: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithIndex(&v1alpha1.Image{}, controller.ImageSpecImageIndex, controller.IndexImageSpecImage).
+				WithObjects(newImage("demo", tt.lastSync), newImage("sidecar", "")).
+				Build()
+
+			mutator := &controller.ImageTagMutator{Client: k, Scheme: scheme}
+			handler := mutator.SetupHandler()
+
+			// The refreshes are patched asynchronously by the mutator
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			go func() { _ = mutator.Start(ctx) }()
+
+			pod := corev1.Pod{
+				TypeMeta:   v1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: "default"},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "a", Image: "ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+					{Name: "b", Image: "ghcr.io/orange-cloudavenue/demo:v0.0.1"},
+				}},
+			}
+			raw, err := json.Marshal(pod)
+			require.NoError(t, err)
+
+			resp := handler.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Namespace: "default",
+				Operation: tt.operation,
+				DryRun:    &tt.dryRun,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			require.True(t, resp.Allowed)
+
+			refreshRequested := func() bool {
+				var image v1alpha1.Image
+				require.NoError(t, k.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "demo"}, &image))
+				return image.Annotations[string(annotations.KeyAction)] == string(annotations.ActionRefresh)
+			}
+			if tt.wantRefresh {
+				assert.Eventually(t, refreshRequested, time.Second, 10*time.Millisecond)
+			} else {
+				assert.Never(t, refreshRequested, 100*time.Millisecond, 10*time.Millisecond)
+			}
+
+			var image v1alpha1.Image
+
+			// The Images not used by the Pod are not refreshed
+			require.NoError(t, k.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "sidecar"}, &image))
+			assert.Empty(t, image.Annotations[string(annotations.KeyAction)])
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/controller"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
+	"github.com/orange-cloudavenue/kube-image-updater/test/mocks/fakekubeclient"
+)
+
+func TestImageValidator_ValidateCreate(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "default"
+
+	alertConfig := v1alpha1.AlertConfig{
+		TypeMeta:   v1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "AlertConfig"},
+		ObjectMeta: v1.ObjectMeta{Name: "slack", Namespace: namespace},
+		Spec: v1alpha1.AlertConfigSpec{
+			Slack: &v1alpha1.AlertSlackSpec{WebhookURL: v1alpha1.ValueOrValueFrom{Value: "https://hooks.slack.com/services/xxx"}},
+		},
+	}
+	u, err := kubeclient.EncodeUnstructured(alertConfig)
+	require.NoError(t, err)
+
+	fakeClient := fakekubeclient.NewFakeKubeClient().WithDynamicObjects(u)
+	_, err = fakeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "token", Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte("dG9rZW4=")},
+	}, v1.CreateOptions{})
+	require.NoError(t, err)
+
+	validator := &controller.ImageValidator{KubeAPIClient: fakeClient}
+
+	newImage := func(triggers []v1alpha1.ImageTrigger, rules ...v1alpha1.ImageRule) *v1alpha1.Image {
+		return &v1alpha1.Image{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: namespace},
+			Spec: v1alpha1.ImageSpec{
+				Image:    "ghcr.io/orange-cloudavenue/demo",
+				BaseTag:  "v1.0.0",
+				Triggers: triggers,
+				Rules:    rules,
+			},
+		}
+	}
+
+	validTriggers := []v1alpha1.ImageTrigger{{Type: "crontab", Value: "0 */5 * * * *"}}
+	validRule := v1alpha1.ImageRule{Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{Type: "apply"}}}
+
+	tests := []struct {
+		name         string
+		image        *v1alpha1.Image
+		wantErr      []string
+		wantWarnings int
+	}{
+		{
+			name:  "Valid image",
+			image: newImage(validTriggers, validRule),
+		},
+		{
+			name:    "Invalid crontab",
+			image:   newImage([]v1alpha1.ImageTrigger{{Type: "crontab", Value: "*/5 * * * *"}}, validRule),
+			wantErr: []string{"spec.triggers[0].value", "invalid crontab expression"},
+		},
+		{
+			name: "Valid interval, on-pod-create and dependency triggers",
+			image: newImage([]v1alpha1.ImageTrigger{
+				{Type: "interval", Every: &v1.Duration{Duration: 15 * time.Minute}},
+				{Type: "on-pod-create"},
+				{Type: "dependency", ImageRef: &corev1.LocalObjectReference{Name: "app"}},
+			}, validRule),
+		},
+		{
+			name:    "Interval too short",
+			image:   newImage([]v1alpha1.ImageTrigger{{Type: "interval", Every: &v1.Duration{Duration: 10 * time.Second}}}, validRule),
+			wantErr: []string{"spec.triggers[0].every", "lower than 1m0s"},
+		},
+		{
+			name:    "Interval without every",
+			image:   newImage([]v1alpha1.ImageTrigger{{Type: "interval"}}, validRule),
+			wantErr: []string{"spec.triggers[0].every"},
+		},
+		{
+			name:    "Dependency on itself",
+			image:   newImage([]v1alpha1.ImageTrigger{{Type: "dependency", ImageRef: &corev1.LocalObjectReference{Name: "demo"}}}, validRule),
+			wantErr: []string{"spec.triggers[0].imageRef.name", "can not depend on itself"},
+		},
+		{
+			name: "Invalid regex",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "regex", Type: "regex", Value: "^(v1", Actions: []v1alpha1.ImageAction{{Type: "apply"}},
+			}),
+			wantErr: []string{"spec.rules[0]", "invalid rule value"},
+		},
+		{
+			name: "Base tag incompatible with the rule",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "calver", Type: "calver-major", Actions: []v1alpha1.ImageAction{{Type: "apply"}},
+			}),
+			wantErr: []string{"spec.rules[0]", "invalid base tag"},
+		},
+		{
+			name:    "Duplicate rule name",
+			image:   newImage(validTriggers, validRule, validRule),
+			wantErr: []string{"spec.rules[1].name", "Duplicate value"},
+		},
+		{
+			name: "Unknown action",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{Type: "request-approval"}},
+			}),
+			wantErr: []string{"spec.rules[0].actions[0]", "action not found"},
+		},
+		{
+			name: "Alert action without alertConfigRef",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{Type: "alert-slack"}},
+			}),
+			wantErr: []string{"spec.rules[0].actions[0]", "alertConfigRef"},
+		},
+		{
+			name: "Alert action not configured in the AlertConfig",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{
+					Type: "alert-discord",
+					Data: v1alpha1.ValueOrValueFrom{ValueFrom: &v1alpha1.ValueFromSource{AlertConfigRef: &corev1.LocalObjectReference{Name: "slack"}}},
+				}},
+			}),
+			wantErr: []string{"spec.rules[0].actions[0]", "has no configuration for the action alert-discord"},
+		},
+		{
+			name: "Alert action with an existing AlertConfig",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{
+					Type: "alert-slack",
+					Data: v1alpha1.ValueOrValueFrom{ValueFrom: &v1alpha1.ValueFromSource{AlertConfigRef: &corev1.LocalObjectReference{Name: "slack"}}},
+				}},
+			}),
+		},
+		{
+			name: "AlertConfig not found",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{
+					Type: "alert-slack",
+					Data: v1alpha1.ValueOrValueFrom{ValueFrom: &v1alpha1.ValueFromSource{AlertConfigRef: &corev1.LocalObjectReference{Name: "unknown"}}},
+				}},
+			}),
+			wantWarnings: 1,
+		},
+		{
+			name: "Several sources in valueFrom",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{
+					Type: "git-commit",
+					Data: v1alpha1.ValueOrValueFrom{ValueFrom: &v1alpha1.ValueFromSource{
+						GitConfigRef:    &corev1.LocalObjectReference{Name: "demo"},
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "demo"}, Key: "key"},
+					}},
+				}},
+			}),
+			wantErr: []string{"spec.rules[0].actions[0]", "exactly one source"},
+		},
+		{
+			name: "Resource kind not supported",
+			image: newImage(validTriggers, v1alpha1.ImageRule{
+				Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{
+					Type: "patch-resource",
+					Data: v1alpha1.ValueOrValueFrom{ValueFrom: &v1alpha1.ValueFromSource{
+						ResourceRef: &v1alpha1.ResourceFieldSelector{Kind: "Deployment", Name: "demo"},
+					}},
+				}},
+			}),
+			wantErr: []string{"spec.rules[0].actions[0]", "resource kind not supported"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := validator.ValidateCreate(ctx, tt.image)
+			assert.Len(t, warnings, tt.wantWarnings)
+
+			if len(tt.wantErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			for _, msg := range tt.wantErr {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestImageValidator_ValidateUpdate(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "default"
+
+	fakeClient := fakekubeclient.NewFakeKubeClient().WithDynamicReactor("get", "alertconfigs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewServiceUnavailable("etcd is unavailable")
+	})
+	validator := &controller.ImageValidator{KubeAPIClient: fakeClient}
+
+	newImage := func(rules ...v1alpha1.ImageRule) *v1alpha1.Image {
+		return &v1alpha1.Image{
+			ObjectMeta: v1.ObjectMeta{Name: "demo", Namespace: namespace},
+			Spec: v1alpha1.ImageSpec{
+				Image:    "ghcr.io/orange-cloudavenue/demo",
+				BaseTag:  "v1.0.0",
+				Triggers: []v1alpha1.ImageTrigger{{Type: "crontab", Value: "0 */5 * * * *"}},
+				Rules:    rules,
+			},
+		}
+	}
+
+	invalidRule := v1alpha1.ImageRule{Name: "regex", Type: "regex", Value: "^(v1", Actions: []v1alpha1.ImageAction{{Type: "apply"}}}
+	alertRule := v1alpha1.ImageRule{Name: "patch", Type: "semver-patch", Actions: []v1alpha1.ImageAction{{
+		Type: "alert-slack",
+		Data: v1alpha1.ValueOrValueFrom{ValueFrom: &v1alpha1.ValueFromSource{AlertConfigRef: &corev1.LocalObjectReference{Name: "slack"}}},
+	}}}
+
+	tests := []struct {
+		name         string
+		oldImage     *v1alpha1.Image
+		newImage     *v1alpha1.Image
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name:     "Spec unchanged",
+			oldImage: newImage(invalidRule),
+			newImage: func() *v1alpha1.Image {
+				image := newImage(invalidRule)
+				image.SetAnnotations(map[string]string{"kimup.cloudavenue.io/action": "refresh"})
+				return image
+			}(),
+		},
+		{
+			name:     "Spec changed",
+			oldImage: newImage(),
+			newImage: newImage(invalidRule),
+			wantErr:  true,
+		},
+		{
+			name:         "AlertConfig not read",
+			oldImage:     newImage(),
+			newImage:     newImage(alertRule),
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := validator.ValidateUpdate(ctx, tt.oldImage, tt.newImage)
+			assert.Len(t, warnings, tt.wantWarnings)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/controller"
+)
+
+func TestKimupValidator_ValidateCreate(t *testing.T) {
+	validator := &controller.KimupValidator{}
+
+	newKimup := func(extra v1alpha1.KimupExtraSpec) *v1alpha1.Kimup {
+		return &v1alpha1.Kimup{
+			ObjectMeta: v1.ObjectMeta{Name: "kimup", Namespace: "kimup-operator"},
+			Spec: v1alpha1.KimupSpec{
+				Name:           "demo",
+				KimupExtraSpec: extra,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		kimup   *v1alpha1.Kimup
+		wantErr []string
+	}{
+		{
+			name:  "API disabled",
+			kimup: newKimup(v1alpha1.KimupExtraSpec{API: v1alpha1.KimupAPISpec{}}),
+		},
+		{
+			name:  "API with TLS",
+			kimup: newKimup(v1alpha1.KimupExtraSpec{API: v1alpha1.KimupAPISpec{Enabled: true, TLSSecretName: "kimup-api-tls"}}),
+		},
+		{
+			name:  "API over plain HTTP allowed",
+			kimup: newKimup(v1alpha1.KimupExtraSpec{API: v1alpha1.KimupAPISpec{Enabled: true, Insecure: true}}),
+		},
+		{
+			name:    "API without TLS",
+			kimup:   newKimup(v1alpha1.KimupExtraSpec{API: v1alpha1.KimupAPISpec{Enabled: true}}),
+			wantErr: []string{"spec.api.tlsSecretName"},
+		},
+		{
+			name: "Events with token",
+			kimup: newKimup(v1alpha1.KimupExtraSpec{Events: v1alpha1.KimupEventsSpec{HTTP: v1alpha1.KimupEventsHTTPSpec{
+				KimupProbeSpec: v1alpha1.KimupProbeSpec{Enabled: true},
+				TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "kimup-events"}, Key: "token"},
+			}}}),
+		},
+		{
+			name: "Events without token allowed",
+			kimup: newKimup(v1alpha1.KimupExtraSpec{Events: v1alpha1.KimupEventsSpec{HTTP: v1alpha1.KimupEventsHTTPSpec{
+				KimupProbeSpec: v1alpha1.KimupProbeSpec{Enabled: true},
+				Insecure:       true,
+			}}}),
+		},
+		{
+			name: "Events without token",
+			kimup: newKimup(v1alpha1.KimupExtraSpec{Events: v1alpha1.KimupEventsSpec{HTTP: v1alpha1.KimupEventsHTTPSpec{
+				KimupProbeSpec: v1alpha1.KimupProbeSpec{Enabled: true},
+			}}}),
+			wantErr: []string{"spec.events.http.tokenSecretRef"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ValidateCreate(context.TODO(), tt.kimup)
+
+			if len(tt.wantErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			for _, msg := range tt.wantErr {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
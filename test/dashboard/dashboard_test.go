@@ -0,0 +1,37 @@
+package dashboard_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/dashboard"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{path: "/", status: http.StatusOK, contentType: "text/html; charset=utf-8"},
+		{path: "/app.js", status: http.StatusOK, contentType: "text/javascript; charset=utf-8"},
+		{path: "/style.css", status: http.StatusOK, contentType: "text/css; charset=utf-8"},
+		{path: "/unknown", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			dashboard.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "default-src 'self'")
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gitops_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/gitops"
+)
+
+func TestUpdateFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		format          gitops.FileFormat
+		key             string
+		expectedChanged bool
+		expectedContent string
+		expectedError   error
+	}{
+		{
+			name: "Kustomize existing image",
+			content: `images:
+  - name: ghcr.io/orange-cloudavenue/kimup
+    newTag: v1.0.0
+`,
+			format:          gitops.FormatKustomize,
+			expectedChanged: true,
+			expectedContent: `images:
+  - name: ghcr.io/orange-cloudavenue/kimup
+    newTag: v1.1.0
+`,
+		},
+		{
+			name: "Kustomize new image",
+			content: `resources:
+  - deployment.yaml
+`,
+			format:          gitops.FormatKustomize,
+			expectedChanged: true,
+			expectedContent: `resources:
+  - deployment.yaml
+images:
+  - name: ghcr.io/orange-cloudavenue/kimup
+    newTag: v1.1.0
+`,
+		},
+		{
+			name: "Helm default key",
+			content: `image:
+  # The tag of the image
+  tag: v1.0.0
+replicas: 1
+`,
+			format:          gitops.FormatHelm,
+			expectedChanged: true,
+			expectedContent: `image:
+  # The tag of the image
+  tag: v1.1.0
+replicas: 1
+`,
+		},
+		{
+			name: "YAML already up to date",
+			content: `app:
+  version: v1.1.0
+`,
+			format:          gitops.FormatYAML,
+			key:             "app.version",
+			expectedChanged: false,
+			expectedContent: `app:
+  version: v1.1.0
+`,
+		},
+		{
+			name: "YAML key not found",
+			content: `app:
+  name: kimup
+`,
+			format:        gitops.FormatYAML,
+			key:           "app.version",
+			expectedError: gitops.ErrKeyNotFound,
+		},
+		{
+			name:          "YAML without key",
+			content:       "app: {}\n",
+			format:        gitops.FormatYAML,
+			expectedError: gitops.ErrInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, changed, err := gitops.UpdateFile([]byte(tt.content), tt.format, tt.key, "ghcr.io/orange-cloudavenue/kimup", "v1.1.0")
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedChanged, changed)
+			assert.Equal(t, tt.expectedContent, string(content))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/metrics"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/models"
+)
+
+func TestLabels(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, metrics.SetLabels(models.MetricsDefaultLabels)) })
+
+	assert.Equal(t, "default", metrics.Label(metrics.LabelNamespace, "default"))
+	assert.Equal(t, "demo", metrics.Label(metrics.LabelImage, "demo"))
+
+	require.NoError(t, metrics.SetLabels("namespace, action"))
+	assert.Equal(t, "default", metrics.Label(metrics.LabelNamespace, "default"))
+	assert.Empty(t, metrics.Label(metrics.LabelImage, "demo"))
+	assert.Empty(t, metrics.Label(metrics.LabelRule, "semver"))
+	assert.Equal(t, "apply", metrics.Label(metrics.LabelAction, "apply"))
+
+	require.Error(t, metrics.SetLabels("namespace,tag"))
+}
+
+func TestObserveImage(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, metrics.SetLabels(models.MetricsDefaultLabels)) })
+
+	images := metrics.Images()
+	since := time.Unix(1700000000, 0)
+	success := time.Unix(1700000600, 0)
+
+	metrics.ObserveImage("default", "demo", 2, &since, &success)
+	assert.InDelta(t, 2, testutil.ToFloat64(images.VersionsBehind.WithLabelValues("default", "demo")), 0)
+	assert.InDelta(t, 1700000000, testutil.ToFloat64(images.NewerTagAvailableTimestampSeconds.WithLabelValues("default", "demo")), 0)
+	assert.InDelta(t, 1700000600, testutil.ToFloat64(images.LastSuccessfulRefreshTimestampSeconds.WithLabelValues("default", "demo")), 0)
+
+	// The timestamp of the newer tag is removed when the image is up to date
+	metrics.ObserveImage("default", "demo", 0, nil, &success)
+	assert.Equal(t, 0, testutil.CollectAndCount(images.NewerTagAvailableTimestampSeconds))
+	assert.Equal(t, 1, testutil.CollectAndCount(images.VersionsBehind))
+
+	metrics.Events().RefreshTotal.WithLabelValues("default", "demo", metrics.ResultSucceeded).Inc()
+	metrics.Events().RefreshTotal.WithLabelValues("default", "other", metrics.ResultFailed).Inc()
+
+	// The series of the deleted image are removed
+	metrics.Forget("default", "demo")
+	assert.Equal(t, 0, testutil.CollectAndCount(images.VersionsBehind))
+	assert.Equal(t, 0, testutil.CollectAndCount(images.LastSuccessfulRefreshTimestampSeconds))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.Events().RefreshTotal))
+
+	// The lag is not observed without the image label
+	require.NoError(t, metrics.SetLabels("namespace"))
+	metrics.ObserveImage("default", "demo", 2, &since, &success)
+	assert.Equal(t, 0, testutil.CollectAndCount(images.VersionsBehind))
+}
@@ -10,6 +10,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	dFake "k8s.io/client-go/dynamic/fake"
 	kFake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
 	"github.com/orange-cloudavenue/kube-image-updater/internal/kubeclient"
@@ -20,6 +21,9 @@ var _ kubeclient.Interface = &FakeKubeClient{}
 type FakeKubeClient struct {
 	mock.Mock
 	kubeclient.InterfaceKubernetes
+	// dynamic is the dynamic client shared by the calls to DynamicResource
+	// when the fake is created with objects.
+	dynamic dynamic.Interface
 }
 
 func NewFakeKubeClient() *FakeKubeClient {
@@ -30,7 +34,27 @@ func NewFakeKubeClient() *FakeKubeClient {
 	}
 }
 
+// WithDynamicObjects returns the fake with a dynamic client containing the objects.
+// The objects are shared by all the calls to DynamicResource.
+func (f *FakeKubeClient) WithDynamicObjects(objects ...runtime.Object) *FakeKubeClient {
+	f.dynamic = dFake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+	return f
+}
+
+// WithDynamicReactor returns the fake with a reaction to the calls of the dynamic client,
+// e.g. to return an error for a resource.
+func (f *FakeKubeClient) WithDynamicReactor(verb, resource string, reaction k8stesting.ReactionFunc) *FakeKubeClient {
+	if f.dynamic == nil {
+		f.dynamic = dFake.NewSimpleDynamicClient(runtime.NewScheme())
+	}
+	f.dynamic.(*dFake.FakeDynamicClient).PrependReactor(verb, resource, reaction)
+	return f
+}
+
 func (f *FakeKubeClient) DynamicResource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	if f.dynamic != nil {
+		return f.dynamic.Resource(resource)
+	}
 	return dFake.NewSimpleDynamicClient(runtime.NewScheme()).Resource(resource)
 }
 
@@ -56,6 +80,14 @@ func (f *FakeKubeClient) Alert() *kubeclient.AlertObj {
 	return kubeclient.NewAlert(f)
 }
 
+func (f *FakeKubeClient) GitConfig() *kubeclient.GitConfigObj {
+	return kubeclient.NewGitConfig(f)
+}
+
+func (f *FakeKubeClient) Resource() *kubeclient.ResourceObj {
+	return kubeclient.NewResource(f)
+}
+
 func (f *FakeKubeClient) Mutator() *kubeclient.MutatorObj {
 	return kubeclient.NewMutator(f)
 }
@@ -0,0 +1,268 @@
+package restapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/restapi"
+)
+
+// The token "admin" can do everything, the token "reader" can only get and list the images
+// of the namespace default and the other tokens are invalid.
+func newKubernetes() *kubefake.Clientset {
+	k := kubefake.NewSimpleClientset()
+
+	k.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch review.Spec.Token {
+		case "admin", "reader":
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: review.Spec.Token}
+		}
+		return true, review, nil
+	})
+
+	k.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "admin" ||
+			(review.Spec.User == "reader" && attributes.Namespace == "default" && (attributes.Verb == "get" || attributes.Verb == "list"))
+		return true, review, nil
+	})
+
+	return k
+}
+
+func newServer(t *testing.T) (http.Handler, client.Client) {
+	t.Helper()
+
+	return newServerWithReviews(t, newKubernetes(), time.Minute)
+}
+
+// newServerWithReviews returns the server reviewing the tokens and the accesses with k,
+// the results of the reviews are cached for ttl.
+func newServerWithReviews(t *testing.T, k kubernetes.Interface, ttl time.Duration) (http.Handler, client.Client) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "demo"},
+			Spec:       v1alpha1.ImageSpec{Image: "ghcr.io/traefik/whoami"},
+			Status: v1alpha1.ImageStatus{
+				Tag:       "v1.9.0",
+				LatestTag: "v1.10.0",
+				Rules: []v1alpha1.ImageStatusRule{
+					{Name: "minor", Result: v1alpha1.ImageRuleResultMatched, NewTag: "v1.10.0"},
+					{Name: "patch", Result: v1alpha1.ImageRuleResultNotMatched},
+				},
+				History: []v1alpha1.ImageStatusHistory{{Tag: "v1.9.0"}, {Tag: "v1.8.0"}},
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ImageConditionReady, Status: metav1.ConditionFalse, Reason: "Error", Message: "rule minor: boom"},
+				},
+			},
+		},
+		&v1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "nginx"},
+			Spec:       v1alpha1.ImageSpec{Image: "nginx"},
+		},
+	).Build()
+
+	next := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	r := chi.NewRouter()
+	restapi.New(c, k).WithReviewTTL(ttl).WithSchedule(func(namespace, name string) (time.Time, bool) {
+		if name == "nginx" {
+			return next, true
+		}
+		return next.Add(time.Hour), namespace == "default"
+	}).Routes(r)
+
+	return r, c
+}
+
+func do(handler http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	handler, _ := newServer(t)
+
+	assert.Equal(t, http.StatusUnauthorized, do(handler, http.MethodGet, "/images", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(handler, http.MethodGet, "/images", "invalid").Code)
+
+	// reader can not list the other namespaces nor refresh
+	assert.Equal(t, http.StatusForbidden, do(handler, http.MethodGet, "/images?namespace=other", "reader").Code)
+	assert.Equal(t, http.StatusForbidden, do(handler, http.MethodPost, "/images/default/demo/refresh", "reader").Code)
+	assert.Equal(t, http.StatusOK, do(handler, http.MethodGet, "/images?namespace=default", "reader").Code)
+}
+
+func TestReviewCache(t *testing.T) {
+	// reviews returns the number of TokenReviews and SubjectAccessReviews created
+	reviews := func(k *kubefake.Clientset) (tokens, accesses int) {
+		for _, action := range k.Actions() {
+			switch action.GetResource().Resource {
+			case "tokenreviews":
+				tokens++
+			case "subjectaccessreviews":
+				accesses++
+			}
+		}
+		return tokens, accesses
+	}
+
+	t.Run("Cached", func(t *testing.T) {
+		k := newKubernetes()
+		handler, _ := newServerWithReviews(t, k, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, do(handler, http.MethodGet, "/images/default/demo", "reader").Code)
+			assert.Equal(t, http.StatusForbidden, do(handler, http.MethodPost, "/images/default/demo/refresh", "reader").Code)
+			assert.Equal(t, http.StatusUnauthorized, do(handler, http.MethodGet, "/images", "invalid").Code)
+		}
+		tokens, accesses := reviews(k)
+		assert.Equal(t, 2, tokens)
+		assert.Equal(t, 2, accesses)
+
+		// Without namespace, the access is reviewed once for all the namespaces and once per namespace
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, do(handler, http.MethodGet, "/images", "reader").Code)
+		}
+		tokens, accesses = reviews(k)
+		assert.Equal(t, 2, tokens)
+		assert.Equal(t, 2+3, accesses)
+
+		// The reviews are per user
+		assert.Equal(t, http.StatusAccepted, do(handler, http.MethodPost, "/images/default/demo/refresh", "admin").Code)
+		tokens, accesses = reviews(k)
+		assert.Equal(t, 3, tokens)
+		assert.Equal(t, 6, accesses)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		k := newKubernetes()
+		handler, _ := newServerWithReviews(t, k, time.Millisecond)
+
+		assert.Equal(t, http.StatusOK, do(handler, http.MethodGet, "/images/default/demo", "reader").Code)
+		time.Sleep(5 * time.Millisecond)
+		assert.Equal(t, http.StatusOK, do(handler, http.MethodGet, "/images/default/demo", "reader").Code)
+		tokens, accesses := reviews(k)
+		assert.Equal(t, 2, tokens)
+		assert.Equal(t, 2, accesses)
+	})
+}
+
+func TestListImagesNamespaceScoped(t *testing.T) {
+	handler, _ := newServer(t)
+
+	// Without namespace, reader gets the Images of the namespaces it can list
+	rec := do(handler, http.MethodGet, "/images", "reader")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var images []restapi.Image
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &images))
+	require.Len(t, images, 1)
+	assert.Equal(t, "default", images[0].Namespace)
+
+	rec = do(handler, http.MethodGet, "/schedule", "reader")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var schedule []restapi.ScheduledRefresh
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedule))
+	require.Len(t, schedule, 1)
+	assert.Equal(t, "demo", schedule[0].Name)
+}
+
+func TestListImages(t *testing.T) {
+	handler, _ := newServer(t)
+
+	rec := do(handler, http.MethodGet, "/images", "admin")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var images []restapi.Image
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &images))
+	require.Len(t, images, 2)
+
+	demo := images[0]
+	assert.Equal(t, "demo", demo.Name)
+	assert.Equal(t, "v1.9.0", demo.Tag)
+	assert.Equal(t, "ghcr.io", demo.Registry)
+	assert.Equal(t, "docker.io", images[1].Registry)
+	assert.Equal(t, "rule minor: boom", demo.Error)
+	assert.Equal(t, "v1.10.0", demo.LatestTag)
+	assert.Equal(t, []restapi.PendingTag{{Rule: "minor", Tag: "v1.10.0"}}, demo.Pending)
+	assert.NotNil(t, demo.NextSync)
+
+	rec = do(handler, http.MethodGet, "/images/default/unknown", "admin")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHistory(t *testing.T) {
+	handler, _ := newServer(t)
+
+	rec := do(handler, http.MethodGet, "/images/default/demo/history", "reader")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var history []v1alpha1.ImageStatusHistory
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history, 2)
+	assert.Equal(t, "v1.9.0", history[0].Tag)
+}
+
+func TestRefreshImage(t *testing.T) {
+	handler, c := newServer(t)
+
+	rec := do(handler, http.MethodPost, "/images/default/demo/refresh", "admin")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var image v1alpha1.Image
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "demo"}, &image))
+	assert.Equal(t, string(annotations.ActionRefresh), image.Annotations[string(annotations.KeyAction)])
+}
+
+func TestApproveImage(t *testing.T) {
+	handler, _ := newServer(t)
+
+	assert.Equal(t, http.StatusNotImplemented, do(handler, http.MethodPost, "/images/default/demo/approve", "admin").Code)
+}
+
+func TestSchedule(t *testing.T) {
+	handler, _ := newServer(t)
+
+	rec := do(handler, http.MethodGet, "/schedule", "admin")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var schedule []restapi.ScheduledRefresh
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedule))
+	require.Len(t, schedule, 2)
+	assert.Equal(t, "nginx", schedule[0].Name)
+	assert.Equal(t, "demo", schedule[1].Name)
+}
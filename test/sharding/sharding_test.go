@@ -0,0 +1,107 @@
+package sharding_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+)
+
+func TestOwner(t *testing.T) {
+	members := []string{"kimup-0", "kimup-1", "kimup-2"}
+
+	assert.Empty(t, sharding.Owner(nil, "default/demo"))
+	assert.Equal(t, "kimup-0", sharding.Owner([]string{"kimup-0"}, "default/demo"))
+
+	owned := map[string]int{}
+	owners := map[string]string{}
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("ns-%d/image-%d", i%7, i)
+		owner := sharding.Owner(members, key)
+		owned[owner]++
+		owners[key] = owner
+
+		// The owner does not depend on the order of the members
+		assert.Equal(t, owner, sharding.Owner([]string{"kimup-2", "kimup-0", "kimup-1"}, key))
+	}
+
+	// The keys are distributed across all the members
+	for _, m := range members {
+		assert.Greater(t, owned[m], 50, m)
+	}
+
+	// Only the keys of the removed member move
+	for key, owner := range owners {
+		if owner != "kimup-2" {
+			assert.Equal(t, owner, sharding.Owner(members[:2], key), key)
+		}
+	}
+}
+
+func TestSharder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	client := fake.NewClientset()
+
+	// Without membership the replica owns all the keys
+	var disabled *sharding.Sharder
+	assert.True(t, disabled.IsOwner("default/demo"))
+
+	a := sharding.New(client, "kimup-operator", "kimup-a", sharding.WithRenewInterval(time.Hour))
+	require.NoError(t, a.Start(ctx))
+	assert.Equal(t, []string{"kimup-a"}, a.Members())
+	assert.True(t, a.IsOwner("default/demo"))
+
+	changed := make(chan []string, 1)
+	b := sharding.New(client, "kimup-operator", "kimup-b", sharding.WithRenewInterval(time.Hour))
+	b.OnChange(func(members []string) { changed <- members })
+	require.NoError(t, b.Start(ctx))
+	assert.Equal(t, []string{"kimup-a", "kimup-b"}, <-changed)
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("default/image-%d", i)
+		assert.NotEqual(t, b.IsOwner(key), sharding.Owner(b.Members(), key) == "kimup-a", key)
+	}
+
+	// The expired leases are not members
+	lease, err := client.CoordinationV1().Leases("kimup-operator").Get(ctx, sharding.DefaultGroup+"-kimup-a", metav1.GetOptions{})
+	require.NoError(t, err)
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now().Add(-time.Hour)}
+	_, err = client.CoordinationV1().Leases("kimup-operator").Update(ctx, lease, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	c := sharding.New(client, "kimup-operator", "kimup-c", sharding.WithRenewInterval(time.Hour))
+	require.NoError(t, c.Start(ctx))
+	assert.Equal(t, []string{"kimup-b", "kimup-c"}, c.Members())
+}
+
+func TestSharder_LeaseNotRenewed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	client := fake.NewClientset()
+
+	a := sharding.New(client, "kimup-operator", "kimup-a", sharding.WithLeaseDuration(time.Second), sharding.WithRenewInterval(100*time.Millisecond))
+	require.NoError(t, a.Start(ctx))
+	assert.True(t, a.IsOwner("default/demo"))
+
+	// The replica keeps its keys while its Lease is renewed
+	time.Sleep(1500 * time.Millisecond)
+	assert.True(t, a.IsOwner("default/demo"))
+
+	// The other members own the keys once the Lease has expired
+	client.PrependReactor("update", "leases", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("api server unavailable")
+	})
+	assert.Eventually(t, func() bool { return !a.IsOwner("default/demo") }, 3*time.Second, 50*time.Millisecond)
+}
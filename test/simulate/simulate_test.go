@@ -0,0 +1,69 @@
+package simulate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/rules"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/simulate"
+)
+
+func TestSimulate(t *testing.T) {
+	image := v1alpha1.Image{
+		Spec: v1alpha1.ImageSpec{
+			Image:   "ghcr.io/traefik/whoami",
+			BaseTag: "v1.9.0",
+			Rules: []v1alpha1.ImageRule{
+				{Name: "patch", Type: rules.SemverPatch, Actions: []v1alpha1.ImageAction{{Type: "apply"}}},
+				{Name: "major", Type: rules.SemverMajor, Actions: []v1alpha1.ImageAction{{Type: "alert-slack"}}},
+				{Name: "regex", Type: rules.Regex},
+			},
+		},
+	}
+	tags := []string{"v1.9.0", "v1.9.1", "v1.10.0", "v2.0.0", "latest"}
+
+	result := simulate.Simulate(image, tags)
+	assert.Equal(t, "v1.9.0", result.Tag)
+	assert.Equal(t, len(tags), result.Tags)
+	assert.Equal(t, "v1.9.1", result.NewTag)
+	require.Len(t, result.Rules, 3)
+	assert.True(t, result.HasErrors())
+
+	// The apply action of the first rule changes the current tag of the following rules
+	patch, major, regex := result.Rules[0], result.Rules[1], result.Rules[2]
+	assert.Equal(t, v1alpha1.ImageRuleResultMatched, patch.Result)
+	assert.Equal(t, "v1.9.1", patch.NewTag)
+	assert.Equal(t, []string{"apply"}, patch.Actions)
+	assert.Contains(t, patch.Rejected, simulate.Rejection{Tag: "latest", Reason: "not a semantic version"})
+	assert.Contains(t, patch.Rejected, simulate.Rejection{Tag: "v1.10.0", Reason: "does not satisfy >=1.9.1 <1.10.0"})
+	assert.Len(t, patch.Rejected, len(tags)-1)
+
+	assert.Equal(t, "v1.9.1", major.Tag)
+	assert.Equal(t, v1alpha1.ImageRuleResultMatched, major.Result)
+	assert.Equal(t, "v2.0.0", major.NewTag)
+
+	assert.Equal(t, v1alpha1.ImageRuleResultError, regex.Result)
+	assert.NotEmpty(t, regex.Error)
+
+	// The tags of the caller are not sorted by the rules
+	assert.Equal(t, []string{"v1.9.0", "v1.9.1", "v1.10.0", "v2.0.0", "latest"}, tags)
+}
+
+func TestSimulate_StatusTag(t *testing.T) {
+	image := v1alpha1.Image{
+		Spec: v1alpha1.ImageSpec{
+			BaseTag: "v1.0.0",
+			Rules:   []v1alpha1.ImageRule{{Name: "minor", Type: rules.SemverMinor}},
+		},
+		Status: v1alpha1.ImageStatus{Tag: "v1.1.0"},
+	}
+
+	result := simulate.Simulate(image, []string{"v1.0.0", "v1.1.0"})
+	assert.Equal(t, "v1.1.0", result.Tag)
+	assert.Empty(t, result.NewTag)
+	assert.False(t, result.HasErrors())
+	assert.Equal(t, v1alpha1.ImageRuleResultNotMatched, result.Rules[0].Result)
+}
@@ -0,0 +1,133 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gookit/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/registry"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/tracing"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+// record registers a tracer provider recording the ended spans.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func TestEnd(t *testing.T) {
+	recorder := record(t)
+
+	_, span := tracing.Start(context.Background(), "ok", tracing.Image("default", "demo")...)
+	tracing.End(span, nil)
+	_, span = tracing.Start(context.Background(), "failed")
+	tracing.End(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), tracing.Image("default", "demo")[1])
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+}
+
+func TestTraceContextThroughEvents(t *testing.T) {
+	recorder := record(t)
+
+	const name triggers.EventName = "test.tracing"
+	received := make(chan context.Context, 1)
+	event.On(name.String(), event.ListenerFunc(func(e event.Event) error {
+		received <- triggers.TraceContext(context.Background(), e)
+		return nil
+	}))
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+	_, err := triggers.TriggerWithTag(ctx, name, "default", "demo", "v1.0.0")
+	require.NoError(t, err)
+	parent.End()
+
+	var listenerCtx context.Context
+	select {
+	case listenerCtx = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event was not received")
+	}
+
+	_, child := tracing.Start(listenerCtx, "child")
+	child.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+}
+
+func TestTraceContextWithoutSpan(t *testing.T) {
+	ctx := triggers.TraceContext(context.Background(), event.NewBasic("test", event.M{}))
+	assert.Equal(t, context.Background(), ctx)
+}
+
+func TestRegistrySpan(t *testing.T) {
+	recorder := record(t)
+
+	_, err := registry.New(context.Background(), "", registry.Settings{})
+	require.ErrorIs(t, err, registry.ErrRepoIsEmpty)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "registry.New", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestInit(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		tracing.Exporter, tracing.File = "", ""
+	})
+
+	// Disabled
+	shutdown, err := tracing.Init(context.Background(), "kimup")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	tracing.Exporter = "unknown"
+	_, err = tracing.Init(context.Background(), "kimup")
+	require.Error(t, err)
+
+	tracing.Exporter = tracing.ExporterFile
+	_, err = tracing.Init(context.Background(), "kimup")
+	require.Error(t, err)
+
+	tracing.File = filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err = tracing.Init(context.Background(), "kimup")
+	require.NoError(t, err)
+
+	_, span := tracing.Start(context.Background(), "RefreshImage", tracing.Image("default", "demo")...)
+	tracing.End(span, nil)
+	require.NoError(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(tracing.File)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"Name":"RefreshImage"`)
+	assert.Contains(t, string(content), `"Value":"kimup"`)
+}
@@ -0,0 +1,187 @@
+package triggers_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/crontab"
+)
+
+func TestMissedRuns(t *testing.T) {
+	from := time.Date(2024, 10, 1, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		crontab string
+		to      time.Time
+		limit   int
+		want    int
+		wantErr bool
+	}{
+		{name: "no missed run", crontab: "0 0 * * * *", to: from.Add(20 * time.Minute), limit: 10, want: 0},
+		{name: "missed runs", crontab: "0 0 * * * *", to: from.Add(3 * time.Hour), limit: 10, want: 3},
+		{name: "limit", crontab: "0 * * * * *", to: from.Add(3 * time.Hour), limit: 10, want: 10},
+		{name: "invalid crontab", crontab: "invalid", to: from.Add(time.Hour), limit: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := crontab.MissedRuns(tt.crontab, "default/demo", from, tt.to, tt.limit)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, runs, tt.want)
+			for _, r := range runs {
+				assert.True(t, r.After(from) && r.Before(tt.to), r)
+			}
+		})
+	}
+}
+
+func TestSyncCronTabs(t *testing.T) {
+	first := crontab.BuildTriggerKey("sync", "demo", 0)
+	second := crontab.BuildTriggerKey("sync", "demo", 1)
+	defer func() { _ = crontab.RemoveImageJobs("sync", "demo") }()
+
+	exists := func(key string) bool {
+		t.Helper()
+		ok, err := crontab.IsExistingJob(key)
+		require.NoError(t, err)
+		return ok
+	}
+
+	changed, err := crontab.SyncCronTabs("sync", "demo", []string{"0 0 * * * *", "0 15 * * * *"})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, exists(first))
+	assert.True(t, exists(second))
+
+	// Same expressions, the jobs are kept
+	changed, err = crontab.SyncCronTabs("sync", "demo", []string{"0 0 * * * *", "0 15 * * * *"})
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// New expression, the job is replaced
+	changed, err = crontab.SyncCronTabs("sync", "demo", []string{"0 30 * * * *", "0 15 * * * *"})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	next, ok := crontab.NextRunTime(first)
+	require.True(t, ok)
+	assert.Equal(t, 30, next.Minute())
+
+	next, ok = crontab.NextImageRunTime("sync", "demo")
+	require.True(t, ok)
+	assert.Contains(t, []int{15, 30}, next.Minute())
+
+	// Invalid expression, the previous job is kept
+	_, err = crontab.SyncCronTabs("sync", "demo", []string{"invalid", "0 15 * * * *"})
+	require.Error(t, err)
+	assert.True(t, exists(first))
+
+	// Removed trigger, the job is removed
+	changed, err = crontab.SyncCronTabs("sync", "demo", []string{"0 30 * * * *"})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, exists(first))
+	assert.False(t, exists(second))
+
+	// The jobs of an image with a colliding name are not removed
+	defer func() { _ = crontab.RemoveImageJobs("sync-demo", "x") }()
+	_, err = crontab.SyncCronTabs("sync-demo", "x", []string{"0 0 * * * *"})
+	require.NoError(t, err)
+
+	// No trigger, the jobs are removed
+	changed, err = crontab.SyncCronTabs("sync", "demo", nil)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.False(t, exists(first))
+	assert.True(t, exists(crontab.BuildTriggerKey("sync-demo", "x", 0)))
+}
+
+func TestBuildKey(t *testing.T) {
+	assert.NotEqual(t, crontab.BuildKey("a-b", "c"), crontab.BuildKey("a", "b-c"))
+	assert.NotEqual(t, crontab.BuildTriggerKey("a-b", "c", 0), crontab.BuildTriggerKey("a", "b-c", 0))
+	assert.NotEqual(t, crontab.BuildTriggerKey("digest", "a", 0), crontab.BuildDigestKey("a", "0"))
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		crontab string
+		check   func(t *testing.T, fields []string)
+		wantErr bool
+	}{
+		{
+			name:    "no hash",
+			crontab: "0 0 * * * *",
+			check: func(t *testing.T, fields []string) {
+				assert.Equal(t, []string{"0", "0", "*", "*", "*", "*"}, fields)
+			},
+		},
+		{
+			name:    "hash",
+			crontab: "H H * * * *",
+			check: func(t *testing.T, fields []string) {
+				for _, f := range fields[:2] {
+					v, err := strconv.Atoi(f)
+					require.NoError(t, err)
+					assert.True(t, v >= 0 && v <= 59)
+				}
+			},
+		},
+		{
+			name:    "hash with range",
+			crontab: "0 0 H(8-10) * * *",
+			check: func(t *testing.T, fields []string) {
+				v, err := strconv.Atoi(fields[2])
+				require.NoError(t, err)
+				assert.True(t, v >= 8 && v <= 10)
+			},
+		},
+		{
+			name:    "hash with step",
+			crontab: "0 H/15 * * * *",
+			check: func(t *testing.T, fields []string) {
+				assert.Regexp(t, `^\d+-59/15$`, fields[1])
+			},
+		},
+		{name: "invalid range", crontab: "0 0 H(20-30) * * *", wantErr: true},
+		{name: "invalid step", crontab: "0 H/0 * * * *", wantErr: true},
+		{name: "invalid field", crontab: "0 Hx * * * *", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := crontab.Expand(tt.crontab, "default/demo")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, crontab.Validate(expr))
+			tt.check(t, strings.Fields(expr))
+
+			// The expansion is stable for the key
+			again, err := crontab.Expand(tt.crontab, "default/demo")
+			require.NoError(t, err)
+			assert.Equal(t, expr, again)
+		})
+	}
+
+	// The keys are spread across the interval
+	values := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		expr, err := crontab.Expand("H H * * * *", fmt.Sprintf("default/image-%d", i))
+		require.NoError(t, err)
+		values[expr] = true
+	}
+	assert.Greater(t, len(values), 40)
+}
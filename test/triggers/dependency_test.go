@@ -0,0 +1,34 @@
+package triggers_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/dependency"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	g := dependency.New()
+	g.Set("default", "sidecar", []string{"app"})
+	g.Set("default", "proxy", []string{"app", "db"})
+	g.Set("other", "sidecar", []string{"app"})
+
+	// The first observation only records the tag
+	assert.Empty(t, g.Observe("default", "app", "v1.0.0"))
+	assert.Empty(t, g.Observe("default", "app", "v1.0.0"))
+
+	assert.Equal(t, []string{"proxy", "sidecar"}, g.Observe("default", "app", "v1.1.0"))
+
+	g.Set("default", "proxy", nil)
+	assert.Equal(t, []string{"sidecar"}, g.Observe("default", "app", "v1.2.0"))
+
+	g.Remove("default", "sidecar")
+	assert.Empty(t, g.Observe("default", "app", "v1.3.0"))
+
+	// The removed image is observed again from scratch
+	g.Remove("default", "app")
+	g.Set("default", "sidecar", []string{"app"})
+	assert.Empty(t, g.Observe("default", "app", "v2.0.0"))
+	assert.Equal(t, []string{"sidecar"}, g.Observe("default", "app", "v2.1.0"))
+}
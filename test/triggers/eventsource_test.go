@@ -0,0 +1,319 @@
+package triggers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/orange-cloudavenue/kube-image-updater/api/v1alpha1"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/annotations"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/sharding"
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/eventsource"
+)
+
+func TestParsePushes(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []eventsource.Push
+		wantErr bool
+	}{
+		{
+			name:    "distribution",
+			payload: `{"events":[{"action":"push","target":{"repository":"app","tag":"v1.2.0"},"request":{"host":"registry.example.com"}},{"action":"pull","target":{"repository":"app","tag":"v1.1.0"},"request":{"host":"registry.example.com"}}]}`,
+			want:    []eventsource.Push{{Repository: "registry.example.com/app", Tag: "v1.2.0"}},
+		},
+		{
+			name:    "harbor webhook",
+			payload: `{"type":"PUSH_ARTIFACT","event_data":{"resources":[{"tag":"v1.2.0","resource_url":"harbor.example.com/library/app:v1.2.0"}]}}`,
+			want:    []eventsource.Push{{Repository: "harbor.example.com/library/app", Tag: "v1.2.0"}},
+		},
+		{
+			name:    "simple",
+			payload: `{"repository":"nginx","tag":"1.27"}`,
+			want:    []eventsource.Push{{Repository: "docker.io/library/nginx", Tag: "1.27"}},
+		},
+		{
+			name:    "cloudevent structured",
+			payload: `{"specversion":"1.0","type":"harbor.artifact.pushed","data":{"resources":[{"tag":"v2","resource_url":"harbor.example.com/library/app:v2"}]}}`,
+			want:    []eventsource.Push{{Repository: "harbor.example.com/library/app", Tag: "v2"}},
+		},
+		{
+			name:    "cloudevent base64",
+			payload: `{"specversion":"1.0","type":"push","data_base64":"eyJyZXBvc2l0b3J5IjoibmdpbngiLCJ0YWciOiIxLjI3In0="}`,
+			want:    []eventsource.Push{{Repository: "docker.io/library/nginx", Tag: "1.27"}},
+		},
+		{
+			name:    "cloudevent without data",
+			payload: `{"specversion":"1.0","type":"push"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown",
+			payload: `{"foo":"bar"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eventsource.ParsePushes([]byte(tt.payload))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, eventsource.ErrUnknownPayload)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type refreshed struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *refreshed) trigger(_ context.Context, namespace, name, tag string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, namespace+"/"+name+":"+tag)
+}
+
+func newTestDispatcher(r *refreshed) *eventsource.Dispatcher {
+	images := []v1alpha1.Image{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}, Spec: v1alpha1.ImageSpec{Image: "nginx"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "nginx"}, Spec: v1alpha1.ImageSpec{Image: "docker.io/library/nginx"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}, Spec: v1alpha1.ImageSpec{Image: "registry.example.com/app"}},
+	}
+
+	return eventsource.NewDispatcher(func(_ context.Context) ([]v1alpha1.Image, error) {
+		return images, nil
+	}).WithTrigger(r.trigger)
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	r := &refreshed{}
+	d := newTestDispatcher(r)
+
+	n, err := d.Dispatch(context.Background(), eventsource.Push{Repository: "docker.io/library/nginx", Tag: "1.27"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"default/nginx:1.27", "other/nginx:1.27"}, r.keys)
+
+	n, err = d.Dispatch(context.Background(), eventsource.Push{Repository: "registry.example.com/unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestDispatcher_Handler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		token       string
+		headers     map[string]string
+		body        string
+		wantStatus  int
+		want        []string
+	}{
+		{
+			name:        "binary",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			headers:     map[string]string{"Ce-Specversion": "1.0", "Ce-Type": "push"},
+			body:        `{"repository":"registry.example.com/app","tag":"v1"}`,
+			wantStatus:  http.StatusAccepted,
+			want:        []string{"default/app:v1"},
+		},
+		{
+			name:        "structured",
+			method:      http.MethodPost,
+			contentType: "application/cloudevents+json",
+			body:        `{"specversion":"1.0","type":"push","data":{"repository":"registry.example.com/app","tag":"v2"}}`,
+			wantStatus:  http.StatusAccepted,
+			want:        []string{"default/app:v2"},
+		},
+		{
+			name:        "batch",
+			method:      http.MethodPost,
+			contentType: "application/cloudevents-batch+json",
+			body:        `[{"specversion":"1.0","type":"push","data":{"repository":"registry.example.com/app","tag":"v3"}},{"specversion":"1.0","type":"push","data":{"repository":"registry.example.com/app","tag":"v4"}}]`,
+			wantStatus:  http.StatusAccepted,
+			want:        []string{"default/app:v3", "default/app:v4"},
+		},
+		{
+			name:        "unknown payload",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        `{"foo":"bar"}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:        "valid token",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			token:       "s3cr3t",
+			headers:     map[string]string{"Authorization": "Bearer s3cr3t"},
+			body:        `{"repository":"registry.example.com/app","tag":"v5"}`,
+			wantStatus:  http.StatusAccepted,
+			want:        []string{"default/app:v5"},
+		},
+		{
+			name:        "invalid token",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			token:       "s3cr3t",
+			headers:     map[string]string{"Authorization": "Bearer guess"},
+			body:        `{"repository":"registry.example.com/app","tag":"v6"}`,
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:        "missing token",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			token:       "s3cr3t",
+			body:        `{"repository":"registry.example.com/app","tag":"v7"}`,
+			wantStatus:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &refreshed{}
+			req := httptest.NewRequest(tt.method, "/events", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			newTestDispatcher(r).WithToken(tt.token).Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.want, r.keys)
+		})
+	}
+}
+
+func TestDispatcher_HandlerSharding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	// The shard group has two members, the request is received by kimup-b
+	kube := kfake.NewClientset()
+	a := sharding.New(kube, "kimup-operator", "kimup-a", sharding.WithRenewInterval(time.Hour))
+	require.NoError(t, a.Start(ctx))
+	b := sharding.New(kube, "kimup-operator", "kimup-b", sharding.WithRenewInterval(time.Hour))
+	require.NoError(t, b.Start(ctx))
+	require.Equal(t, []string{"kimup-a", "kimup-b"}, b.Members())
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+	images := []v1alpha1.Image{}
+	for i := 0; i < 10; i++ {
+		images = append(images, v1alpha1.Image{
+			ObjectMeta: metav1.ObjectMeta{Namespace: fmt.Sprintf("ns-%d", i), Name: "app"},
+			Spec:       v1alpha1.ImageSpec{Image: "registry.example.com/app"},
+		})
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range images {
+		builder = builder.WithObjects(&images[i])
+	}
+	c := builder.Build()
+
+	r := &refreshed{}
+	d := eventsource.NewDispatcher(func(_ context.Context) ([]v1alpha1.Image, error) {
+		return images, nil
+	}).WithTrigger(r.trigger).WithSharding(b, c)
+
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(`{"repository":"registry.example.com/app","tag":"v1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", traceparent)
+	rec := httptest.NewRecorder()
+	d.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	owned, routed := 0, 0
+	for _, image := range images {
+		key := image.Namespace + "/" + image.Name
+
+		var got v1alpha1.Image
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: image.Namespace, Name: image.Name}, &got))
+
+		if sharding.Owner(b.Members(), key) == "kimup-b" {
+			// The Images owned by the replica are refreshed with the pushed tag
+			owned++
+			assert.Contains(t, r.keys, key+":v1")
+			assert.Empty(t, got.Annotations[string(annotations.KeyAction)], key)
+		} else {
+			// The Images owned by the other replica are refreshed by their owner
+			routed++
+			assert.NotContains(t, r.keys, key+":v1")
+			assert.Equal(t, string(annotations.ActionRefresh), got.Annotations[string(annotations.KeyAction)], key)
+
+			// The pushed tag and the trace context are forwarded to the owner
+			an := annotations.New(ctx, &got)
+			assert.Equal(t, "v1", an.RefreshHint().Get().Tag, key)
+			assert.Equal(t, traceparent, an.RefreshHint().Get().TraceContext["traceparent"], key)
+		}
+	}
+	assert.Positive(t, owned)
+	assert.Positive(t, routed)
+	assert.Len(t, r.keys, owned)
+}
+
+// memoryBroker is an in-memory message broker.
+type memoryBroker struct {
+	handlers map[string]func([]byte)
+}
+
+func (b *memoryBroker) Subscribe(_ context.Context, subject string, handler func([]byte)) error {
+	b.handlers[subject] = handler
+	return nil
+}
+
+func (b *memoryBroker) publish(subject, payload string) {
+	if h, ok := b.handlers[subject]; ok {
+		h([]byte(payload))
+	}
+}
+
+func TestDispatcher_Subscribe(t *testing.T) {
+	r := &refreshed{}
+	b := &memoryBroker{handlers: map[string]func([]byte){}}
+
+	require.NoError(t, newTestDispatcher(r).Subscribe(context.Background(), b, "registry.events"))
+
+	b.publish("registry.events", `{"repository":"registry.example.com/app","tag":"v1"}`)
+	b.publish("registry.events", `invalid`)
+	b.publish("other", `{"repository":"registry.example.com/app","tag":"v2"}`)
+
+	assert.Equal(t, []string{"default/app:v1"}, r.keys)
+}
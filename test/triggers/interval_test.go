@@ -0,0 +1,44 @@
+package triggers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers/interval"
+)
+
+func TestInterval(t *testing.T) {
+	_, err := interval.New(10*time.Second, "default/demo")
+	require.Error(t, err)
+
+	trigger, err := interval.New(15*time.Minute, "default/demo")
+	require.NoError(t, err)
+
+	now := time.Now().UnixNano()
+	next, err := trigger.NextFireTime(now)
+	require.NoError(t, err)
+	assert.Greater(t, next, now)
+	assert.LessOrEqual(t, next, now+int64(15*time.Minute))
+
+	after, err := trigger.NextFireTime(next)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15*time.Minute), after-next)
+
+	// The phase is stable for the key and differs between the keys
+	same, err := interval.New(15*time.Minute, "default/demo")
+	require.NoError(t, err)
+	assert.Equal(t, trigger.Description(), same.Description())
+
+	phases := map[int64]bool{}
+	for _, key := range []string{"default/a", "default/b", "default/c", "default/d"} {
+		other, err := interval.New(15*time.Minute, key)
+		require.NoError(t, err)
+		n, err := other.NextFireTime(now)
+		require.NoError(t, err)
+		phases[n] = true
+	}
+	assert.Greater(t, len(phases), 1)
+}
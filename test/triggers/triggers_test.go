@@ -0,0 +1,52 @@
+package triggers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orange-cloudavenue/kube-image-updater/internal/triggers"
+)
+
+func TestTriggerDigest(t *testing.T) {
+	received := make(chan event.M, 1)
+	event.On(triggers.SendDigest.String(), event.ListenerFunc(func(e event.Event) error {
+		received <- e.Data()
+		return nil
+	}))
+
+	_, err := triggers.TriggerDigest("default", "demo")
+	require.NoError(t, err)
+
+	select {
+	case data := <-received:
+		assert.Equal(t, "default", data["namespace"])
+		assert.Equal(t, "demo", data["alertConfig"])
+		assert.NotContains(t, data, "image")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event was not received")
+	}
+}
+
+func TestTriggerSchedule(t *testing.T) {
+	received := make(chan event.M, 1)
+	event.On("test-schedule", event.ListenerFunc(func(e event.Event) error {
+		received <- e.Data()
+		return nil
+	}))
+
+	_, err := triggers.TriggerSchedule("test-schedule", "default", "demo", 1)
+	require.NoError(t, err)
+
+	select {
+	case data := <-received:
+		assert.Equal(t, "default", data["namespace"])
+		assert.Equal(t, "demo", data["image"])
+		assert.Equal(t, 1, data["trigger"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event was not received")
+	}
+}
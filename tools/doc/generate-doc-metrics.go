@@ -21,6 +21,7 @@ func generateDocMetrics() {
 			sSlice = append(sSlice, []string{fmt.Sprintf("--%s", models.MetricsFlagName), "false", "Enable metrics collection"})
 			sSlice = append(sSlice, []string{fmt.Sprintf("--%s", models.MetricsPortFlagName), models.MetricsDefaultAddr, "Port to expose metrics on"})
 			sSlice = append(sSlice, []string{fmt.Sprintf("--%s", models.MetricsPathFlagName), models.MetricsDefaultPath, "Path to expose metrics on"})
+			sSlice = append(sSlice, []string{fmt.Sprintf("--%s", models.MetricsLabelsFlagName), models.MetricsDefaultLabels, "Labels of the metrics of the images, the others are aggregated"})
 
 			prettyPrintedTable, err := markdown.NewTableFormatterBuilder().
 				WithPrettyPrint().